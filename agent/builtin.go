@@ -0,0 +1,239 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/casibase/casibase/util"
+)
+
+// BuiltinServerName is the server name of the builtin tools in the tool IDs, like the MCP server names
+const BuiltinServerName = "builtin"
+
+// KnowledgeSearcher searches the knowledge of a store for the "search_knowledge" builtin tool, it is given by the
+// object package, which owns the stores
+type KnowledgeSearcher func(ctx context.Context, storeName string, query string) (string, error)
+
+// BuiltinConfig is the config of a "Builtin" agent provider, from the text of the provider
+type BuiltinConfig struct {
+	Tools               []string `json:"tools"`
+	Stores              []string `json:"stores"`
+	DriverName          string   `json:"driverName"`
+	DataSourceName      string   `json:"dataSourceName"`
+	MaxRows             int      `json:"maxRows"`
+	AllowPrivateNetwork bool     `json:"allowPrivateNetwork"`
+}
+
+type builtinTool struct {
+	Tool    *protocol.Tool
+	Handler func(ctx context.Context, request *protocol.CallToolRequest) (string, error)
+}
+
+// BuiltinAgentProvider serves its tools in process: they are registered on an MCP server that talks to the client
+// through pipes, so that the tools are called by QueryTextWithTools() like the tools of an external MCP server
+type BuiltinAgentProvider struct {
+	Typ      string
+	SubType  string
+	Config   *BuiltinConfig
+	Tools    []*builtinTool
+	McpTools []*McpTools
+}
+
+func parseBuiltinConfig(text string) (*BuiltinConfig, error) {
+	config := &BuiltinConfig{}
+	if text != "" {
+		err := json.Unmarshal([]byte(text), config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the builtin agent config: %s", err.Error())
+		}
+	}
+
+	if config.MaxRows <= 0 {
+		config.MaxRows = 100
+	}
+	return config, nil
+}
+
+func NewBuiltinAgentProvider(typ string, subType string, text string, mcpTools []*McpTools, searcher KnowledgeSearcher) (*BuiltinAgentProvider, error) {
+	config, err := parseBuiltinConfig(text)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := getBuiltinTools(config, searcher)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &BuiltinAgentProvider{
+		Typ:      typ,
+		SubType:  subType,
+		Config:   config,
+		Tools:    tools,
+		McpTools: mcpTools,
+	}
+	return p, nil
+}
+
+// GetBuiltinToolsList returns the builtin tools of the config in the form of the MCP tools of a server
+func GetBuiltinToolsList(text string) ([]*McpTools, error) {
+	config, err := parseBuiltinConfig(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// The searcher is only called by the tool handlers, so a placeholder is enough to list the tool
+	searcher := func(ctx context.Context, storeName string, query string) (string, error) {
+		return "", nil
+	}
+	tools, err := getBuiltinTools(config, searcher)
+	if err != nil {
+		return nil, err
+	}
+
+	return getInProcessToolsList(BuiltinServerName, tools)
+}
+
+func getInProcessToolsList(serverName string, tools []*builtinTool) ([]*McpTools, error) {
+	toolsList := []*protocol.Tool{}
+	for _, tool := range tools {
+		toolsList = append(toolsList, tool.Tool)
+	}
+	toolsJson, err := json.Marshal(toolsList)
+	if err != nil {
+		return nil, err
+	}
+
+	mcpTools := &McpTools{
+		ServerName:  serverName,
+		Tools:       string(toolsJson),
+		IsEnabled:   true,
+		Status:      McpServerStatusHealthy,
+		CheckedTime: util.GetCurrentTime(),
+	}
+	return []*McpTools{mcpTools}, nil
+}
+
+// pipeReadCloser closes the other pipe with it, so that the server stops reading when the client is closed
+type pipeReadCloser struct {
+	*io.PipeReader
+	other *io.PipeWriter
+}
+
+func (r *pipeReadCloser) Close() error {
+	err := r.other.Close()
+	if err != nil {
+		return err
+	}
+	return r.PipeReader.Close()
+}
+
+func newBuiltinServer(tools []*builtinTool, in io.ReadCloser, out io.Writer) (*server.Server, error) {
+	s, err := server.NewServer(
+		transport.NewMockServerTransport(in, out),
+		server.WithServerInfo(protocol.Implementation{Name: "Casibase", Version: "1.0.0"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	registerBuiltinTools(s, tools)
+	return s, nil
+}
+
+func registerBuiltinTools(s *server.Server, tools []*builtinTool) {
+	for _, tool := range tools {
+		handler := tool.Handler
+		s.RegisterTool(tool.Tool, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+			text, err := handler(ctx, request)
+			if err != nil {
+				return protocol.NewCallToolResult([]protocol.Content{&protocol.TextContent{Type: "text", Text: err.Error()}}, true), nil
+			}
+			return protocol.NewCallToolResult([]protocol.Content{&protocol.TextContent{Type: "text", Text: text}}, false), nil
+		})
+	}
+}
+
+func newBuiltinClient(tools []*builtinTool) (*client.Client, error) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	s, err := newBuiltinServer(tools, serverReader, serverWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := s.Run()
+		if err != nil {
+			fmt.Printf("newBuiltinClient() error: %s\n", err.Error())
+		}
+		serverWriter.Close()
+	}()
+
+	cli, err := client.NewClient(transport.NewMockClientTransport(&pipeReadCloser{PipeReader: clientReader, other: clientWriter}, clientWriter))
+	if err != nil {
+		clientWriter.Close()
+		return nil, err
+	}
+	return cli, nil
+}
+
+func (p *BuiltinAgentProvider) GetAgentClients() (*AgentClients, error) {
+	return getInProcessAgentClients(BuiltinServerName, p.Tools, p.McpTools)
+}
+
+// getInProcessAgentClients serves the tools in process under the server name, the tools denied by the policies of the
+// MCP tools are not given to the model
+func getInProcessAgentClients(serverName string, builtinTools []*builtinTool, mcpTools []*McpTools) (*AgentClients, error) {
+	cli, err := newBuiltinClient(builtinTools)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := getToolPolicies(mcpTools)
+
+	tools := []*protocol.Tool{}
+	for _, tool := range builtinTools {
+		id := GetIdFromServerNameAndToolName(serverName, tool.Tool.Name)
+		if policies[id] == ToolPolicyDeny {
+			continue
+		}
+
+		tools = append(tools, &protocol.Tool{
+			Name:        id,
+			Description: tool.Tool.Description,
+			InputSchema: tool.Tool.InputSchema,
+		})
+	}
+
+	return &AgentClients{
+		Clients:  map[string]*client.Client{serverName: cli},
+		Tools:    tools,
+		Policies: policies,
+	}, nil
+}
+
+func isBuiltinToolEnabled(config *BuiltinConfig, name string) bool {
+	return len(config.Tools) == 0 || util.InSlice(config.Tools, name)
+}
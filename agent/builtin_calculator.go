@@ -0,0 +1,280 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var calculatorConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+var calculatorFunctions = map[string]func(args []float64) (float64, error){
+	"sqrt":  unaryFunction(math.Sqrt),
+	"abs":   unaryFunction(math.Abs),
+	"exp":   unaryFunction(math.Exp),
+	"ln":    unaryFunction(math.Log),
+	"log":   unaryFunction(math.Log10),
+	"log2":  unaryFunction(math.Log2),
+	"sin":   unaryFunction(math.Sin),
+	"cos":   unaryFunction(math.Cos),
+	"tan":   unaryFunction(math.Tan),
+	"floor": unaryFunction(math.Floor),
+	"ceil":  unaryFunction(math.Ceil),
+	"round": unaryFunction(math.Round),
+	"pow": func(args []float64) (float64, error) {
+		if len(args) != 2 {
+			return 0, fmt.Errorf("pow() takes 2 arguments")
+		}
+		return math.Pow(args[0], args[1]), nil
+	},
+	"min": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("min() takes at least 1 argument")
+		}
+		res := args[0]
+		for _, arg := range args[1:] {
+			res = math.Min(res, arg)
+		}
+		return res, nil
+	},
+	"max": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("max() takes at least 1 argument")
+		}
+		res := args[0]
+		for _, arg := range args[1:] {
+			res = math.Max(res, arg)
+		}
+		return res, nil
+	},
+}
+
+func unaryFunction(f func(float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("the function takes 1 argument")
+		}
+		return f(args[0]), nil
+	}
+}
+
+type calculatorParser struct {
+	text string
+	pos  int
+}
+
+func (p *calculatorParser) skipSpaces() {
+	for p.pos < len(p.text) && unicode.IsSpace(rune(p.text[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *calculatorParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+// evaluateExpression evaluates an arithmetic expression by recursive descent, only the numbers, the operators,
+// the parentheses and the whitelisted constants and functions are accepted, "^" is the right-associative power
+func evaluateExpression(expression string) (float64, error) {
+	p := &calculatorParser{text: expression}
+	res, err := p.parseExpression()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != 0 {
+		return 0, fmt.Errorf("unexpected character: %c at position %d", p.text[p.pos], p.pos)
+	}
+	if math.IsNaN(res) || math.IsInf(res, 0) {
+		return 0, fmt.Errorf("the result of the expression is not a finite number")
+	}
+	return res, nil
+}
+
+func (p *calculatorParser) parseExpression() (float64, error) {
+	res, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return res, nil
+		}
+		p.pos++
+
+		value, err := p.parseTerm()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			res += value
+		} else {
+			res -= value
+		}
+	}
+}
+
+func (p *calculatorParser) parseTerm() (float64, error) {
+	res, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return res, nil
+		}
+		p.pos++
+
+		value, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			res *= value
+		case '/':
+			if value == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			res /= value
+		case '%':
+			if value == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			res = math.Mod(res, value)
+		}
+	}
+}
+
+func (p *calculatorParser) parseUnary() (float64, error) {
+	switch p.peek() {
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	case '-':
+		p.pos++
+		value, err := p.parseUnary()
+		return -value, err
+	default:
+		return p.parsePower()
+	}
+}
+
+func (p *calculatorParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func (p *calculatorParser) parsePrimary() (float64, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		value, err := p.parseExpression()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.text) && (p.text[p.pos] == '.' || (p.text[p.pos] >= '0' && p.text[p.pos] <= '9')) {
+			p.pos++
+		}
+		// The exponent of the scientific notation, like 1.5e3
+		if p.pos < len(p.text) && (p.text[p.pos] == 'e' || p.text[p.pos] == 'E') && p.pos+1 < len(p.text) && (unicode.IsDigit(rune(p.text[p.pos+1])) || p.text[p.pos+1] == '-' || p.text[p.pos+1] == '+') {
+			p.pos += 2
+			for p.pos < len(p.text) && unicode.IsDigit(rune(p.text[p.pos])) {
+				p.pos++
+			}
+		}
+		return strconv.ParseFloat(p.text[start:p.pos], 64)
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.text) && (unicode.IsLetter(rune(p.text[p.pos])) || unicode.IsDigit(rune(p.text[p.pos]))) {
+			p.pos++
+		}
+		name := strings.ToLower(p.text[start:p.pos])
+
+		if p.peek() != '(' {
+			value, ok := calculatorConstants[name]
+			if !ok {
+				return 0, fmt.Errorf("unknown constant: %s", name)
+			}
+			return value, nil
+		}
+		p.pos++
+
+		function, ok := calculatorFunctions[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown function: %s", name)
+		}
+
+		args := []float64{}
+		for p.peek() != ')' {
+			value, err := p.parseExpression()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, value)
+
+			if p.peek() == ',' {
+				p.pos++
+			} else if p.peek() != ')' {
+				return 0, fmt.Errorf("missing closing parenthesis of function: %s", name)
+			}
+		}
+		p.pos++
+		return function(args)
+	case c == 0:
+		return 0, fmt.Errorf("unexpected end of the expression")
+	default:
+		return 0, fmt.Errorf("unexpected character: %c at position %d", c, p.pos)
+	}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"2 * 3 ^ 2", 18},
+		{"2 ^ 3 ^ 2", 512},
+		{"-2 ^ 2", -4},
+		{"10 % 4", 2},
+		{"sqrt(16) + abs(-3)", 7},
+		{"max(1, 5, 3) - min(4, 2)", 3},
+		{"pow(2, 10)", 1024},
+		{"1.5e3 / 3", 500},
+		{"round(pi * 100)", 314},
+	}
+
+	for _, test := range tests {
+		got, err := evaluateExpression(test.expression)
+		if err != nil {
+			t.Errorf("evaluateExpression(%q) error: %s", test.expression, err.Error())
+			continue
+		}
+		if got != test.want {
+			t.Errorf("evaluateExpression(%q) = %v, want %v", test.expression, got, test.want)
+		}
+	}
+
+	invalidExpressions := []string{"", "1 +", "(1 + 2", "1 / 0", "foo(1)", "x + 1", "1; 2", "sqrt(1, 2)"}
+	for _, expression := range invalidExpressions {
+		_, err := evaluateExpression(expression)
+		if err == nil {
+			t.Errorf("evaluateExpression(%q) should fail", expression)
+		}
+	}
+}
+
+func TestCheckReadOnlySql(t *testing.T) {
+	validQueries := []string{"SELECT * FROM user", "  with t as (select 1) select * from t;", "SHOW TABLES", "explain select 1"}
+	for _, query := range validQueries {
+		err := checkReadOnlySql(query)
+		if err != nil {
+			t.Errorf("checkReadOnlySql(%q) error: %s", query, err.Error())
+		}
+	}
+
+	invalidQueries := []string{
+		"DELETE FROM user", "select 1; drop table user", "UPDATE user SET name = 'a'", "selection",
+		"SELECT * FROM user INTO OUTFILE '/tmp/user.csv'", "select x into  dumpfile '/tmp/x' from t", "SELECT LOAD_FILE('/etc/passwd')",
+	}
+	for _, query := range invalidQueries {
+		err := checkReadOnlySql(query)
+		if err == nil {
+			t.Errorf("checkReadOnlySql(%q) should fail", query)
+		}
+	}
+}
+
+func TestBuiltinAgentClients(t *testing.T) {
+	p, err := NewBuiltinAgentProvider("Builtin", "Default", `{"tools": ["calculator", "current_time"]}`, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentClients, err := p.GetAgentClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agentClients.Clients[BuiltinServerName].Close()
+
+	if len(agentClients.Tools) != 2 || agentClients.Tools[0].Name != "builtin__calculator" {
+		t.Fatalf("unexpected tools: %v", agentClients.Tools)
+	}
+
+	request := protocol.NewCallToolRequest("calculator", map[string]interface{}{"expression": "(2 + 3) * 4"})
+	result, err := agentClients.Clients[BuiltinServerName].CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if text := result.Content[0].(*protocol.TextContent).Text; text != "(2 + 3) * 4 = 20" {
+		t.Errorf("got %q", text)
+	}
+
+	request = protocol.NewCallToolRequest("calculator", map[string]interface{}{"expression": "1 / 0"})
+	result, err = agentClients.Clients[BuiltinServerName].CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError {
+		t.Errorf("the division by zero should be an error result")
+	}
+}
+
+func TestFetchUrlPrivateNetwork(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer httpServer.Close()
+
+	tests := []struct {
+		config  string
+		want    string
+		isError bool
+	}{
+		{`{"tools": ["fetch_url"]}`, "private network", true},
+		{`{"tools": ["fetch_url"], "allowPrivateNetwork": true}`, "internal", false},
+	}
+	for _, test := range tests {
+		p, err := NewBuiltinAgentProvider("Builtin", "Default", test.config, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		agentClients, err := p.GetAgentClients()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request := protocol.NewCallToolRequest("fetch_url", map[string]interface{}{"url": httpServer.URL})
+		result, err := agentClients.Clients[BuiltinServerName].CallTool(context.Background(), request)
+		agentClients.Clients[BuiltinServerName].Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text := result.Content[0].(*protocol.TextContent).Text
+		if result.IsError != test.isError || !strings.Contains(text, test.want) {
+			t.Errorf("fetch_url with %s got %q (error: %v), want %q", test.config, text, result.IsError, test.want)
+		}
+	}
+}
+
+func TestIsPrivateIp(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", false},
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"169.254.169.254", true},
+		{"0.1.2.3", true},
+		{"100.100.100.200", true},
+		{"100.64.0.1", true},
+		{"100.128.0.1", false},
+		{"::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"64:ff9b::808:808", false},
+		{"64:ff9b:1::1", true},
+		{"2002:a00:1::1", true},
+		{"2002:6464:64c8::1", true},
+		{"2002:808:808::1", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, test := range tests {
+		if got := isPrivateIp(net.ParseIP(test.ip)); got != test.want {
+			t.Errorf("isPrivateIp(%s) = %v, want %v", test.ip, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/util"
+)
+
+const fetchUrlMaxBytes = 1 << 20
+
+const fetchUrlMaxChars = 20000
+
+type searchKnowledgeRequest struct {
+	Store string `json:"store,omitempty" description:"The name of the store to search, the first configured store by default"`
+	Query string `json:"query" description:"What to search for, in natural language"`
+}
+
+type fetchUrlRequest struct {
+	Url string `json:"url" description:"The http or https URL to fetch"`
+}
+
+type calculatorRequest struct {
+	Expression string `json:"expression" description:"The arithmetic expression, e.g. (2 + 3) * sqrt(16) / 2 ^ 3"`
+}
+
+type currentTimeRequest struct {
+	Timezone string `json:"timezone,omitempty" description:"The IANA timezone, e.g. Asia/Shanghai, UTC by default"`
+}
+
+type sqlQueryRequest struct {
+	Query string `json:"query" description:"A single read-only SQL statement: SELECT, WITH, SHOW, DESCRIBE or EXPLAIN"`
+}
+
+func getBuiltinTools(config *BuiltinConfig, searcher KnowledgeSearcher) ([]*builtinTool, error) {
+	res := []*builtinTool{}
+	addTool := func(name string, description string, request interface{}, handler func(ctx context.Context, request *protocol.CallToolRequest) (string, error)) error {
+		if !isBuiltinToolEnabled(config, name) {
+			return nil
+		}
+
+		tool, err := protocol.NewTool(name, description, request)
+		if err != nil {
+			return err
+		}
+		res = append(res, &builtinTool{Tool: tool, Handler: handler})
+		return nil
+	}
+
+	if searcher != nil && len(config.Stores) != 0 {
+		description := fmt.Sprintf("Search the knowledge base of a store and return the most relevant passages. The stores are: %s.", strings.Join(config.Stores, ", "))
+		err := addTool("search_knowledge", description, searchKnowledgeRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+			return searchKnowledge(ctx, config, searcher, request)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := addTool("fetch_url", "Fetch a web page or a text file by its URL and return its text.", fetchUrlRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+		return fetchUrl(ctx, config, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = addTool("calculator", "Evaluate an arithmetic expression with + - * / % ^, parentheses, pi, e and the functions sqrt, abs, pow, exp, ln, log, log2, sin, cos, tan, floor, ceil, round, min and max.", calculatorRequest{}, calculate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addTool("current_time", "Get the current date, time and weekday.", currentTimeRequest{}, getCurrentTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.DriverName != "" && config.DataSourceName != "" {
+		err = addTool("sql_query", fmt.Sprintf("Run a read-only SQL query on the %s database and return at most %d rows as JSON.", config.DriverName, config.MaxRows), sqlQueryRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+			return querySql(ctx, config, request)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func searchKnowledge(ctx context.Context, config *BuiltinConfig, searcher KnowledgeSearcher, request *protocol.CallToolRequest) (string, error) {
+	var req searchKnowledgeRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return "", err
+	}
+
+	if req.Store == "" {
+		req.Store = config.Stores[0]
+	}
+	if !util.InSlice(config.Stores, req.Store) {
+		return "", fmt.Errorf("the store: %s is not one of the stores: %s", req.Store, strings.Join(config.Stores, ", "))
+	}
+
+	return searcher(ctx, req.Store, req.Query)
+}
+
+func mustParseCidrs(cidrs ...string) []*net.IPNet {
+	res := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, ipNet)
+	}
+	return res
+}
+
+// privateIpNets are the internal ranges that the net.IP methods don't cover: "this network", the carrier-grade NAT
+// (where the metadata service of Alibaba Cloud is, 100.100.100.200) and the local-use NAT64 prefix
+var privateIpNets = mustParseCidrs("0.0.0.0/8", "100.64.0.0/10", "64:ff9b:1::/48")
+
+var (
+	nat64IpNet     = mustParseCidrs("64:ff9b::/96")[0]
+	sixToFourIpNet = mustParseCidrs("2002::/16")[0]
+)
+
+// getEmbeddedIpv4 returns the IPv4 address that a NAT64 or 6to4 address is translated to, or nil
+func getEmbeddedIpv4(ip net.IP) net.IP {
+	if ip.To4() != nil {
+		return nil
+	}
+
+	ip16 := ip.To16()
+	if nat64IpNet.Contains(ip16) {
+		return net.IPv4(ip16[12], ip16[13], ip16[14], ip16[15])
+	}
+	if sixToFourIpNet.Contains(ip16) {
+		return net.IPv4(ip16[2], ip16[3], ip16[4], ip16[5])
+	}
+	return nil
+}
+
+func isPrivateIp(ip net.IP) bool {
+	embeddedIp := getEmbeddedIpv4(ip)
+	if embeddedIp != nil {
+		return isPrivateIp(embeddedIp)
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, ipNet := range privateIpNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialPublicHost connects only to the public addresses of the host, so that the agent can't be used to reach the
+// internal network. The addresses are checked when connecting rather than before the request, so a host that
+// resolves to another address the second time (DNS rebinding) is still checked, and so are the redirects
+func dialPublicHost(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ipAddrs) == 0 {
+		return nil, fmt.Errorf("the host: %s has no addresses", host)
+	}
+	for _, ipAddr := range ipAddrs {
+		if isPrivateIp(ipAddr.IP) {
+			return nil, fmt.Errorf("the host: %s is in a private network", host)
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	for _, ipAddr := range ipAddrs {
+		var conn net.Conn
+		conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ipAddr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func newFetchUrlClient(config *BuiltinConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !config.AllowPrivateNetwork {
+		// A proxy would connect on behalf of the agent, bypassing the check of the addresses
+		transport.Proxy = nil
+		transport.DialContext = dialPublicHost
+	}
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(redirectRequest *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+}
+
+var htmlScriptRegex = regexp.MustCompile(`(?is)<(script|style|noscript)[^>]*>.*?</(script|style|noscript)>`)
+
+var htmlTagRegex = regexp.MustCompile(`(?s)<[^>]*>`)
+
+var blankLinesRegex = regexp.MustCompile(`\n\s*\n+`)
+
+func getHtmlText(html string) string {
+	text := htmlScriptRegex.ReplaceAllString(html, "")
+	text = htmlTagRegex.ReplaceAllString(text, "\n")
+	text = strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", "\"", "&#39;", "'").Replace(text)
+	text = blankLinesRegex.ReplaceAllString(text, "\n")
+	return strings.TrimSpace(text)
+}
+
+func fetchUrl(ctx context.Context, config *BuiltinConfig, request *protocol.CallToolRequest) (string, error) {
+	var req fetchUrlRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(req.Url)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("the URL scheme: %s is not supported", u.Scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := newFetchUrlClient(config).Do(httpRequest)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the URL: %s, status: %s", req.Url, resp.Status)
+	}
+
+	bytes, err := io.ReadAll(io.LimitReader(resp.Body, fetchUrlMaxBytes))
+	if err != nil {
+		return "", err
+	}
+
+	text := string(bytes)
+	if strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		text = getHtmlText(text)
+	}
+
+	runes := []rune(text)
+	if len(runes) > fetchUrlMaxChars {
+		text = string(runes[:fetchUrlMaxChars]) + "\n[truncated]"
+	}
+	return text, nil
+}
+
+func calculate(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+	var req calculatorRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := evaluateExpression(req.Expression)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", req.Expression, formatNumber(res)), nil
+}
+
+func getCurrentTime(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+	var req currentTimeRequest
+	if len(request.RawArguments) != 0 {
+		err := json.Unmarshal(request.RawArguments, &req)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	location := time.UTC
+	if req.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now().In(location)
+	return fmt.Sprintf("%s (%s, %s)", now.Format(time.RFC3339), now.Weekday(), location.String()), nil
+}
+
+var readOnlySqlRegex = regexp.MustCompile(`(?i)^\s*(select|with|show|describe|desc|explain)\b`)
+
+// sideEffectSqlRegex matches the parts of a SELECT that write or read files on the database server, which a
+// read-only transaction doesn't prevent
+var sideEffectSqlRegex = regexp.MustCompile(`(?i)\binto\s+(outfile|dumpfile)\b|\bload_file\s*\(|\bpg_read_file\s*\(|\bpg_read_binary_file\s*\(|\blo_import\s*\(|\blo_export\s*\(`)
+
+// checkReadOnlySql accepts a single statement of the read-only kinds without file access, the query also runs in
+// a read-only transaction, so this check is for a clear error rather than the only protection
+func checkReadOnlySql(query string) error {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSuffix(query, ";")
+	if strings.Contains(query, ";") {
+		return fmt.Errorf("only a single SQL statement is allowed")
+	}
+	if !readOnlySqlRegex.MatchString(query) {
+		return fmt.Errorf("only read-only SQL statements are allowed")
+	}
+	if sideEffectSqlRegex.MatchString(query) {
+		return fmt.Errorf("the SQL statements that access the files of the database server are not allowed")
+	}
+	return nil
+}
+
+func querySql(ctx context.Context, config *BuiltinConfig, request *protocol.CallToolRequest) (string, error) {
+	var req sqlQueryRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkReadOnlySql(req.Query)
+	if err != nil {
+		return "", err
+	}
+
+	db, err := sql.Open(config.DriverName, config.DataSourceName)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSuffix(strings.TrimSpace(req.Query), ";"))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	res := []map[string]interface{}{}
+	for rows.Next() && len(res) < config.MaxRows {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return "", err
+		}
+
+		row := map[string]interface{}{}
+		for i, column := range columns {
+			if bytes, ok := values[i].([]byte); ok {
+				row[column] = string(bytes)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	resBytes, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	return string(resBytes), nil
+}
@@ -46,9 +46,16 @@ func (p *McpAgentProvider) GetAgentClients() (*AgentClients, error) {
 	if err != nil {
 		return nil, err
 	}
+	pooledServers := map[string]bool{}
+	for name := range clients {
+		pooledServers[name] = true
+	}
+
+	policies := getToolPolicies(p.McpTools)
+
 	var tools []*protocol.Tool
 	for _, mcpTool := range p.McpTools {
-		if !mcpTool.IsEnabled {
+		if _, ok := clients[mcpTool.ServerName]; !ok || mcpTool.Tools == "" {
 			continue
 		}
 		toolsStr := mcpTool.Tools
@@ -58,11 +65,16 @@ func (p *McpAgentProvider) GetAgentClients() (*AgentClients, error) {
 		}
 		for _, tool := range toolsList {
 			tool.Name = GetIdFromServerNameAndToolName(mcpTool.ServerName, tool.Name)
+			if policies[tool.Name] == ToolPolicyDeny {
+				continue
+			}
+			tools = append(tools, tool)
 		}
-		tools = append(tools, toolsList...)
 	}
 	return &AgentClients{
-		Clients: clients,
-		Tools:   tools,
+		Clients:       clients,
+		Tools:         tools,
+		Policies:      policies,
+		pooledServers: pooledServers,
 	}, nil
 }
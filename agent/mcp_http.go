@@ -0,0 +1,82 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// headerTransport sets the configured headers of an MCP server on each request
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+func isStreamableHttpServer(srv ServerConfig) bool {
+	switch strings.ToLower(srv.Type) {
+	case "streamablehttp", "streamable-http", "streamable_http", "http":
+		return true
+	default:
+		return false
+	}
+}
+
+// getMcpHttpClient returns the HTTP client with the headers, the bearer token or the OAuth client credentials of
+// the server, the OAuth tokens are fetched and refreshed by the client itself
+func getMcpHttpClient(srv ServerConfig) (*http.Client, error) {
+	headers := map[string]string{}
+	for k, v := range srv.Headers {
+		headers[k] = v
+	}
+	if srv.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + srv.BearerToken
+	}
+
+	var base http.RoundTripper = http.DefaultTransport
+	if srv.OAuth != nil {
+		if srv.BearerToken != "" {
+			return nil, fmt.Errorf("the bearer token and the OAuth config can't be both set for the MCP server: %s", srv.URL)
+		}
+		if srv.OAuth.TokenUrl == "" || srv.OAuth.ClientId == "" {
+			return nil, fmt.Errorf("the token URL and the client ID of the OAuth config are required for the MCP server: %s", srv.URL)
+		}
+
+		config := &clientcredentials.Config{
+			ClientID:     srv.OAuth.ClientId,
+			ClientSecret: srv.OAuth.ClientSecret,
+			TokenURL:     srv.OAuth.TokenUrl,
+			Scopes:       srv.OAuth.Scopes,
+		}
+		base = config.Client(context.Background()).Transport
+	}
+
+	if len(headers) == 0 {
+		return &http.Client{Transport: base}, nil
+	}
+	return &http.Client{Transport: &headerTransport{headers: headers, base: base}}, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+const (
+	mcpClientIdleTimeout  = 10 * time.Minute
+	mcpClientPingInterval = 30 * time.Second
+)
+
+// pooledMcpClient is a client of the pool, it is only closed when no request uses it, a client removed from the pool
+// while in use is closed by its last release
+type pooledMcpClient struct {
+	client       *client.Client
+	lastUsedTime time.Time
+	useCount     int
+	isRemoved    bool
+}
+
+// mcpClientPool keeps the MCP clients across requests, it is keyed by the server name and config, so that a
+// changed config gets a new connection and the old one is closed when it becomes idle
+var (
+	mcpClientPool = map[string]*pooledMcpClient{}
+	// mcpClientsInUse finds the pooled client to release, including the ones already removed from the pool
+	mcpClientsInUse    = map[*client.Client]*pooledMcpClient{}
+	mcpClientPoolMutex sync.Mutex
+)
+
+func getMcpClientKey(name string, srv ServerConfig) string {
+	configBytes, _ := json.Marshal(srv)
+	hash := sha256.Sum256(append([]byte(name+"\n"), configBytes...))
+	return hex.EncodeToString(hash[:])
+}
+
+func (pooledClient *pooledMcpClient) acquire(now time.Time) {
+	pooledClient.useCount++
+	pooledClient.lastUsedTime = now
+	mcpClientsInUse[pooledClient.client] = pooledClient
+}
+
+// release ends a use of the client, it returns true if the client should be closed now
+func (pooledClient *pooledMcpClient) release(now time.Time) bool {
+	pooledClient.useCount--
+	pooledClient.lastUsedTime = now
+	if pooledClient.useCount > 0 {
+		return false
+	}
+
+	delete(mcpClientsInUse, pooledClient.client)
+	return pooledClient.isRemoved
+}
+
+// remove takes the client out of the pool, it returns true if the client should be closed now, a client in use is
+// closed by its last release instead
+func (pooledClient *pooledMcpClient) remove(key string) bool {
+	if mcpClientPool[key] == pooledClient {
+		delete(mcpClientPool, key)
+	}
+	pooledClient.isRemoved = true
+	return pooledClient.useCount == 0
+}
+
+// removeIdleMcpClients takes the idle clients out of the pool and returns them, the caller closes them after
+// unlocking mcpClientPoolMutex, as closing a client may wait for its server
+func removeIdleMcpClients(now time.Time) []*client.Client {
+	idleClients := []*client.Client{}
+	for key, pooledClient := range mcpClientPool {
+		if pooledClient.useCount == 0 && now.Sub(pooledClient.lastUsedTime) > mcpClientIdleTimeout {
+			pooledClient.remove(key)
+			idleClients = append(idleClients, pooledClient.client)
+		}
+	}
+	return idleClients
+}
+
+func pingMcpClient(cli *client.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := cli.Ping(ctx, protocol.NewPingRequest())
+	return err
+}
+
+// getPooledMcpClient returns the pooled client of the server or connects a new one, a client that has been idle
+// for a while is pinged first and replaced if the server doesn't answer. The client is in use until it is released
+// by releasePooledMcpClient()
+func getPooledMcpClient(name string, srv ServerConfig) (*client.Client, error) {
+	key := getMcpClientKey(name, srv)
+	now := time.Now()
+
+	mcpClientPoolMutex.Lock()
+	idleClients := removeIdleMcpClients(now)
+	pooledClient, ok := mcpClientPool[key]
+	var needsPing bool
+	if ok {
+		needsPing = now.Sub(pooledClient.lastUsedTime) > mcpClientPingInterval
+		pooledClient.acquire(now)
+	}
+	mcpClientPoolMutex.Unlock()
+
+	for _, idleClient := range idleClients {
+		idleClient.Close()
+	}
+
+	if ok {
+		if !needsPing {
+			return pooledClient.client, nil
+		}
+
+		err := pingMcpClient(pooledClient.client)
+		if err == nil {
+			return pooledClient.client, nil
+		}
+
+		mcpClientPoolMutex.Lock()
+		pooledClient.release(time.Now())
+		needsClose := pooledClient.remove(key)
+		mcpClientPoolMutex.Unlock()
+		if needsClose {
+			pooledClient.client.Close()
+		}
+	}
+
+	// The connection is made outside of the lock, as starting a stdio server or an HTTP session may take a while
+	cli, err := createMCPClient(srv)
+	if err != nil {
+		return nil, err
+	}
+
+	mcpClientPoolMutex.Lock()
+	existingClient, ok := mcpClientPool[key]
+	if ok {
+		existingClient.acquire(time.Now())
+	} else {
+		pooledClient = &pooledMcpClient{client: cli}
+		pooledClient.acquire(time.Now())
+		mcpClientPool[key] = pooledClient
+	}
+	mcpClientPoolMutex.Unlock()
+
+	// Another request has connected the server meanwhile, the new connection is not needed
+	if ok {
+		cli.Close()
+		return existingClient.client, nil
+	}
+	return cli, nil
+}
+
+// releasePooledMcpClient ends a use of the client returned by getPooledMcpClient()
+func releasePooledMcpClient(cli *client.Client) {
+	mcpClientPoolMutex.Lock()
+	pooledClient, ok := mcpClientsInUse[cli]
+	needsClose := ok && pooledClient.release(time.Now())
+	mcpClientPoolMutex.Unlock()
+
+	if needsClose {
+		cli.Close()
+	}
+}
+
+func removePooledMcpClient(name string, srv ServerConfig) {
+	key := getMcpClientKey(name, srv)
+
+	mcpClientPoolMutex.Lock()
+	pooledClient, ok := mcpClientPool[key]
+	needsClose := ok && pooledClient.remove(key)
+	mcpClientPoolMutex.Unlock()
+
+	if needsClose {
+		pooledClient.client.Close()
+	}
+}
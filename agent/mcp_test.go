@@ -0,0 +1,151 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+)
+
+func newTestStreamableHttpServer(t *testing.T) *httptest.Server {
+	serverTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := parseBuiltinConfig(`{"tools": ["calculator"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools, err := getBuiltinTools(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := server.NewServer(serverTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerBuiltinTools(s, tools)
+	go s.Run()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" || r.Header.Get("X-Tenant") != "casibase" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.HandleMCP().ServeHTTP(w, r)
+	}))
+}
+
+func TestStreamableHttpMcpServer(t *testing.T) {
+	httpServer := newTestStreamableHttpServer(t)
+	defer httpServer.Close()
+
+	config := fmt.Sprintf(`{"mcpServers": {"test": {"type": "streamableHttp", "url": "%s", "bearerToken": "test-token", "headers": {"X-Tenant": "casibase"}}, "wrong": {"type": "streamableHttp", "url": "%s"}}}`, httpServer.URL, httpServer.URL)
+	tools, err := GetToolsList(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("got %d servers, want 2", len(tools))
+	}
+
+	// The servers are sorted by name
+	if tools[0].ServerName != "test" || tools[0].Status != McpServerStatusHealthy || tools[0].Tools == "" {
+		t.Errorf("unexpected tools of the healthy server: %+v", tools[0])
+	}
+	if tools[1].ServerName != "wrong" || tools[1].Status != McpServerStatusUnhealthy || tools[1].Message == "" {
+		t.Errorf("unexpected tools of the unauthorized server: %+v", tools[1])
+	}
+
+	toolsMap := map[string]bool{"test": true}
+	clients, err := GetMCPClientMap(config, toolsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients2, err := GetMCPClientMap(config, toolsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clients["test"] != clients2["test"] {
+		t.Errorf("the client of the server should be reused across requests")
+	}
+
+	p, err := NewMcpAgentProvider("MCP", "Default", config, tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentClients, err := p.GetAgentClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agentClients.Tools) != 1 || agentClients.Tools[0].Name != "test__calculator" {
+		t.Errorf("only the tools of the connected server should be returned, got: %v", agentClients.Tools)
+	}
+	agentClients.Close()
+
+	clients3, err := GetMCPClientMap(config, toolsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clients3["test"] != clients["test"] {
+		t.Errorf("the pooled client should be kept open after the request")
+	}
+}
+
+func TestMcpClientPoolInUse(t *testing.T) {
+	httpServer := newTestStreamableHttpServer(t)
+	defer httpServer.Close()
+
+	srv := ServerConfig{Type: "streamableHttp", URL: httpServer.URL, BearerToken: "test-token", Headers: map[string]string{"X-Tenant": "casibase"}}
+	cli, err := getPooledMcpClient("in-use", srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := getMcpClientKey("in-use", srv)
+
+	// A long tool call doesn't refresh the last used time, but the client in use must stay open
+	mcpClientPoolMutex.Lock()
+	mcpClientPool[key].lastUsedTime = time.Now().Add(-2 * mcpClientIdleTimeout)
+	idleClients := removeIdleMcpClients(time.Now())
+	_, isPooled := mcpClientPool[key]
+	mcpClientPoolMutex.Unlock()
+	if !isPooled || len(idleClients) != 0 {
+		t.Fatal("removeIdleMcpClients() should keep the client in use")
+	}
+
+	// A removed client in use is only closed by its last release
+	removePooledMcpClient("in-use", srv)
+	_, err = cli.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools() of the removed client in use error: %v", err)
+	}
+	releasePooledMcpClient(cli)
+
+	mcpClientPoolMutex.Lock()
+	_, isInUse := mcpClientsInUse[cli]
+	mcpClientPoolMutex.Unlock()
+	if isInUse {
+		t.Fatal("releasePooledMcpClient() should end the use of the client")
+	}
+}
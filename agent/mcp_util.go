@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ThinkInAIXYZ/go-mcp/client"
 	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/casibase/casibase/util"
 )
 
 type ServerConfig struct {
@@ -18,52 +21,141 @@ type ServerConfig struct {
 	Args    []string          `json:"args"`
 	Env     map[string]string `json:"env"`
 
-	// SSE config
-	URL string `json:"url"`
+	// HTTP config, the type is "sse" (by default) or "streamableHttp"
+	Type        string            `json:"type"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	BearerToken string            `json:"bearerToken"`
+	OAuth       *OAuthConfig      `json:"oauth"`
+}
+
+// OAuthConfig is the OAuth client credentials grant of an HTTP MCP server
+type OAuthConfig struct {
+	TokenUrl     string   `json:"tokenUrl"`
+	ClientId     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	Scopes       []string `json:"scopes"`
 }
 
 type McpTools struct {
-	ServerName string `json:"serverName"`
-	Tools      string `json:"tools"`
-	IsEnabled  bool   `json:"isEnabled"`
+	ServerName   string            `json:"serverName"`
+	Tools        string            `json:"tools"`
+	IsEnabled    bool              `json:"isEnabled"`
+	ToolPolicies map[string]string `json:"toolPolicies"`
+	Status       string            `json:"status"`
+	Message      string            `json:"message"`
+	CheckedTime  string            `json:"checkedTime"`
+}
+
+const (
+	McpServerStatusHealthy   = "Healthy"
+	McpServerStatusUnhealthy = "Unhealthy"
+)
+
+// The policies of the tool calls, a tool without a policy is called automatically
+const (
+	ToolPolicyAuto     = "Auto"
+	ToolPolicyApproval = "Approval"
+	ToolPolicyDeny     = "Deny"
+)
+
+// getToolPolicies returns the policies of the tools of the servers, keyed by the tool IDs
+func getToolPolicies(mcpTools []*McpTools) map[string]string {
+	res := map[string]string{}
+	for _, serverTools := range mcpTools {
+		for toolName, policy := range serverTools.ToolPolicies {
+			res[GetIdFromServerNameAndToolName(serverTools.ServerName, toolName)] = policy
+		}
+	}
+	return res
 }
 
+func parseMcpServers(config string) (map[string]ServerConfig, error) {
+	var outer struct {
+		MCPServers map[string]ServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal([]byte(config), &outer); err != nil {
+		return nil, err
+	}
+	return outer.MCPServers, nil
+}
+
+// GetToolsList lists the tools of each MCP server with a new connection, a server that fails is returned with
+// the "Unhealthy" status and the error message instead of failing the others
 func GetToolsList(config string) ([]*McpTools, error) {
-	clients, err := GetMCPClientMap(config, nil)
+	servers, err := parseMcpServers(config)
 	if err != nil {
 		return nil, err
 	}
 
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var totalTools []*McpTools
-	for name, cli := range clients {
-		defer cli.Close()
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-		list, err := cli.ListTools(ctx)
-		if err != nil {
-			return nil, err
+	for _, name := range names {
+		mcpTools := &McpTools{
+			ServerName:  name,
+			IsEnabled:   true,
+			CheckedTime: util.GetCurrentTime(),
 		}
 
-		toolsJson, err := json.Marshal(list.Tools)
+		tools, err := listServerTools(name, servers[name])
 		if err != nil {
-			return nil, err
+			mcpTools.Status = McpServerStatusUnhealthy
+			mcpTools.Message = err.Error()
+		} else {
+			mcpTools.Tools = tools
+			mcpTools.Status = McpServerStatusHealthy
 		}
-		totalTools = append(totalTools, &McpTools{
-			ServerName: name,
-			Tools:      string(toolsJson),
-			IsEnabled:  true,
-		})
+		totalTools = append(totalTools, mcpTools)
 	}
 
 	return totalTools, nil
 }
 
+func listServerTools(name string, srv ServerConfig) (string, error) {
+	// The refresh always reconnects, so that a stale connection in the pool is replaced
+	removePooledMcpClient(name, srv)
+	cli, err := getPooledMcpClient(name, srv)
+	if err != nil {
+		return "", err
+	}
+	defer releasePooledMcpClient(cli)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	list, err := cli.ListTools(ctx)
+	if err != nil {
+		removePooledMcpClient(name, srv)
+		return "", err
+	}
+
+	toolsJson, err := json.Marshal(list.Tools)
+	if err != nil {
+		return "", err
+	}
+	return string(toolsJson), nil
+}
+
 func createMCPClient(srv ServerConfig) (*client.Client, error) {
 	var transportClient transport.ClientTransport
 	var err error
 
 	if srv.URL != "" {
-		transportClient, err = transport.NewSSEClientTransport(srv.URL)
+		var httpClient *http.Client
+		httpClient, err = getMcpHttpClient(srv)
+		if err != nil {
+			return nil, err
+		}
+
+		if isStreamableHttpServer(srv) {
+			transportClient, err = transport.NewStreamableHTTPClientTransport(srv.URL, transport.WithStreamableHTTPClientOptionHTTPClient(httpClient))
+		} else {
+			transportClient, err = transport.NewSSEClientTransport(srv.URL, transport.WithSSEClientOptionHTTPClient(httpClient))
+		}
 	} else {
 		envs := make([]string, 0, len(srv.Env))
 		for k, v := range srv.Env {
@@ -87,28 +179,27 @@ func createMCPClient(srv ServerConfig) (*client.Client, error) {
 	return cli, nil
 }
 
+// GetMCPClientMap returns the pooled clients of the enabled MCP servers, the clients are shared across requests, so
+// the callers release them by AgentClients.Close() instead of closing them, a server that can't be connected is
+// skipped so that the others still work
 func GetMCPClientMap(config string, toolsMap map[string]bool) (map[string]*client.Client, error) {
-	var outer struct {
-		MCPServers map[string]ServerConfig `json:"mcpServers"`
-	}
-	if err := json.Unmarshal([]byte(config), &outer); err != nil {
+	servers, err := parseMcpServers(config)
+	if err != nil {
 		return nil, err
 	}
 
 	clients := make(map[string]*client.Client)
-	for name, srv := range outer.MCPServers {
+	for name, srv := range servers {
 		if toolsMap != nil {
 			if enabled, exists := toolsMap[name]; !exists || !enabled {
 				continue
 			}
 		}
 
-		cli, err := createMCPClient(srv)
+		cli, err := getPooledMcpClient(name, srv)
 		if err != nil {
-			for _, c := range clients {
-				c.Close()
-			}
-			return nil, err
+			fmt.Printf("GetMCPClientMap() error, failed to connect to the MCP server: %s, %s\n", name, err.Error())
+			continue
 		}
 		clients[name] = cli
 	}
@@ -0,0 +1,794 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"sigs.k8s.io/yaml"
+)
+
+// OpenApiServerName is the server name of the tools of the spec operations in the tool IDs
+const OpenApiServerName = "openapi"
+
+const (
+	openApiMaxResponseBytes = 1 << 20
+	openApiMaxResponseChars = 20000
+	openApiMaxSchemaDepth   = 8
+	openApiMaxToolNameLen   = 48
+	openApiSpecTimeout      = 10 * time.Second
+)
+
+// openApiSpecClient fetches the specs, its timeout bounds how long a slow spec host can hold back a refresh
+var openApiSpecClient = &http.Client{Timeout: openApiSpecTimeout}
+
+// openApiCallClient calls the operations, it doesn't follow a redirect to another host, as the auth of the provider
+// is set in the headers and would be sent to that host too
+var openApiCallClient = &http.Client{CheckRedirect: checkOpenApiRedirect}
+
+func checkOpenApiRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("the redirect from host: %s to another host: %s is refused", via[0].URL.Host, req.URL.Host)
+	}
+	return nil
+}
+
+// openApiSpecs caches the fetched specs by their URLs. A spec is fetched when the tools of its provider are
+// refreshed, so that building the provider for each answer never waits for the spec host
+var (
+	openApiSpecs     = map[string]map[string]interface{}{}
+	openApiSpecMutex sync.RWMutex
+)
+
+var openApiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var openApiToolNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// openApiToolNameUnderscoreRegex matches the runs of "_", a "__" in a tool name would break the "<server>__<tool>" ID
+var openApiToolNameUnderscoreRegex = regexp.MustCompile(`_{2,}`)
+
+// OpenApiAuth is how the calls of an "OpenAPI" agent provider are authenticated, the type is one of "None",
+// "Bearer", "Basic" and "ApiKey"
+type OpenApiAuth struct {
+	Type     string `json:"type"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+}
+
+// OpenApiConfig is the config of an "OpenAPI" agent provider, from the text of the provider. The spec is given
+// by its URL or pasted as JSON or YAML, the operations are the selected operation IDs, all operations if empty
+type OpenApiConfig struct {
+	SpecUrl    string            `json:"specUrl"`
+	Spec       string            `json:"spec"`
+	BaseUrl    string            `json:"baseUrl"`
+	Operations []string          `json:"operations"`
+	Headers    map[string]string `json:"headers"`
+	Auth       *OpenApiAuth      `json:"auth"`
+	Timeout    int               `json:"timeout"`
+}
+
+type openApiParameter struct {
+	Name     string
+	In       string
+	Required bool
+}
+
+type openApiOperation struct {
+	Method          string
+	Path            string
+	Parameters      []*openApiParameter
+	HasBody         bool
+	BodyContentType string
+	Tool            *protocol.Tool
+}
+
+// OpenApiAgentProvider turns the operations of an OpenAPI spec into tools, which are served in process like the
+// builtin tools and call the API over HTTP
+type OpenApiAgentProvider struct {
+	Typ        string
+	SubType    string
+	Config     *OpenApiConfig
+	BaseUrl    string
+	Operations []*openApiOperation
+	Tools      []*builtinTool
+	McpTools   []*McpTools
+}
+
+func parseOpenApiConfig(text string) (*OpenApiConfig, error) {
+	config := &OpenApiConfig{}
+	if text != "" {
+		err := json.Unmarshal([]byte(text), config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the OpenAPI agent config: %s", err.Error())
+		}
+	}
+
+	if config.Spec == "" && config.SpecUrl == "" {
+		return nil, fmt.Errorf("the OpenAPI agent config should have a spec or a spec URL")
+	}
+	if config.Auth == nil {
+		config.Auth = &OpenApiAuth{Type: "None"}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30
+	}
+	return config, nil
+}
+
+func NewOpenApiAgentProvider(typ string, subType string, text string, mcpTools []*McpTools) (*OpenApiAgentProvider, error) {
+	p, err := newOpenApiAgentProvider(text, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Typ = typ
+	p.SubType = subType
+	p.McpTools = mcpTools
+	return p, nil
+}
+
+// newOpenApiAgentProvider builds the provider from the config, the spec of a spec URL is fetched again if
+// isRefreshed is true, otherwise it comes from the cache and is only fetched if it's not cached yet
+func newOpenApiAgentProvider(text string, isRefreshed bool) (*OpenApiAgentProvider, error) {
+	config, err := parseOpenApiConfig(text)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := getOpenApiSpec(config, isRefreshed)
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl, err := getOpenApiBaseUrl(config, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := getOpenApiOperations(config, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &OpenApiAgentProvider{
+		Config:     config,
+		BaseUrl:    baseUrl,
+		Operations: operations,
+	}
+	for _, operation := range operations {
+		operation := operation
+		p.Tools = append(p.Tools, &builtinTool{
+			Tool: operation.Tool,
+			Handler: func(ctx context.Context, request *protocol.CallToolRequest) (string, error) {
+				return p.callOperation(ctx, operation, request.Arguments)
+			},
+		})
+	}
+	return p, nil
+}
+
+// GetOpenApiToolsList returns the tools of the selected operations of the spec in the form of the MCP tools of a server,
+// the spec of a spec URL is fetched again and cached for the later answers
+func GetOpenApiToolsList(text string) ([]*McpTools, error) {
+	p, err := newOpenApiAgentProvider(text, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return getInProcessToolsList(OpenApiServerName, p.Tools)
+}
+
+func (p *OpenApiAgentProvider) GetAgentClients() (*AgentClients, error) {
+	return getInProcessAgentClients(OpenApiServerName, p.Tools, p.McpTools)
+}
+
+func getOpenApiSpec(config *OpenApiConfig, isRefreshed bool) (map[string]interface{}, error) {
+	if config.Spec != "" {
+		return parseOpenApiSpec(config.Spec)
+	}
+
+	if !isRefreshed {
+		openApiSpecMutex.RLock()
+		spec, ok := openApiSpecs[config.SpecUrl]
+		openApiSpecMutex.RUnlock()
+		if ok {
+			return spec, nil
+		}
+	}
+
+	specText, err := fetchOpenApiSpec(config.SpecUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parseOpenApiSpec(specText)
+	if err != nil {
+		return nil, err
+	}
+
+	openApiSpecMutex.Lock()
+	openApiSpecs[config.SpecUrl] = spec
+	openApiSpecMutex.Unlock()
+	return spec, nil
+}
+
+func fetchOpenApiSpec(specUrl string) (string, error) {
+	resp, err := openApiSpecClient.Get(specUrl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the OpenAPI spec: %s, status: %s", specUrl, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*openApiMaxResponseBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func parseOpenApiSpec(specText string) (map[string]interface{}, error) {
+	// YAML is a superset of JSON, so both forms of the spec are converted to JSON
+	data, err := yaml.YAMLToJSON([]byte(specText))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI spec: %s", err.Error())
+	}
+
+	spec := map[string]interface{}{}
+	err = json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI spec: %s", err.Error())
+	}
+
+	if _, ok := spec["paths"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("the OpenAPI spec has no paths")
+	}
+	return spec, nil
+}
+
+func getOpenApiBaseUrl(config *OpenApiConfig, spec map[string]interface{}) (string, error) {
+	baseUrl := config.BaseUrl
+	if baseUrl == "" {
+		if servers, ok := spec["servers"].([]interface{}); ok && len(servers) > 0 {
+			server := getJsonMap(servers[0])
+			baseUrl = getJsonString(server, "url")
+			for name, variable := range getJsonMap(server["variables"]) {
+				baseUrl = strings.ReplaceAll(baseUrl, "{"+name+"}", getJsonString(getJsonMap(variable), "default"))
+			}
+		} else if host := getJsonString(spec, "host"); host != "" {
+			// Swagger 2.0
+			scheme := "https"
+			if schemes, ok := spec["schemes"].([]interface{}); ok && len(schemes) > 0 {
+				scheme = fmt.Sprint(schemes[0])
+			}
+			baseUrl = fmt.Sprintf("%s://%s%s", scheme, host, getJsonString(spec, "basePath"))
+		} else {
+			baseUrl = getJsonString(spec, "basePath")
+		}
+	}
+
+	// A relative server URL is relative to the URL of the spec
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() {
+		if config.SpecUrl == "" {
+			return "", fmt.Errorf("the OpenAPI spec has no absolute server URL, please set the base URL")
+		}
+
+		specUrl, err := url.Parse(config.SpecUrl)
+		if err != nil {
+			return "", err
+		}
+		u = specUrl.ResolveReference(u)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+func getOpenApiOperations(config *OpenApiConfig, spec map[string]interface{}) ([]*openApiOperation, error) {
+	paths := getJsonMap(spec["paths"])
+	pathNames := []string{}
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	operations := []*openApiOperation{}
+	toolNames := map[string]bool{}
+	for _, path := range pathNames {
+		pathItem := resolveOpenApiRef(spec, getJsonMap(paths[path]), 0)
+		for _, method := range openApiMethods {
+			operationObject, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			operationId := getJsonString(operationObject, "operationId")
+			if !isOpenApiOperationSelected(config, operationId, method, path) {
+				continue
+			}
+
+			name := getOpenApiToolName(operationId, method, path)
+			for i := 2; toolNames[name]; i++ {
+				name = fmt.Sprintf("%s_%d", getOpenApiToolName(operationId, method, path), i)
+			}
+			toolNames[name] = true
+
+			operation := getOpenApiOperation(spec, pathItem, operationObject, method, path)
+			operation.Tool.Name = name
+			operations = append(operations, operation)
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no operation of the OpenAPI spec is selected")
+	}
+	return operations, nil
+}
+
+func isOpenApiOperationSelected(config *OpenApiConfig, operationId string, method string, path string) bool {
+	if len(config.Operations) == 0 {
+		return true
+	}
+
+	for _, operation := range config.Operations {
+		if operation == operationId || strings.EqualFold(operation, fmt.Sprintf("%s %s", method, path)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getOpenApiToolName(operationId string, method string, path string) string {
+	name := operationId
+	if name == "" {
+		name = method + "_" + path
+	}
+
+	name = openApiToolNameRegex.ReplaceAllString(name, "_")
+	name = strings.Trim(openApiToolNameUnderscoreRegex.ReplaceAllString(name, "_"), "_")
+	if len(name) > openApiMaxToolNameLen {
+		name = strings.TrimRight(name[:openApiMaxToolNameLen], "_")
+	}
+	return name
+}
+
+func getOpenApiOperation(spec map[string]interface{}, pathItem map[string]interface{}, operationObject map[string]interface{}, method string, path string) *openApiOperation {
+	operation := &openApiOperation{
+		Method: strings.ToUpper(method),
+		Path:   path,
+	}
+
+	inputSchema := protocol.InputSchema{
+		Type:       protocol.Object,
+		Properties: map[string]*protocol.Property{},
+	}
+
+	// The parameters of the operation override the parameters of the path with the same name and location
+	parameterObjects := map[string]map[string]interface{}{}
+	parameterKeys := []string{}
+	for _, list := range []interface{}{pathItem["parameters"], operationObject["parameters"]} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			parameterObject := resolveOpenApiRef(spec, getJsonMap(item), 0)
+			key := getJsonString(parameterObject, "in") + ":" + getJsonString(parameterObject, "name")
+			if _, ok := parameterObjects[key]; !ok {
+				parameterKeys = append(parameterKeys, key)
+			}
+			parameterObjects[key] = parameterObject
+		}
+	}
+
+	for _, key := range parameterKeys {
+		parameterObject := parameterObjects[key]
+		name := getJsonString(parameterObject, "name")
+		in := getJsonString(parameterObject, "in")
+		required := in == "path" || parameterObject["required"] == true
+
+		if in == "body" {
+			// Swagger 2.0
+			operation.HasBody = true
+			operation.BodyContentType = "application/json"
+			property := openApiSchemaToProperty(spec, getJsonMap(parameterObject["schema"]), 0)
+			setPropertyDescription(property, getJsonString(parameterObject, "description"))
+			inputSchema.Properties["body"] = property
+			if required {
+				inputSchema.Required = append(inputSchema.Required, "body")
+			}
+			continue
+		}
+		if in != "path" && in != "query" && in != "header" && in != "cookie" {
+			continue
+		}
+
+		// The schema of the parameter is in the "schema" field since OpenAPI 3.0, and in the parameter itself before
+		schema := getJsonMap(parameterObject["schema"])
+		if len(schema) == 0 {
+			schema = parameterObject
+		}
+		property := openApiSchemaToProperty(spec, schema, 0)
+		setPropertyDescription(property, getJsonString(parameterObject, "description"))
+
+		operation.Parameters = append(operation.Parameters, &openApiParameter{Name: name, In: in, Required: required})
+		inputSchema.Properties[name] = property
+		if required {
+			inputSchema.Required = append(inputSchema.Required, name)
+		}
+	}
+
+	requestBody := resolveOpenApiRef(spec, getJsonMap(operationObject["requestBody"]), 0)
+	if content := getJsonMap(requestBody["content"]); len(content) > 0 {
+		contentType := getOpenApiContentType(content)
+		operation.HasBody = true
+		operation.BodyContentType = contentType
+		property := openApiSchemaToProperty(spec, getJsonMap(getJsonMap(content[contentType])["schema"]), 0)
+		setPropertyDescription(property, getJsonString(requestBody, "description"))
+		inputSchema.Properties["body"] = property
+		if requestBody["required"] == true {
+			inputSchema.Required = append(inputSchema.Required, "body")
+		}
+	}
+
+	description := getJsonString(operationObject, "summary")
+	if text := getJsonString(operationObject, "description"); text != "" && text != description {
+		description = strings.TrimSpace(description + "\n" + text)
+	}
+	if description == "" {
+		description = fmt.Sprintf("%s %s", operation.Method, path)
+	}
+
+	operation.Tool = &protocol.Tool{
+		Description: description,
+		InputSchema: inputSchema,
+	}
+	return operation
+}
+
+// getOpenApiContentType prefers JSON for the request body, then form data, then the first content type
+func getOpenApiContentType(content map[string]interface{}) string {
+	contentTypes := []string{}
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	for _, contentType := range contentTypes {
+		if strings.Contains(contentType, "json") {
+			return contentType
+		}
+	}
+	for _, contentType := range contentTypes {
+		if contentType == "application/x-www-form-urlencoded" {
+			return contentType
+		}
+	}
+	return contentTypes[0]
+}
+
+// resolveOpenApiRef follows the local "$ref" of the object, like "#/components/schemas/Pet"
+func resolveOpenApiRef(spec map[string]interface{}, object map[string]interface{}, depth int) map[string]interface{} {
+	ref := getJsonString(object, "$ref")
+	if ref == "" || depth > openApiMaxSchemaDepth {
+		return object
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return map[string]interface{}{}
+	}
+
+	var node interface{} = spec
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		node = getJsonMap(node)[token]
+	}
+	return resolveOpenApiRef(spec, getJsonMap(node), depth+1)
+}
+
+func openApiSchemaToProperty(spec map[string]interface{}, schema map[string]interface{}, depth int) *protocol.Property {
+	if depth > openApiMaxSchemaDepth {
+		return &protocol.Property{Type: protocol.ObjectT}
+	}
+	schema = resolveOpenApiRef(spec, schema, 0)
+
+	// The subschemas of "allOf" are merged, only the first subschema of "oneOf" and "anyOf" is used
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		property := &protocol.Property{Type: protocol.ObjectT, Properties: map[string]*protocol.Property{}}
+		for _, item := range allOf {
+			subProperty := openApiSchemaToProperty(spec, getJsonMap(item), depth+1)
+			for name, p := range subProperty.Properties {
+				property.Properties[name] = p
+			}
+			property.Required = append(property.Required, subProperty.Required...)
+			setPropertyDescription(property, subProperty.Description)
+		}
+		setPropertyDescription(property, getJsonString(schema, "description"))
+		return property
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if items, ok := schema[key].([]interface{}); ok && len(items) > 0 {
+			property := openApiSchemaToProperty(spec, getJsonMap(items[0]), depth+1)
+			setPropertyDescription(property, getJsonString(schema, "description"))
+			return property
+		}
+	}
+
+	property := &protocol.Property{
+		Type:        getOpenApiSchemaType(schema),
+		Description: getJsonString(schema, "description"),
+	}
+	if format := getJsonString(schema, "format"); format != "" {
+		setPropertyDescription(property, fmt.Sprintf("Format: %s", format))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, value := range enum {
+			if value != nil {
+				property.Enum = append(property.Enum, fmt.Sprint(value))
+			}
+		}
+	}
+
+	switch property.Type {
+	case protocol.Array:
+		property.Items = openApiSchemaToProperty(spec, getJsonMap(schema["items"]), depth+1)
+	case protocol.ObjectT:
+		properties := getJsonMap(schema["properties"])
+		if len(properties) > 0 {
+			property.Properties = map[string]*protocol.Property{}
+			for name, item := range properties {
+				property.Properties[name] = openApiSchemaToProperty(spec, getJsonMap(item), depth+1)
+			}
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				property.Required = append(property.Required, fmt.Sprint(name))
+			}
+		}
+	}
+	return property
+}
+
+func getOpenApiSchemaType(schema map[string]interface{}) protocol.DataType {
+	typ := ""
+	switch value := schema["type"].(type) {
+	case string:
+		typ = value
+	case []interface{}:
+		// OpenAPI 3.1 allows a list of types like ["string", "null"]
+		for _, item := range value {
+			if item != "null" {
+				typ = fmt.Sprint(item)
+				break
+			}
+		}
+	}
+
+	switch typ {
+	case "object":
+		return protocol.ObjectT
+	case "array":
+		return protocol.Array
+	case "integer":
+		return protocol.Integer
+	case "number":
+		return protocol.Number
+	case "boolean":
+		return protocol.Boolean
+	case "string":
+		return protocol.String
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return protocol.ObjectT
+	}
+	if _, ok := schema["items"]; ok {
+		return protocol.Array
+	}
+	return protocol.String
+}
+
+func setPropertyDescription(property *protocol.Property, description string) {
+	if description == "" || strings.Contains(property.Description, description) {
+		return
+	}
+
+	if property.Description == "" {
+		property.Description = description
+	} else {
+		property.Description = property.Description + "\n" + description
+	}
+}
+
+func (p *OpenApiAgentProvider) callOperation(ctx context.Context, operation *openApiOperation, arguments map[string]interface{}) (string, error) {
+	path := operation.Path
+	query := url.Values{}
+	header := http.Header{}
+	cookies := []string{}
+	for _, parameter := range operation.Parameters {
+		value, ok := arguments[parameter.Name]
+		if !ok || value == nil {
+			if parameter.Required {
+				return "", fmt.Errorf("the parameter: %s is required", parameter.Name)
+			}
+			continue
+		}
+
+		switch parameter.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+parameter.Name+"}", url.PathEscape(getOpenApiParameterValue(value)))
+		case "query":
+			if values, ok := value.([]interface{}); ok {
+				for _, item := range values {
+					query.Add(parameter.Name, getOpenApiParameterValue(item))
+				}
+			} else {
+				query.Set(parameter.Name, getOpenApiParameterValue(value))
+			}
+		case "header":
+			header.Set(parameter.Name, getOpenApiParameterValue(value))
+		case "cookie":
+			cookies = append(cookies, fmt.Sprintf("%s=%s", parameter.Name, url.QueryEscape(getOpenApiParameterValue(value))))
+		}
+	}
+
+	var body io.Reader
+	if operation.HasBody {
+		if value, ok := arguments["body"]; ok && value != nil {
+			data, err := getOpenApiBody(operation.BodyContentType, value)
+			if err != nil {
+				return "", err
+			}
+			body = bytes.NewReader(data)
+			header.Set("Content-Type", operation.BodyContentType)
+		}
+	}
+
+	auth := p.Config.Auth
+	if auth.Type == "ApiKey" && auth.In == "query" {
+		query.Set(auth.Name, auth.Token)
+	}
+
+	u := p.BaseUrl + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.Config.Timeout)*time.Second)
+	defer cancel()
+
+	httpRequest, err := http.NewRequestWithContext(ctx, operation.Method, u, body)
+	if err != nil {
+		return "", err
+	}
+
+	for name, value := range p.Config.Headers {
+		httpRequest.Header.Set(name, value)
+	}
+	for name, values := range header {
+		httpRequest.Header[name] = values
+	}
+	if len(cookies) > 0 {
+		httpRequest.Header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	if httpRequest.Header.Get("Accept") == "" {
+		httpRequest.Header.Set("Accept", "application/json")
+	}
+
+	switch auth.Type {
+	case "Bearer":
+		httpRequest.Header.Set("Authorization", "Bearer "+auth.Token)
+	case "Basic":
+		httpRequest.SetBasicAuth(auth.Username, auth.Password)
+	case "ApiKey":
+		if auth.In != "query" {
+			httpRequest.Header.Set(auth.Name, auth.Token)
+		}
+	}
+
+	resp, err := openApiCallClient.Do(httpRequest)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, openApiMaxResponseBytes))
+	if err != nil {
+		return "", err
+	}
+
+	text := string(data)
+	runes := []rune(text)
+	if len(runes) > openApiMaxResponseChars {
+		text = string(runes[:openApiMaxResponseChars]) + "\n[truncated]"
+	}
+
+	// The model is given the response of the failed calls too, so that it can correct the arguments
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("%s %s failed, status: %s\n%s", operation.Method, operation.Path, resp.Status, text)
+	}
+	return fmt.Sprintf("Status: %s\n%s", resp.Status, text), nil
+}
+
+func getOpenApiParameterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			values = append(values, getOpenApiParameterValue(item))
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func getOpenApiBody(contentType string, value interface{}) ([]byte, error) {
+	if contentType == "application/x-www-form-urlencoded" {
+		values := url.Values{}
+		for name, item := range getJsonMap(value) {
+			values.Set(name, getOpenApiParameterValue(item))
+		}
+		return []byte(values.Encode()), nil
+	}
+
+	if text, ok := value.(string); ok && !strings.Contains(contentType, "json") {
+		return []byte(text), nil
+	}
+	return json.Marshal(value)
+}
+
+func getJsonMap(value interface{}) map[string]interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func getJsonString(m map[string]interface{}, key string) string {
+	s, ok := m[key].(string)
+	if !ok {
+		return ""
+	}
+	return s
+}
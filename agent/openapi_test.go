@@ -0,0 +1,284 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+const testOpenApiSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      summary: Get a pet by its ID
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+    delete:
+      operationId: deletePet
+  /pets:
+    post:
+      operationId: create pet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          enum: [cat, dog]
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestOpenApiTools(t *testing.T) {
+	config := fmt.Sprintf(`{"spec": %q, "baseUrl": "http://localhost/api/", "operations": ["getPet", "create pet"]}`, testOpenApiSpec)
+	tools, err := GetOpenApiToolsList(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 1 || tools[0].ServerName != OpenApiServerName || tools[0].Status != McpServerStatusHealthy {
+		t.Fatalf("unexpected tools: %v", tools)
+	}
+
+	toolsList := []*protocol.Tool{}
+	err = json.Unmarshal([]byte(tools[0].Tools), &toolsList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The paths are sorted, and the names of the tools are sanitized from the operation IDs
+	if len(toolsList) != 2 || toolsList[0].Name != "create_pet" || toolsList[1].Name != "getPet" {
+		t.Fatalf("unexpected tools: %v", toolsList)
+	}
+
+	body := toolsList[0].InputSchema.Properties["body"]
+	if body == nil || body.Type != protocol.ObjectT || body.Properties["kind"].Enum[1] != "dog" || body.Required[0] != "name" {
+		t.Errorf("unexpected body schema: %+v", body)
+	}
+	if len(toolsList[0].InputSchema.Required) != 1 || toolsList[0].InputSchema.Required[0] != "body" {
+		t.Errorf("unexpected required parameters: %v", toolsList[0].InputSchema.Required)
+	}
+
+	getPetSchema := toolsList[1].InputSchema
+	if getPetSchema.Properties["petId"].Type != protocol.Integer || getPetSchema.Properties["fields"].Items.Type != protocol.String {
+		t.Errorf("unexpected parameters: %+v", getPetSchema.Properties)
+	}
+}
+
+func TestOpenApiAgentClients(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method == http.MethodGet && r.URL.Path == "/api/pets/7" {
+			fmt.Fprintf(w, `{"id": 7, "fields": %q}`, strings.Join(r.URL.Query()["fields"], ","))
+			return
+		}
+		if r.Method == http.MethodPost && r.URL.Path == "/api/pets" {
+			data, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer httpServer.Close()
+
+	config := fmt.Sprintf(`{"spec": %q, "baseUrl": "%s/api", "auth": {"type": "ApiKey", "name": "X-Api-Key", "in": "header", "token": "test-key"}}`, testOpenApiSpec, httpServer.URL)
+	p, err := NewOpenApiAgentProvider("OpenAPI", "Default", config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentClients, err := p.GetAgentClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agentClients.Clients[OpenApiServerName].Close()
+
+	if len(agentClients.Tools) != 3 || agentClients.Tools[2].Name != "openapi__deletePet" {
+		t.Fatalf("unexpected tools: %v", agentClients.Tools)
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		want      string
+		isError   bool
+	}{
+		{"getPet", map[string]interface{}{"petId": 7, "fields": []interface{}{"name", "kind"}}, `{"id": 7, "fields": "name,kind"}`, false},
+		{"create_pet", map[string]interface{}{"body": map[string]interface{}{"name": "Tom"}}, `{"name":"Tom"}`, false},
+		{"deletePet", map[string]interface{}{"petId": 8}, "404 Not Found", true},
+		{"getPet", map[string]interface{}{}, "the parameter: petId is required", true},
+	}
+	for _, test := range tests {
+		request := protocol.NewCallToolRequest(test.name, test.arguments)
+		result, err := agentClients.Clients[OpenApiServerName].CallTool(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text := result.Content[0].(*protocol.TextContent).Text
+		if result.IsError != test.isError || !strings.Contains(text, test.want) {
+			t.Errorf("%s(%v) got %q (error: %v), want %q", test.name, test.arguments, text, result.IsError, test.want)
+		}
+	}
+}
+
+func TestOpenApiSpecCache(t *testing.T) {
+	fetchCount := 0
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetchCount++
+		w.Write([]byte(testOpenApiSpec))
+	}))
+	defer httpServer.Close()
+
+	config := fmt.Sprintf(`{"specUrl": "%s/openapi.yaml", "baseUrl": "http://localhost/api/"}`, httpServer.URL)
+	for i := 0; i < 3; i++ {
+		_, err := NewOpenApiAgentProvider("OpenAPI", "Default", config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetchCount != 1 {
+		t.Errorf("the spec is fetched %d times for 3 providers, want 1", fetchCount)
+	}
+
+	// Refreshing the tools fetches the spec again
+	_, err := GetOpenApiToolsList(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetchCount != 2 {
+		t.Errorf("the spec is fetched %d times after the refresh, want 2", fetchCount)
+	}
+}
+
+func TestGetOpenApiToolName(t *testing.T) {
+	tests := []struct {
+		operationId string
+		method      string
+		path        string
+		want        string
+	}{
+		{"getPet", "get", "/pets/{petId}", "getPet"},
+		{"", "get", "/users/{id}", "get_users_id"},
+		{"", "post", "/users/{id}/__tags", "post_users_id_tags"},
+		{"get__user", "get", "/user", "get_user"},
+	}
+	for _, test := range tests {
+		name := getOpenApiToolName(test.operationId, test.method, test.path)
+		if name != test.want {
+			t.Errorf("getOpenApiToolName(%q, %q, %q) = %q, want %q", test.operationId, test.method, test.path, name, test.want)
+		}
+
+		// The ID of the tool must split back into the server name and the tool name
+		serverName, toolName := GetServerNameAndToolNameFromId(GetIdFromServerNameAndToolName(OpenApiServerName, name))
+		if serverName != OpenApiServerName || toolName != name {
+			t.Errorf("GetServerNameAndToolNameFromId() = %q, %q, want %q, %q", serverName, toolName, OpenApiServerName, name)
+		}
+	}
+}
+
+func TestOpenApiRedirect(t *testing.T) {
+	var leakedKey string
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leakedKey = r.Header.Get("X-Api-Key")
+		fmt.Fprint(w, `{"id": 7}`)
+	}))
+	defer otherServer.Close()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/pets/7":
+			http.Redirect(w, r, "/api/pets/8", http.StatusFound)
+		case "/api/pets/8":
+			fmt.Fprintf(w, `{"id": 8, "key": %q}`, r.Header.Get("X-Api-Key"))
+		default:
+			http.Redirect(w, r, otherServer.URL+r.URL.Path, http.StatusFound)
+		}
+	}))
+	defer httpServer.Close()
+
+	config := fmt.Sprintf(`{"spec": %q, "baseUrl": "%s/api", "auth": {"type": "ApiKey", "name": "X-Api-Key", "in": "header", "token": "test-key"}}`, testOpenApiSpec, httpServer.URL)
+	p, err := NewOpenApiAgentProvider("OpenAPI", "Default", config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentClients, err := p.GetAgentClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agentClients.Clients[OpenApiServerName].Close()
+
+	// A redirect on the same host keeps the auth
+	request := protocol.NewCallToolRequest("getPet", map[string]interface{}{"petId": 7})
+	result, err := agentClients.Clients[OpenApiServerName].CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*protocol.TextContent).Text
+	if result.IsError || !strings.Contains(text, `"key": "test-key"`) {
+		t.Errorf("the redirect on the same host got %q (error: %v)", text, result.IsError)
+	}
+
+	// A redirect to another host is refused, so that the API key is not sent to it
+	request = protocol.NewCallToolRequest("getPet", map[string]interface{}{"petId": 9})
+	result, err = agentClients.Clients[OpenApiServerName].CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || leakedKey != "" {
+		t.Errorf("the redirect to another host should be refused, got %q, leaked key: %q", result.Content[0].(*protocol.TextContent).Text, leakedKey)
+	}
+}
@@ -26,15 +26,49 @@ type AgentProvider interface {
 }
 
 type AgentClients struct {
-	Clients map[string]*client.Client
-	Tools   []*protocol.Tool
+	Clients  map[string]*client.Client
+	Tools    []*protocol.Tool
+	Policies map[string]string
+
+	// pooledServers are the servers whose clients are shared across requests by the MCP client pool
+	pooledServers map[string]bool
+	isClosed      bool
+}
+
+// GetToolPolicy returns the policy of the tool ID, "Auto" if the tool has no policy
+func (agentClients *AgentClients) GetToolPolicy(id string) string {
+	if agentClients == nil || agentClients.Policies[id] == "" {
+		return ToolPolicyAuto
+	}
+	return agentClients.Policies[id]
+}
+
+// Close closes the clients of the request, the pooled clients are released and kept open for the later requests,
+// it can be called more than once
+func (agentClients *AgentClients) Close() {
+	if agentClients == nil || agentClients.isClosed {
+		return
+	}
+	agentClients.isClosed = true
+
+	for name, cli := range agentClients.Clients {
+		if agentClients.pooledServers[name] {
+			releasePooledMcpClient(cli)
+		} else {
+			cli.Close()
+		}
+	}
 }
 
-func GetAgentProvider(typ string, subType string, text string, mcpTools []*McpTools) (AgentProvider, error) {
+func GetAgentProvider(typ string, subType string, text string, mcpTools []*McpTools, searcher KnowledgeSearcher) (AgentProvider, error) {
 	var p AgentProvider
 	var err error
 	if typ == "MCP" {
 		p, err = NewMcpAgentProvider(typ, subType, text, mcpTools)
+	} else if typ == "Builtin" {
+		p, err = NewBuiltinAgentProvider(typ, subType, text, mcpTools, searcher)
+	} else if typ == "OpenAPI" {
+		p, err = NewOpenApiAgentProvider(typ, subType, text, mcpTools)
 	} else {
 		return nil, fmt.Errorf("the agent provider type: %s is not supported", typ)
 	}
@@ -0,0 +1,162 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carrier
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The dividers of the other carriers, a value of a prompt carrier ends at any of them
+var carrierDividers = []string{"<<<", "=====", "|||"}
+
+// PromptCarrier asks the model to append a value to its answer by an admin-defined instruction, like the intent of
+// the question or the confidence of the answer. The value follows the marker of the carrier, and it is extracted
+// from the text by the parse type: "Text" for the whole text, "Regex" for the first group (or the match) of the
+// pattern, and "JSON" for the field of the pattern path in the JSON object, like "intent.name"
+type PromptCarrier struct {
+	name        string
+	instruction string
+	parseType   string
+	pattern     string
+	regex       *regexp.Regexp
+}
+
+func NewPromptCarrier(name string, instruction string, parseType string, pattern string) (*PromptCarrier, error) {
+	if name == "" || strings.ContainsAny(name, "<>\n") {
+		return nil, fmt.Errorf("the carrier name: %q is invalid", name)
+	}
+
+	p := &PromptCarrier{name: name, instruction: instruction, parseType: parseType, pattern: pattern}
+	if parseType == "Regex" {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the pattern of the carrier: %s is invalid, %s", name, err.Error())
+		}
+		p.regex = regex
+	} else if parseType != "Text" && parseType != "JSON" {
+		return nil, fmt.Errorf("the parse type: %s of the carrier: %s is not supported", parseType, name)
+	}
+	return p, nil
+}
+
+func (p *PromptCarrier) GetName() string {
+	return p.name
+}
+
+func (p *PromptCarrier) GetMarker() string {
+	return "<<<" + p.name + ">>>"
+}
+
+func (p *PromptCarrier) GetInstruction() string {
+	instruction := p.instruction
+	if p.parseType == "JSON" {
+		instruction += "\nOutput it as a JSON object on a single line."
+	}
+	return fmt.Sprintf("%s\nOutput it on a new line, prefixed by: %s", instruction, p.GetMarker())
+}
+
+func (p *PromptCarrier) GetQuestion(question string) (string, error) {
+	question = question +
+		"\n\n**At the end of your answer, also append the following.**\n" +
+		p.GetInstruction() + "\n" +
+		"Do not explain it, and do not mention it in your answer.\n"
+	return question, nil
+}
+
+// ParseAnswer removes the marker and the value of the carrier from the answer, the value is extracted by the parse
+// type, it is empty if the model does not give it
+func (p *PromptCarrier) ParseAnswer(answer string) (string, []string, error) {
+	marker := p.GetMarker()
+	start := strings.Index(answer, marker)
+	if start < 0 {
+		return answer, []string{""}, nil
+	}
+
+	rest := answer[start+len(marker):]
+	end := len(rest)
+	for _, divider := range carrierDividers {
+		i := strings.Index(rest, divider)
+		if i >= 0 && i < end {
+			end = i
+		}
+	}
+
+	text := strings.TrimSpace(rest[:end])
+	parsedAnswer := strings.TrimRight(answer[:start], " \t\r\n") + rest[end:]
+
+	value, err := p.extractValue(text)
+	if err != nil {
+		return parsedAnswer, []string{""}, nil
+	}
+	return parsedAnswer, []string{value}, nil
+}
+
+func (p *PromptCarrier) extractValue(text string) (string, error) {
+	switch p.parseType {
+	case "Regex":
+		match := p.regex.FindStringSubmatch(text)
+		if match == nil {
+			return "", nil
+		}
+		if len(match) > 1 {
+			return strings.TrimSpace(match[1]), nil
+		}
+		return strings.TrimSpace(match[0]), nil
+	case "JSON":
+		return getJsonFieldText(text, p.pattern)
+	default:
+		return text, nil
+	}
+}
+
+func getJsonFieldText(text string, path string) (string, error) {
+	// The object may be wrapped in a code block, so only the text between the outermost braces is parsed
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("no JSON object is found in: %s", text)
+	}
+
+	var value interface{}
+	err := json.Unmarshal([]byte(text[start:end+1]), &value)
+	if err != nil {
+		return "", err
+	}
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("the JSON field: %s is not found", path)
+			}
+			value, ok = m[key]
+			if !ok {
+				return "", fmt.Errorf("the JSON field: %s is not found", path)
+			}
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carrier
+
+import "testing"
+
+func TestPromptCarrierParseAnswer(t *testing.T) {
+	tests := []struct {
+		parseType  string
+		pattern    string
+		answer     string
+		wantAnswer string
+		wantValue  string
+	}{
+		{"Text", "", "Paris is the capital.\n<<<intent>>> geography\n=====Capital of France", "Paris is the capital.=====Capital of France", "geography"},
+		{"Regex", `(\d+)%`, "It is sunny.\n<<<intent>>>Confidence: 85%|||Will it rain?", "It is sunny.|||Will it rain?", "85"},
+		{"JSON", "intent.name", "Hello!\n<<<intent>>>```json\n{\"intent\": {\"name\": \"greeting\"}}\n```", "Hello!", "greeting"},
+		{"JSON", "", "Hello!\n<<<intent>>>{\"followUp\": true}", "Hello!", `{"followUp":true}`},
+		{"JSON", "intent", "Hello!\n<<<intent>>>not a JSON", "Hello!", ""},
+		{"Text", "", "No carrier value here.", "No carrier value here.", ""},
+	}
+
+	for _, test := range tests {
+		p, err := NewPromptCarrier("intent", "Classify the intent of the question.", test.parseType, test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		answer, values, err := p.ParseAnswer(test.answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if answer != test.wantAnswer || values[0] != test.wantValue {
+			t.Errorf("ParseAnswer(%q) = %q, %q, want %q, %q", test.answer, answer, values[0], test.wantAnswer, test.wantValue)
+		}
+	}
+}
+
+func TestNewPromptCarrier(t *testing.T) {
+	_, err := NewPromptCarrier("intent", "", "Regex", "(")
+	if err == nil {
+		t.Errorf("the invalid pattern should be an error")
+	}
+
+	_, err = NewPromptCarrier("in<tent", "", "Text", "")
+	if err == nil {
+		t.Errorf("the invalid name should be an error")
+	}
+
+	_, err = NewPromptCarrier("intent", "", "XML", "")
+	if err == nil {
+		t.Errorf("the unsupported parse type should be an error")
+	}
+}
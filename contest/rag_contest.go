@@ -89,7 +89,7 @@ func sendMessage(store *object.Store, question string, modelProviderName string,
 		return "", nil, err
 	}
 
-	knowledge, _, _, err := object.GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, store.KnowledgeCount)
+	knowledge, _, _, err := object.GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, store.KnowledgeCount, nil)
 	if err != nil {
 		return "", nil, err
 	}
@@ -0,0 +1,454 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+	"github.com/sashabaranov/go-openai"
+)
+
+type AnthropicMessagesRequest struct {
+	Model       string               `json:"model"`
+	Messages    []AnthropicMessage   `json:"messages"`
+	System      json.RawMessage      `json:"system"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature *float32             `json:"temperature"`
+	TopP        *float32             `json:"top_p"`
+	TopK        *int                 `json:"top_k"`
+	Stream      bool                 `json:"stream"`
+	Tools       []AnthropicTool      `json:"tools"`
+	ToolChoice  *AnthropicToolChoice `json:"tool_choice"`
+	Thinking    *AnthropicThinking   `json:"thinking"`
+}
+
+type AnthropicMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type AnthropicContentBlock struct {
+	Type      string                `json:"type"`
+	Text      string                `json:"text"`
+	Source    *AnthropicImageSource `json:"source"`
+	Id        string                `json:"id"`
+	Name      string                `json:"name"`
+	Input     json.RawMessage       `json:"input"`
+	ToolUseId string                `json:"tool_use_id"`
+	Content   json.RawMessage       `json:"content"`
+	IsError   bool                  `json:"is_error"`
+}
+
+type AnthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+	Url       string `json:"url"`
+}
+
+type AnthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type AnthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type AnthropicThinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// ResponseAnthropicError returns an error in the format of the Anthropic API
+func (c *ApiController) ResponseAnthropicError(status int, message string) {
+	typ := "invalid_request_error"
+	if status == http.StatusUnauthorized {
+		typ = "authentication_error"
+	} else if status >= http.StatusInternalServerError {
+		typ = "api_error"
+	}
+
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = map[string]interface{}{
+		"type":  "error",
+		"error": OpenAiError{Message: message, Type: typ},
+	}
+	c.ServeJSON()
+}
+
+// getAnthropicApiProvider authenticates the request by the "x-api-key" header like the Anthropic API,
+// or by the bearer token like the OpenAI-compatible API
+func (c *ApiController) getAnthropicApiProvider() (*object.Provider, bool) {
+	apiKey := c.Ctx.Request.Header.Get("x-api-key")
+	if apiKey == "" {
+		apiKey = strings.TrimPrefix(c.Ctx.Request.Header.Get("Authorization"), "Bearer ")
+	}
+	if apiKey == "" {
+		c.ResponseAnthropicError(http.StatusUnauthorized, "Missing API key, expected the 'x-api-key' header")
+		return nil, false
+	}
+
+	provider, err := object.GetProviderByProviderKeyAndCategory(apiKey, "Model")
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusUnauthorized, fmt.Sprintf("Authentication failed: %s", err.Error()))
+		return nil, false
+	}
+
+	return provider, true
+}
+
+// getAnthropicThinkingBudget returns the thinking budget of the request, checked like the Anthropic API does, or 0
+// if the request does not enable thinking
+func getAnthropicThinkingBudget(request *AnthropicMessagesRequest) (int, error) {
+	if request.Thinking == nil || request.Thinking.Type != "enabled" {
+		return 0, nil
+	}
+
+	budgetTokens := request.Thinking.BudgetTokens
+	if budgetTokens < 1024 {
+		return 0, fmt.Errorf("thinking.budget_tokens: should be at least 1024, got: %d", budgetTokens)
+	}
+	if request.MaxTokens > 0 && budgetTokens >= request.MaxTokens {
+		return 0, fmt.Errorf("thinking.budget_tokens: should be less than max_tokens: %d, got: %d", request.MaxTokens, budgetTokens)
+	}
+	return budgetTokens, nil
+}
+
+// getAnthropicContentBlocks parses a content that is either a plain string or an array of content blocks
+func getAnthropicContentBlocks(content json.RawMessage) ([]AnthropicContentBlock, error) {
+	if len(content) == 0 || string(content) == "null" {
+		return []AnthropicContentBlock{}, nil
+	}
+
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return []AnthropicContentBlock{{Type: "text", Text: text}}, nil
+	}
+
+	blocks := []AnthropicContentBlock{}
+	err := json.Unmarshal(content, &blocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the content: %s", err.Error())
+	}
+	return blocks, nil
+}
+
+func getAnthropicContentText(content json.RawMessage) (string, error) {
+	blocks, err := getAnthropicContentBlocks(content)
+	if err != nil {
+		return "", err
+	}
+
+	texts := []string{}
+	for _, block := range blocks {
+		if block.Type == "text" {
+			texts = append(texts, block.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// anthropicMessagesToOpenaiMessages converts the Anthropic messages to the OpenAI ones, so that they go through
+// the same conversion as the OpenAI-compatible API. The thinking blocks of the history are dropped.
+func anthropicMessagesToOpenaiMessages(request *AnthropicMessagesRequest) ([]openai.ChatCompletionMessage, error) {
+	res := []openai.ChatCompletionMessage{}
+
+	system, err := getAnthropicContentText(request.System)
+	if err != nil {
+		return nil, err
+	}
+	if system != "" {
+		res = append(res, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: system})
+	}
+
+	for _, message := range request.Messages {
+		blocks, err := getAnthropicContentBlocks(message.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		if message.Role == "assistant" {
+			texts := []string{}
+			toolCalls := []openai.ToolCall{}
+			for _, block := range blocks {
+				if block.Type == "text" {
+					texts = append(texts, block.Text)
+				} else if block.Type == "tool_use" {
+					toolCalls = append(toolCalls, openai.ToolCall{
+						ID:       block.Id,
+						Type:     openai.ToolTypeFunction,
+						Function: openai.FunctionCall{Name: block.Name, Arguments: string(block.Input)},
+					})
+				}
+			}
+
+			assistantMessage := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: strings.Join(texts, "\n")}
+			if len(toolCalls) > 0 {
+				assistantMessage.ToolCalls = toolCalls
+			}
+			res = append(res, assistantMessage)
+			continue
+		}
+
+		// The tool results come first, as they answer the tool calls of the previous assistant message
+		parts := []openai.ChatMessagePart{}
+		for _, block := range blocks {
+			switch block.Type {
+			case "tool_result":
+				text, err := getAnthropicContentText(block.Content)
+				if err != nil {
+					return nil, err
+				}
+				if block.IsError {
+					text = fmt.Sprintf("Error: %s", text)
+				}
+
+				res = append(res, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleTool, Content: text, ToolCallID: block.ToolUseId})
+			case "text":
+				parts = append(parts, openai.ChatMessagePart{Type: openai.ChatMessagePartTypeText, Text: block.Text})
+			case "image":
+				if block.Source == nil {
+					continue
+				}
+
+				url := block.Source.Url
+				if block.Source.Type == "base64" {
+					url = fmt.Sprintf("data:%s;base64,%s", block.Source.MediaType, block.Source.Data)
+				}
+				parts = append(parts, openai.ChatMessagePart{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: url}})
+			}
+		}
+
+		if len(parts) == 1 && parts[0].Type == openai.ChatMessagePartTypeText {
+			res = append(res, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: parts[0].Text})
+		} else if len(parts) > 0 {
+			res = append(res, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, MultiContent: parts})
+		}
+	}
+
+	return res, nil
+}
+
+func anthropicToolsToOpenaiTools(tools []AnthropicTool) []openai.Tool {
+	res := []openai.Tool{}
+	for _, tool := range tools {
+		function := &openai.FunctionDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+		}
+		if len(tool.InputSchema) > 0 {
+			function.Parameters = tool.InputSchema
+		}
+
+		res = append(res, openai.Tool{Type: openai.ToolTypeFunction, Function: function})
+	}
+	return res
+}
+
+func getAnthropicContent(writer *AnthropicWriter, toolCalls []openai.ToolCall) []map[string]interface{} {
+	content := []map[string]interface{}{}
+	if writer.ShowThinking && writer.ReasonString() != "" {
+		content = append(content, map[string]interface{}{"type": "thinking", "thinking": writer.ReasonString(), "signature": ""})
+	}
+	if writer.MessageString() != "" {
+		content = append(content, map[string]interface{}{"type": "text", "text": writer.MessageString()})
+	}
+
+	for _, toolCall := range toolCalls {
+		var input interface{} = map[string]interface{}{}
+		if toolCall.Function.Arguments != "" {
+			err := json.Unmarshal([]byte(toolCall.Function.Arguments), &input)
+			if err != nil {
+				input = map[string]interface{}{}
+			}
+		}
+
+		content = append(content, map[string]interface{}{
+			"type":  "tool_use",
+			"id":    toolCall.ID,
+			"name":  toolCall.Function.Name,
+			"input": input,
+		})
+	}
+	return content
+}
+
+// AnthropicMessages implements the Anthropic-compatible messages API
+// @Title AnthropicMessages
+// @Tag Anthropic Compatible API
+// @Description Anthropic compatible messages API, the tools in the request are returned as tool use blocks for the client to run
+// @Param   body    body    controllers.AnthropicMessagesRequest  true    "The Anthropic messages request"
+// @Success 200 {object} object
+// @router /v1/messages [post]
+func (c *ApiController) AnthropicMessages() {
+	provider, ok := c.getAnthropicApiProvider()
+	if !ok {
+		return
+	}
+
+	var request AnthropicMessagesRequest
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &request)
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusBadRequest, fmt.Sprintf("Failed to parse request: %s", err.Error()))
+		return
+	}
+
+	messages, err := anthropicMessagesToOpenaiMessages(&request)
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompt, question, history, agentMessages, err := model.OpenaiMessagesToRawMessages(messages)
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	budgetTokens, err := getAnthropicThinkingBudget(&request)
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The thinking of the request overrides the one configured for the provider
+	showThinking := budgetTokens > 0
+	if request.Thinking != nil {
+		provider.EnableThinking = showThinking
+	}
+
+	modelProvider, err := provider.GetModelProvider()
+	if err != nil {
+		c.ResponseAnthropicError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	agentInfo := &model.AgentInfo{
+		AgentMessages: &model.AgentMessages{Messages: agentMessages},
+	}
+	if len(request.Tools) > 0 && (request.ToolChoice == nil || request.ToolChoice.Type != "none") {
+		tools, err := model.OpenaiToolsToMcpTools(anthropicToolsToOpenaiTools(request.Tools))
+		if err != nil {
+			c.ResponseAnthropicError(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// The tools are run by the client, so there are no clients to call them here
+		agentInfo.AgentClients = &agent.AgentClients{Tools: tools}
+	}
+
+	responseModel := request.Model
+	if responseModel == "" {
+		responseModel = provider.Name
+	}
+
+	if request.Stream {
+		c.Ctx.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
+		c.Ctx.ResponseWriter.Header().Set("Cache-Control", "no-cache")
+		c.Ctx.ResponseWriter.Header().Set("Connection", "keep-alive")
+	}
+
+	writer := &AnthropicWriter{
+		Response:     *c.Ctx.ResponseWriter,
+		Cleaner:      *NewCleaner(6),
+		RequestID:    util.GenerateUUID(),
+		Stream:       request.Stream,
+		Model:        responseModel,
+		ShowThinking: showThinking,
+	}
+
+	// The sampling parameters of the request override the ones configured for the provider
+	options := &model.QueryOptions{
+		MaxTokens:      request.MaxTokens,
+		Temperature:    request.Temperature,
+		TopP:           request.TopP,
+		TopK:           request.TopK,
+		ThinkingBudget: budgetTokens,
+	}
+
+	knowledge := []*model.RawMessage{}
+	modelResult, err := modelProvider.QueryText(c.Ctx.Request.Context(), question, writer, history, prompt, knowledge, agentInfo, options)
+	if err != nil {
+		if writer.StreamSent {
+			_ = writer.writeEvent("error", map[string]interface{}{
+				"type":  "error",
+				"error": OpenAiError{Message: err.Error(), Type: "api_error"},
+			})
+			c.EnableRender = false
+			return
+		}
+
+		c.ResponseAnthropicError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	answerMessage := &object.Message{
+		Text:              writer.MessageString(),
+		ReasonText:        writer.ReasonString(),
+		TokenCount:        modelResult.TotalTokenCount,
+		Price:             modelResult.TotalPrice,
+		Currency:          modelResult.Currency,
+		ModelProvider:     provider.Name,
+		UsedModelProvider: provider.Name,
+	}
+	if modelResult.Provider != "" {
+		answerMessage.UsedModelProvider = modelResult.Provider
+	}
+	err = c.addOpenAiApiUsage(fmt.Sprintf("chat_api_%s", provider.Name), "", &object.Message{Text: question}, answerMessage)
+	if err != nil {
+		fmt.Printf("AnthropicMessages() error: failed to record the usage, %s\n", err.Error())
+	}
+
+	toolCalls := model.GetToolCalls(agentInfo.AgentMessages)
+	stopReason := "end_turn"
+	if len(toolCalls) > 0 {
+		stopReason = "tool_use"
+	}
+	usage := map[string]interface{}{
+		"input_tokens":  modelResult.PromptTokenCount,
+		"output_tokens": modelResult.ResponseTokenCount,
+	}
+
+	if request.Stream {
+		err = writer.Close(toolCalls, stopReason, usage)
+		if err != nil {
+			fmt.Printf("AnthropicMessages() error: %s\n", err.Error())
+		}
+		c.EnableRender = false
+		return
+	}
+
+	c.Data["json"] = map[string]interface{}{
+		"id":            "msg_" + writer.RequestID,
+		"type":          "message",
+		"role":          "assistant",
+		"model":         writer.Model,
+		"content":       getAnthropicContent(writer, toolCalls),
+		"stop_reason":   stopReason,
+		"stop_sequence": nil,
+		"usage":         usage,
+	}
+	c.ServeJSON()
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestAnthropicMessagesToOpenaiMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    []openai.ChatCompletionMessage
+	}{
+		{
+			name:    "string content",
+			request: `{"system":"You are a bot.","messages":[{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello!"},{"role":"user","content":"How are you?"}]}`,
+			want: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: "You are a bot."},
+				{Role: openai.ChatMessageRoleUser, Content: "Hi"},
+				{Role: openai.ChatMessageRoleAssistant, Content: "Hello!"},
+				{Role: openai.ChatMessageRoleUser, Content: "How are you?"},
+			},
+		},
+		{
+			name:    "system blocks",
+			request: `{"system":[{"type":"text","text":"Be brief."},{"type":"text","text":"Be kind."}],"messages":[{"role":"user","content":[{"type":"text","text":"Hi"}]}]}`,
+			want: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: "Be brief.\nBe kind."},
+				{Role: openai.ChatMessageRoleUser, Content: "Hi"},
+			},
+		},
+		{
+			name: "tool use and result",
+			request: `{"messages":[{"role":"user","content":"Weather in Paris?"},` +
+				`{"role":"assistant","content":[{"type":"thinking","thinking":"Let me check."},{"type":"text","text":"Checking."},{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}]},` +
+				`{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"Sunny"},{"type":"text","text":"And tomorrow?"}]}]}`,
+			want: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: "Weather in Paris?"},
+				{Role: openai.ChatMessageRoleAssistant, Content: "Checking.", ToolCalls: []openai.ToolCall{
+					{ID: "toolu_1", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+				}},
+				{Role: openai.ChatMessageRoleTool, Content: "Sunny", ToolCallID: "toolu_1"},
+				{Role: openai.ChatMessageRoleUser, Content: "And tomorrow?"},
+			},
+		},
+		{
+			name:    "tool error",
+			request: `{"messages":[{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"city not found"}],"is_error":true}]}]}`,
+			want: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleTool, Content: "Error: city not found", ToolCallID: "toolu_1"},
+			},
+		},
+		{
+			name:    "images",
+			request: `{"messages":[{"role":"user","content":[{"type":"text","text":"What is this?"},{"type":"image","source":{"type":"base64","media_type":"image/png","data":"AAAA"}},{"type":"image","source":{"type":"url","url":"https://example.com/a.png"}}]}]}`,
+			want: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, MultiContent: []openai.ChatMessagePart{
+					{Type: openai.ChatMessagePartTypeText, Text: "What is this?"},
+					{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "data:image/png;base64,AAAA"}},
+					{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "https://example.com/a.png"}},
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var request AnthropicMessagesRequest
+			err := json.Unmarshal([]byte(test.request), &request)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			messages, err := anthropicMessagesToOpenaiMessages(&request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(messages, test.want) {
+				t.Errorf("anthropicMessagesToOpenaiMessages() = %+v, want %+v", messages, test.want)
+			}
+		})
+	}
+
+	request := AnthropicMessagesRequest{Messages: []AnthropicMessage{{Role: "user", Content: json.RawMessage(`{"type":"text"}`)}}}
+	_, err := anthropicMessagesToOpenaiMessages(&request)
+	if err == nil {
+		t.Error("anthropicMessagesToOpenaiMessages() should fail on a content that is neither a string nor blocks")
+	}
+}
+
+func TestAnthropicToolsToOpenaiTools(t *testing.T) {
+	tools := []AnthropicTool{
+		{Name: "get_weather", Description: "Get the weather", InputSchema: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`)},
+		{Name: "get_time"},
+	}
+
+	want := []openai.Tool{
+		{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "get_weather", Description: "Get the weather", Parameters: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`)}},
+		{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "get_time"}},
+	}
+	if res := anthropicToolsToOpenaiTools(tools); !reflect.DeepEqual(res, want) {
+		t.Errorf("anthropicToolsToOpenaiTools() = %+v, want %+v", res, want)
+	}
+}
+
+func TestGetAnthropicContent(t *testing.T) {
+	writer := &AnthropicWriter{ShowThinking: true}
+	writer.MessageBuf = []byte("Let me check.")
+	writer.ReasonBuf = []byte("The user asks about Paris.")
+	toolCalls := []openai.ToolCall{
+		{ID: "call_1", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_2", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "get_time", Arguments: `not json`}},
+	}
+
+	want := []map[string]interface{}{
+		{"type": "thinking", "thinking": "The user asks about Paris.", "signature": ""},
+		{"type": "text", "text": "Let me check."},
+		{"type": "tool_use", "id": "call_1", "name": "get_weather", "input": map[string]interface{}{"city": "Paris"}},
+		{"type": "tool_use", "id": "call_2", "name": "get_time", "input": map[string]interface{}{}},
+	}
+	if content := getAnthropicContent(writer, toolCalls); !reflect.DeepEqual(content, want) {
+		t.Errorf("getAnthropicContent() = %+v, want %+v", content, want)
+	}
+
+	// The thinking is only returned when the request enables it
+	writer.ShowThinking = false
+	if content := getAnthropicContent(writer, nil); len(content) != 1 || content[0]["type"] != "text" {
+		t.Errorf("getAnthropicContent() = %+v, want only the text", content)
+	}
+}
+
+func TestGetAnthropicThinkingBudget(t *testing.T) {
+	tests := []struct {
+		thinking  *AnthropicThinking
+		maxTokens int
+		want      int
+		wantErr   bool
+	}{
+		{nil, 4096, 0, false},
+		{&AnthropicThinking{Type: "disabled"}, 4096, 0, false},
+		{&AnthropicThinking{Type: "enabled", BudgetTokens: 2048}, 4096, 2048, false},
+		{&AnthropicThinking{Type: "enabled", BudgetTokens: 2048}, 0, 2048, false},
+		{&AnthropicThinking{Type: "enabled"}, 4096, 0, true},
+		{&AnthropicThinking{Type: "enabled", BudgetTokens: 512}, 4096, 0, true},
+		{&AnthropicThinking{Type: "enabled", BudgetTokens: 4096}, 4096, 0, true},
+	}
+	for _, test := range tests {
+		budgetTokens, err := getAnthropicThinkingBudget(&AnthropicMessagesRequest{Thinking: test.thinking, MaxTokens: test.maxTokens})
+		if budgetTokens != test.want || (err != nil) != test.wantErr {
+			t.Errorf("getAnthropicThinkingBudget(%+v, %d) = (%d, %v), want %d", test.thinking, test.maxTokens, budgetTokens, err, test.want)
+		}
+	}
+}
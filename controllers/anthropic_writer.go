@@ -0,0 +1,246 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/beego/beego/context"
+	"github.com/sashabaranov/go-openai"
+)
+
+// AnthropicWriter implements a writer that formats responses in Anthropic Messages format
+type AnthropicWriter struct {
+	context.Response
+	Cleaner      Cleaner
+	MessageBuf   []byte
+	ReasonBuf    []byte
+	RequestID    string
+	Stream       bool
+	StreamSent   bool
+	Model        string
+	ShowThinking bool
+	blockIndex   int
+	blockType    string
+}
+
+// Write processes incoming data chunks and streams them as content block deltas
+func (w *AnthropicWriter) Write(p []byte) (n int, err error) {
+	var content string
+	var reasonContent string
+
+	if bytes.HasPrefix(p, []byte("event: message\ndata: ")) {
+		prefix := []byte("event: message\ndata: ")
+		suffix := []byte("\n\n")
+		content = string(bytes.TrimSuffix(bytes.TrimPrefix(p, prefix), suffix))
+		w.MessageBuf = append(w.MessageBuf, []byte(content)...)
+	} else if bytes.HasPrefix(p, []byte("event: reason\ndata: ")) {
+		// Reason data is exposed as thinking blocks, only when the request enables thinking
+		prefix := []byte("event: reason\ndata: ")
+		suffix := []byte("\n\n")
+		reasonContent = string(bytes.TrimSuffix(bytes.TrimPrefix(p, prefix), suffix))
+		w.ReasonBuf = append(w.ReasonBuf, []byte(reasonContent)...)
+		if !w.ShowThinking {
+			reasonContent = ""
+		}
+	} else {
+		content = w.Cleaner.CleanString(string(p))
+		if content != "" {
+			w.MessageBuf = append(w.MessageBuf, []byte(content)...)
+		}
+	}
+
+	if !w.Stream {
+		return len(p), nil
+	}
+
+	if reasonContent != "" {
+		err = w.writeDelta("thinking", map[string]interface{}{"type": "thinking_delta", "thinking": reasonContent})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if content != "" {
+		err = w.writeDelta("text", map[string]interface{}{"type": "text_delta", "text": content})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// start sends the message_start event once, before any content of the stream
+func (w *AnthropicWriter) start() error {
+	if w.StreamSent {
+		return nil
+	}
+
+	w.StreamSent = true
+	return w.writeEvent("message_start", map[string]interface{}{
+		"type": "message_start",
+		"message": map[string]interface{}{
+			"id":            "msg_" + w.RequestID,
+			"type":          "message",
+			"role":          "assistant",
+			"model":         w.Model,
+			"content":       []interface{}{},
+			"stop_reason":   nil,
+			"stop_sequence": nil,
+			"usage":         map[string]interface{}{"input_tokens": 0, "output_tokens": 0},
+		},
+	})
+}
+
+// writeDelta sends a delta of the block type, closing the open block and starting a new one when the type changes
+func (w *AnthropicWriter) writeDelta(blockType string, delta map[string]interface{}) error {
+	err := w.start()
+	if err != nil {
+		return err
+	}
+
+	if w.blockType != blockType {
+		err = w.stopBlock()
+		if err != nil {
+			return err
+		}
+
+		contentBlock := map[string]interface{}{"type": blockType, blockType: ""}
+		if blockType == "thinking" {
+			contentBlock["signature"] = ""
+		}
+		err = w.startBlock(contentBlock)
+		if err != nil {
+			return err
+		}
+		w.blockType = blockType
+	}
+
+	return w.writeEvent("content_block_delta", map[string]interface{}{
+		"type":  "content_block_delta",
+		"index": w.blockIndex,
+		"delta": delta,
+	})
+}
+
+func (w *AnthropicWriter) startBlock(contentBlock map[string]interface{}) error {
+	return w.writeEvent("content_block_start", map[string]interface{}{
+		"type":          "content_block_start",
+		"index":         w.blockIndex,
+		"content_block": contentBlock,
+	})
+}
+
+func (w *AnthropicWriter) stopBlock() error {
+	if w.blockType == "" {
+		return nil
+	}
+
+	err := w.writeEvent("content_block_stop", map[string]interface{}{
+		"type":  "content_block_stop",
+		"index": w.blockIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.blockIndex += 1
+	w.blockType = ""
+	return nil
+}
+
+func (w *AnthropicWriter) writeEvent(event string, data interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.ResponseWriter.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, jsonData)))
+	if err != nil {
+		return err
+	}
+
+	w.Flush()
+	return nil
+}
+
+// MessageString returns the complete buffered message
+func (w *AnthropicWriter) MessageString() string {
+	return string(w.MessageBuf)
+}
+
+// ReasonString returns the complete buffered reasoning
+func (w *AnthropicWriter) ReasonString() string {
+	return string(w.ReasonBuf)
+}
+
+// Close finalizes the stream by sending the tool use blocks requested by the model, the stop reason
+// with the token usage and the message_stop event
+func (w *AnthropicWriter) Close(toolCalls []openai.ToolCall, stopReason string, usage map[string]interface{}) error {
+	if !w.Stream {
+		return nil
+	}
+
+	err := w.start()
+	if err != nil {
+		return err
+	}
+
+	err = w.stopBlock()
+	if err != nil {
+		return err
+	}
+
+	for _, toolCall := range toolCalls {
+		err = w.startBlock(map[string]interface{}{
+			"type":  "tool_use",
+			"id":    toolCall.ID,
+			"name":  toolCall.Function.Name,
+			"input": map[string]interface{}{},
+		})
+		if err != nil {
+			return err
+		}
+		w.blockType = "tool_use"
+
+		err = w.writeEvent("content_block_delta", map[string]interface{}{
+			"type":  "content_block_delta",
+			"index": w.blockIndex,
+			"delta": map[string]interface{}{"type": "input_json_delta", "partial_json": toolCall.Function.Arguments},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = w.stopBlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = w.writeEvent("message_delta", map[string]interface{}{
+		"type":  "message_delta",
+		"delta": map[string]interface{}{"stop_reason": stopReason, "stop_sequence": nil},
+		"usage": usage,
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.writeEvent("message_stop", map[string]interface{}{"type": "message_stop"})
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// getOwnChat returns the chat of the id if it belongs to the current user, otherwise it responds with the error
+func (c *ApiController) getOwnChat(id string) (*object.Chat, bool) {
+	chat, err := object.GetChat(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if chat == nil {
+		c.ResponseError(fmt.Sprintf("The chat: %s is not found", id))
+		return nil, false
+	}
+
+	ok := c.IsCurrentUser(chat.User)
+	if !ok {
+		return nil, false
+	}
+	return chat, true
+}
+
+// ExportChat
+// @Title ExportChat
+// @Tag Chat API
+// @Description export the chat with the messages of its active branch as Markdown, JSON or HTML
+// @Param id query string true "The id (owner/name) of the chat"
+// @Param format query string false "The format: Markdown, JSON or HTML, default is Markdown"
+// @Success 200 {object} controllers.Response The Response object, the data is the exported text and the data2 is the file name
+// @router /export-chat [get]
+func (c *ApiController) ExportChat() {
+	id := c.Input().Get("id")
+	format := c.Input().Get("format")
+
+	chat, ok := c.getOwnChat(id)
+	if !ok {
+		return
+	}
+
+	chatExport, err := object.GetChatExport(chat)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	var text string
+	var ext string
+	switch format {
+	case "", object.ChatExportFormatMarkdown:
+		text = chatExport.GetMarkdown()
+		ext = "md"
+	case object.ChatExportFormatJson:
+		data, err := json.MarshalIndent(chatExport, "", "  ")
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		text = string(data)
+		ext = "json"
+	case object.ChatExportFormatHtml:
+		text = chatExport.GetHtml()
+		ext = "html"
+	default:
+		c.ResponseError(fmt.Sprintf("The export format: %s is not supported", format))
+		return
+	}
+
+	c.ResponseOk(text, fmt.Sprintf("%s.%s", chat.Name, ext))
+}
+
+// ImportChat
+// @Title ImportChat
+// @Tag Chat API
+// @Description import a chat from the JSON of an exported chat as a new chat of the current user
+// @Param store query string false "The store of the new chat, default is the store of the exported chat or the default store"
+// @Param body body object.ChatExport true "The JSON of the exported chat"
+// @Success 200 {object} controllers.Response The Response object, the data is the new chat
+// @router /import-chat [post]
+func (c *ApiController) ImportChat() {
+	storeName := c.Input().Get("store")
+
+	user, ok := c.RequireSignedInUser()
+	if !ok {
+		return
+	}
+
+	chatExport, err := object.ParseChatExport(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if storeName == "" {
+		storeName = chatExport.Store
+	}
+
+	var store *object.Store
+	if storeName != "" {
+		store, err = object.GetStore(util.GetId("admin", storeName))
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+	if store == nil {
+		store, err = object.GetDefaultStore("admin")
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+		if store == nil {
+			c.ResponseError("The default store is not found")
+			return
+		}
+	}
+
+	currentTime := util.GetCurrentTime()
+	chat := &object.Chat{
+		Owner:        "admin",
+		Name:         fmt.Sprintf("chat_%s", util.GetRandomName()),
+		CreatedTime:  currentTime,
+		UpdatedTime:  currentTime,
+		Organization: user.Owner,
+		Store:        store.Name,
+		Category:     "Default Category",
+		Type:         "AI",
+		User:         user.Name,
+		Users:        []string{},
+		ClientIp:     c.getClientIp(),
+		UserAgent:    c.getUserAgent(),
+	}
+	chat.ClientIpDesc = util.GetDescFromIP(chat.ClientIp)
+	chat.UserAgentDesc = util.GetDescFromUserAgent(chat.UserAgent)
+
+	success, err := object.ImportChatExport(chat, chatExport, user)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if !success {
+		c.ResponseError(fmt.Sprintf("Failed to import the chat: %s", chatExport.DisplayName))
+		return
+	}
+
+	c.ResponseOk(chat)
+}
+
+// ShareChat
+// @Title ShareChat
+// @Tag Chat API
+// @Description create or update the read-only public share link of the chat
+// @Param id query string true "The id (owner/name) of the chat"
+// @Param expireTime query string false "The RFC 3339 expire time of the link, empty means the link never expires"
+// @Success 200 {object} controllers.Response The Response object, the data is the chat with the share id
+// @router /share-chat [post]
+func (c *ApiController) ShareChat() {
+	id := c.Input().Get("id")
+	expireTime := c.Input().Get("expireTime")
+
+	chat, ok := c.getOwnChat(id)
+	if !ok {
+		return
+	}
+
+	_, err := object.ShareChat(chat, expireTime)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(chat)
+}
+
+// UnshareChat
+// @Title UnshareChat
+// @Tag Chat API
+// @Description revoke the public share link of the chat
+// @Param id query string true "The id (owner/name) of the chat"
+// @Success 200 {object} controllers.Response The Response object
+// @router /unshare-chat [post]
+func (c *ApiController) UnshareChat() {
+	id := c.Input().Get("id")
+
+	chat, ok := c.getOwnChat(id)
+	if !ok {
+		return
+	}
+
+	success, err := object.UnshareChat(chat)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// GetSharedChat
+// @Title GetSharedChat
+// @Tag Chat API
+// @Description get the read-only conversation of a share link, signing in is not required
+// @Param shareId query string true "The share id of the chat"
+// @Success 200 {object} object.ChatExport The Response object
+// @router /get-shared-chat [get]
+func (c *ApiController) GetSharedChat() {
+	shareId := c.Input().Get("shareId")
+
+	chatExport, err := object.GetSharedChatExport(shareId)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(chatExport)
+}
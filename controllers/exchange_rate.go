@@ -0,0 +1,182 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// GetExchangeRates
+// @Title GetExchangeRates
+// @Tag ExchangeRate API
+// @Description get exchange rates
+// @Param owner query string true "The owner of exchange rates"
+// @Success 200 {array} object.ExchangeRate The Response object
+// @router /get-exchange-rates [get]
+func (c *ApiController) GetExchangeRates() {
+	owner := c.Input().Get("owner")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if limit == "" || page == "" {
+		exchangeRates, err := object.GetExchangeRates(owner)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+		c.ResponseOk(exchangeRates)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetExchangeRateCount(owner, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		exchangeRates, err := object.GetPaginationExchangeRates(owner, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(exchangeRates, paginator.Nums())
+	}
+}
+
+// GetExchangeRate
+// @Title GetExchangeRate
+// @Tag ExchangeRate API
+// @Description get exchange rate
+// @Param id query string true "The id of exchange rate"
+// @Success 200 {object} object.ExchangeRate The Response object
+// @router /get-exchange-rate [get]
+func (c *ApiController) GetExchangeRate() {
+	id := c.Input().Get("id")
+
+	res, err := object.GetExchangeRate(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(res)
+}
+
+// UpdateExchangeRate
+// @Title UpdateExchangeRate
+// @Tag ExchangeRate API
+// @Description update exchange rate
+// @Param id query string true "The id (owner/name) of the exchange rate"
+// @Param body body object.ExchangeRate true "The details of the exchange rate"
+// @Success 200 {object} controllers.Response The Response object
+// @router /update-exchange-rate [post]
+func (c *ApiController) UpdateExchangeRate() {
+	id := c.Input().Get("id")
+
+	var exchangeRate object.ExchangeRate
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.UpdateExchangeRate(id, &exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// AddExchangeRate
+// @Title AddExchangeRate
+// @Tag ExchangeRate API
+// @Description add exchange rate
+// @Param body body object.ExchangeRate true "The details of the exchange rate"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-exchange-rate [post]
+func (c *ApiController) AddExchangeRate() {
+	var exchangeRate object.ExchangeRate
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.AddExchangeRate(&exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// DeleteExchangeRate
+// @Title DeleteExchangeRate
+// @Tag ExchangeRate API
+// @Description delete exchange rate
+// @Param body body object.ExchangeRate true "The details of the exchange rate"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-exchange-rate [post]
+func (c *ApiController) DeleteExchangeRate() {
+	var exchangeRate object.ExchangeRate
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.DeleteExchangeRate(&exchangeRate)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// ConvertPrice
+// @Title ConvertPrice
+// @Tag ExchangeRate API
+// @Description convert a price to the target currency with the exchange rates
+// @Param price query string true "The price"
+// @Param currency query string true "The currency of the price"
+// @Param targetCurrency query string true "The target currency"
+// @Success 200 {object} controllers.Response The Response object
+// @router /convert-price [get]
+func (c *ApiController) ConvertPrice() {
+	price := util.ParseFloat(c.Input().Get("price"))
+	currency := c.Input().Get("currency")
+	targetCurrency := c.Input().Get("targetCurrency")
+
+	res, err := object.ConvertPrice(price, currency, targetCurrency)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(res)
+}
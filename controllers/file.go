@@ -148,3 +148,41 @@ func (c *ApiController) DeleteFile() {
 
 	c.ResponseOk(res)
 }
+
+// MoveFile
+// @Title MoveFile
+// @Tag File API
+// @Description move or rename file
+// @Param store query string true "The store of the file"
+// @Param key query string true "The key of the file"
+// @Param newKey query string true "The new key of the file"
+// @Param isLeaf query string true "if is leaf"
+// @Success 200 {object} controllers.Response The Response object
+// @router /move-file [post]
+func (c *ApiController) MoveFile() {
+	userName, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	storeId := c.Input().Get("store")
+	key := c.Input().Get("key")
+	newKey := c.Input().Get("newKey")
+	isLeaf := c.Input().Get("isLeaf") == "1"
+
+	res, err := object.MoveFile(storeId, key, newKey, isLeaf)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if res {
+		err = addRecordForFile(c, userName, "Move", storeId, key, "", isLeaf)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+
+	c.ResponseOk(res)
+}
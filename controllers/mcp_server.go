@@ -0,0 +1,402 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+const mcpStoreFileUriTemplate = "casibase://stores/{store}/files/{+file}"
+
+// mcpIdentity is who calls the MCP server: the API key of a store can only reach that store and reads the files
+// as an anonymous user, while a signed-in user can reach all the stores and reads the files permitted to the user
+type mcpIdentity struct {
+	User  *casdoorsdk.User
+	Store string
+}
+
+type mcpStoreRequest struct {
+	Store string `json:"store,omitempty" description:"The name of the store, the store of the API key or the default store if empty"`
+}
+
+type mcpSearchStoreRequest struct {
+	Store string `json:"store,omitempty" description:"The name of the store, the store of the API key or the default store if empty"`
+	Query string `json:"query" description:"What to search for, in natural language"`
+}
+
+type mcpAskStoreRequest struct {
+	Store    string `json:"store,omitempty" description:"The name of the store, the store of the API key or the default store if empty"`
+	Question string `json:"question" description:"The question to answer with the knowledge of the store"`
+}
+
+type mcpStoreInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type mcpStoreFile struct {
+	File string `json:"file"`
+	Uri  string `json:"uri"`
+}
+
+// getMcpStoreFileUri returns the URI of a store file, the path segments are escaped so that the URI matches the template
+func getMcpStoreFileUri(storeName string, file string) string {
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("casibase://stores/%s/files/%s", url.PathEscape(storeName), strings.Join(segments, "/"))
+}
+
+// getMcpResourceArgument returns the value of a variable matched in the URI of a resource template
+func getMcpResourceArgument(request *protocol.ReadResourceRequest, name string) string {
+	switch value := request.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		return strings.Join(value, ",")
+	default:
+		return ""
+	}
+}
+
+// getMcpIdentity authenticates the request by the API key of a store in the bearer token, or by the session user
+func (c *ApiController) getMcpIdentity() (*mcpIdentity, bool) {
+	apiKey := strings.TrimPrefix(c.Ctx.Request.Header.Get("Authorization"), "Bearer ")
+	if apiKey != "" {
+		store, err := object.GetStoreByApiKey(apiKey)
+		if err != nil {
+			c.responseMcpError(http.StatusInternalServerError, err.Error())
+			return nil, false
+		}
+		if store != nil {
+			return &mcpIdentity{Store: store.Name}, true
+		}
+	}
+
+	user := c.GetSessionUser()
+	if user != nil {
+		return &mcpIdentity{User: user}, true
+	}
+
+	c.responseMcpError(http.StatusUnauthorized, "Authentication failed: the API key of a store is required, expected 'Bearer API_KEY'")
+	return nil, false
+}
+
+func (c *ApiController) responseMcpError(status int, message string) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error":   map[string]interface{}{"code": -32000, "message": message},
+		"id":      nil,
+	}
+	c.ServeJSON()
+}
+
+// getStore returns the store of the name that the identity can reach
+func (identity *mcpIdentity) getStore(storeName string) (*object.Store, error) {
+	if storeName == "" {
+		storeName = identity.Store
+	}
+	if identity.Store != "" && storeName != identity.Store {
+		return nil, fmt.Errorf("the API key can't access the store: %s", storeName)
+	}
+
+	var store *object.Store
+	var err error
+	if storeName == "" {
+		store, err = object.GetDefaultStore("admin")
+	} else {
+		store, err = object.GetStore(util.GetId("admin", storeName))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("the store: %s is not found", storeName)
+	}
+	return store, nil
+}
+
+func newMcpTextResult(text string, isError bool) *protocol.CallToolResult {
+	return protocol.NewCallToolResult([]protocol.Content{&protocol.TextContent{Type: "text", Text: text}}, isError)
+}
+
+func newMcpJsonResult(data interface{}) (*protocol.CallToolResult, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return newMcpTextResult(string(dataBytes), false), nil
+}
+
+// registerMcpTool registers a tool whose errors are returned to the agent as error results
+func registerMcpTool(s *server.Server, name string, description string, request interface{}, handler func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error)) error {
+	tool, err := protocol.NewTool(name, description, request)
+	if err != nil {
+		return err
+	}
+
+	s.RegisterTool(tool, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil {
+			return newMcpTextResult(err.Error(), true), nil
+		}
+		return result, nil
+	})
+	return nil
+}
+
+func (c *ApiController) newMcpServer(identity *mcpIdentity, serverTransport transport.ServerTransport) (*server.Server, error) {
+	s, err := server.NewServer(
+		serverTransport,
+		server.WithServerInfo(protocol.Implementation{Name: "Casibase", Version: "1.0.0"}),
+		server.WithInstructions("Search the knowledge bases (stores) of Casibase, ask questions about them and read their files."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = registerMcpTool(s, "list_stores", "List the stores (knowledge bases) that can be searched.", struct{}{}, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return c.mcpListStores(identity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = registerMcpTool(s, "search_store", "Search the knowledge of a store and return the most relevant passages with their files and scores.", mcpSearchStoreRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return c.mcpSearchStore(identity, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = registerMcpTool(s, "ask_store", "Answer a question with the model and the knowledge of a store, like the chat of the store does.", mcpAskStoreRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return c.mcpAskStore(identity, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = registerMcpTool(s, "list_store_files", "List the files of a store with the URIs to read them as resources.", mcpStoreRequest{}, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return c.mcpListStoreFiles(identity, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTemplate := &protocol.ResourceTemplate{
+		Name:        "Store file",
+		URITemplate: mcpStoreFileUriTemplate,
+		Description: "The text of a file in a store",
+		MimeType:    "text/plain",
+	}
+	err = s.RegisterResourceTemplate(resourceTemplate, func(ctx context.Context, request *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+		return c.mcpReadStoreFile(identity, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (c *ApiController) mcpListStores(identity *mcpIdentity) (*protocol.CallToolResult, error) {
+	var stores []*object.Store
+	if identity.Store != "" {
+		store, err := identity.getStore(identity.Store)
+		if err != nil {
+			return nil, err
+		}
+		stores = []*object.Store{store}
+	} else {
+		var err error
+		stores, err = object.GetGlobalStores()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := []*mcpStoreInfo{}
+	for _, store := range stores {
+		res = append(res, &mcpStoreInfo{Name: store.Name, DisplayName: store.DisplayName})
+	}
+	return newMcpJsonResult(res)
+}
+
+func (c *ApiController) mcpSearchStore(identity *mcpIdentity, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var req mcpSearchStoreRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := identity.getStore(req.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	_, vectorScores, err := object.SearchStoreKnowledge(store, req.Query, identity.User)
+	if err != nil {
+		return nil, err
+	}
+
+	citations, err := getOpenAiCitations(vectorScores)
+	if err != nil {
+		return nil, err
+	}
+	return newMcpJsonResult(citations)
+}
+
+func (c *ApiController) mcpAskStore(identity *mcpIdentity, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var req mcpAskStoreRequest
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := identity.getStore(req.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	question, guardrailEvents, isBlocked, err := object.ApplyGuardrails(store.Guardrails, object.GuardrailStageInput, req.Question)
+	if err != nil {
+		return nil, err
+	}
+	if isBlocked {
+		return newMcpTextResult(object.GetGuardrailBlockedText(object.GuardrailStageInput), true), nil
+	}
+
+	knowledge, _, err := object.SearchStoreKnowledge(store, question, identity.User)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, modelResult, err := object.GetAnswerWithContext(store.ModelProvider, question, nil, knowledge, store.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A blocked answer is returned as an error result with the blocked text
+	answer, _, outputEvents, isBlocked, err := applyOutputGuardrails(store, answer, "")
+	if err != nil {
+		return nil, err
+	}
+	guardrailEvents = append(guardrailEvents, outputEvents...)
+
+	answerMessage := &object.Message{
+		Text:            answer,
+		TokenCount:      modelResult.TotalTokenCount,
+		Price:           modelResult.TotalPrice,
+		Currency:        modelResult.Currency,
+		GuardrailEvents: guardrailEvents,
+	}
+	err = c.addOpenAiApiUsage(fmt.Sprintf("mcp_%s", store.Name), store.Name, &object.Message{Text: question}, answerMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMcpTextResult(answer, isBlocked), nil
+}
+
+func (c *ApiController) mcpListStoreFiles(identity *mcpIdentity, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var req mcpStoreRequest
+	if len(request.RawArguments) != 0 {
+		err := json.Unmarshal(request.RawArguments, &req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := identity.getStore(req.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := object.GetStoreFiles(store, identity.User)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*mcpStoreFile{}
+	for _, file := range files {
+		res = append(res, &mcpStoreFile{File: file, Uri: getMcpStoreFileUri(store.Name, file)})
+	}
+	return newMcpJsonResult(res)
+}
+
+func (c *ApiController) mcpReadStoreFile(identity *mcpIdentity, request *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+	storeName := getMcpResourceArgument(request, "store")
+	file := getMcpResourceArgument(request, "file")
+
+	store, err := identity.getStore(storeName)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := object.GetStoreFileText(store, file, identity.User)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &protocol.TextResourceContents{URI: request.URI, MimeType: "text/plain", Text: text}
+	return protocol.NewReadResourceResult([]protocol.ResourceContents{content}), nil
+}
+
+// McpServer
+// @Title McpServer
+// @Tag MCP API
+// @Description the MCP server of Casibase over the streamable HTTP transport, it exposes the tools list_stores, search_store, ask_store and list_store_files and the store files as resources, authenticated by the API key of a store or the session user
+// @Success 200 {object} controllers.Response The Response object
+// @router /mcp [post]
+func (c *ApiController) McpServer() {
+	identity, ok := c.getMcpIdentity()
+	if !ok {
+		return
+	}
+
+	// The server is stateless, so it is created for each request with the identity of the request
+	serverTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		c.responseMcpError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_, err = c.newMcpServer(identity, serverTransport)
+	if err != nil {
+		c.responseMcpError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The request body has been read by beego already
+	request := c.Ctx.Request
+	request.Body = io.NopCloser(bytes.NewReader(c.Ctx.Input.RequestBody))
+	handler.HandleMCP().ServeHTTP(c.Ctx.ResponseWriter, request)
+}
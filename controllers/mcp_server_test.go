@@ -0,0 +1,136 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/casibase/casibase/object"
+)
+
+func newTestMcpClient(t *testing.T, identity *mcpIdentity) *client.Client {
+	serverTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ApiController{}
+	s, err := c.newMcpServer(identity, serverTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Run()
+
+	httpServer := httptest.NewServer(handler.HandleMCP())
+	t.Cleanup(httpServer.Close)
+
+	clientTransport, err := transport.NewStreamableHTTPClientTransport(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := client.NewClient(clientTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cli.Close() })
+	return cli
+}
+
+func TestMcpServerStoreAccess(t *testing.T) {
+	cli := newTestMcpClient(t, &mcpIdentity{Store: "store-a"})
+
+	toolsResult, err := cli.ListTools(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, tool := range toolsResult.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "ask_store,list_store_files,list_stores,search_store" {
+		t.Fatalf("unexpected tools: %v", names)
+	}
+
+	// The API key of a store can't reach another store, which fails before anything is read
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"search_store", map[string]interface{}{"store": "store-b", "query": "refunds"}},
+		{"ask_store", map[string]interface{}{"store": "store-b", "question": "What is the refund policy?"}},
+		{"list_store_files", map[string]interface{}{"store": "store-b"}},
+	}
+	for _, test := range tests {
+		result, err := cli.CallTool(context.Background(), protocol.NewCallToolRequest(test.name, test.arguments))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text := result.Content[0].(*protocol.TextContent).Text
+		if !result.IsError || !strings.Contains(text, "can't access the store: store-b") {
+			t.Errorf("%s() = %q (error: %v), want an access error", test.name, text, result.IsError)
+		}
+	}
+}
+
+func TestMcpIdentityGetStore(t *testing.T) {
+	identity := &mcpIdentity{Store: "store-a"}
+	_, err := identity.getStore("store-b")
+	if err == nil {
+		t.Fatal("getStore() of another store should fail for the API key of a store")
+	}
+}
+
+func TestMcpAskStoreOutputGuardrails(t *testing.T) {
+	store := &object.Store{
+		Guardrails: []object.Guardrail{
+			{Name: "pii", Type: object.GuardrailTypePii, Stage: object.GuardrailStageOutput, Action: object.GuardrailActionMask, IsEnabled: true},
+			{Name: "secrets", Type: object.GuardrailTypeBlocklist, Stage: object.GuardrailStageOutput, Action: object.GuardrailActionBlock, Keywords: []string{"password"}, IsEnabled: true},
+		},
+	}
+
+	tests := []struct {
+		answer    string
+		want      string
+		isBlocked bool
+	}{
+		{"Contact alice@example.com for refunds.", "Contact [EMAIL] for refunds.", false},
+		{"The admin password is 123.", object.GetGuardrailBlockedText(object.GuardrailStageOutput), true},
+	}
+	for _, test := range tests {
+		answer, _, events, isBlocked, err := applyOutputGuardrails(store, test.answer, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if answer != test.want || isBlocked != test.isBlocked || len(events) != 1 {
+			t.Errorf("applyOutputGuardrails(%q) = %q, %v, %d events, want %q, %v, 1 event", test.answer, answer, isBlocked, len(events), test.want, test.isBlocked)
+		}
+	}
+}
+
+func TestGetMcpStoreFileUri(t *testing.T) {
+	uri := getMcpStoreFileUri("store a", "docs/年报 2025.pdf")
+	if uri != "casibase://stores/store%20a/files/docs/%E5%B9%B4%E6%8A%A5%202025.pdf" {
+		t.Errorf("getMcpStoreFileUri() = %q", uri)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// GetMemories
+// @Title GetMemories
+// @Tag Memory API
+// @Description get memories, the memories of all the users are returned to the admin if the user is empty, other callers always get their own memories
+// @Param user query string false "The user of memories"
+// @Success 200 {array} object.Memory The Response object
+// @router /get-memories [get]
+func (c *ApiController) GetMemories() {
+	user := c.Input().Get("user")
+
+	if user == "" {
+		if c.IsAdmin() {
+			memories, err := object.GetGlobalMemories()
+			if err != nil {
+				c.ResponseError(err.Error())
+				return
+			}
+
+			c.ResponseOk(memories)
+			return
+		}
+
+		user = c.getMemoryUsername()
+	}
+
+	if user == "" {
+		c.ResponseError("Please sign in first")
+		return
+	}
+
+	ok := c.IsCurrentUser(user)
+	if !ok {
+		return
+	}
+
+	memories, err := object.GetMemories("admin", user)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(memories)
+}
+
+// getMemoryUsername returns the signed-in user, or the anonymous identity of the client if not signed in
+func (c *ApiController) getMemoryUsername() string {
+	username := c.GetSessionUsername()
+	if username == "" {
+		username = c.getAnonymousUsername()
+	}
+	return username
+}
+
+// getCurrentUserMemory returns the memory of the id if it belongs to the current user
+func (c *ApiController) getCurrentUserMemory(id string) (*object.Memory, bool) {
+	memory, err := object.GetMemory(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if memory == nil {
+		c.ResponseError(fmt.Sprintf("The memory: %s is not found", id))
+		return nil, false
+	}
+
+	ok := c.IsCurrentUser(memory.User)
+	if !ok {
+		return nil, false
+	}
+
+	return memory, true
+}
+
+// GetMemory
+// @Title GetMemory
+// @Tag Memory API
+// @Description get memory
+// @Param id query string true "The id of memory"
+// @Success 200 {object} object.Memory The Response object
+// @router /get-memory [get]
+func (c *ApiController) GetMemory() {
+	id := c.Input().Get("id")
+
+	memory, ok := c.getCurrentUserMemory(id)
+	if !ok {
+		return
+	}
+
+	c.ResponseOk(memory)
+}
+
+// UpdateMemory
+// @Title UpdateMemory
+// @Tag Memory API
+// @Description update memory
+// @Param id query string true "The id (owner/name) of the memory"
+// @Param body body object.Memory true "The details of the memory"
+// @Success 200 {object} controllers.Response The Response object
+// @router /update-memory [post]
+func (c *ApiController) UpdateMemory() {
+	id := c.Input().Get("id")
+
+	var memory object.Memory
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &memory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	originalMemory, ok := c.getCurrentUserMemory(id)
+	if !ok {
+		return
+	}
+	memory.User = originalMemory.User
+
+	success, err := object.UpdateMemory(id, &memory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// AddMemory
+// @Title AddMemory
+// @Tag Memory API
+// @Description add memory
+// @Param body body object.Memory true "The details of the memory"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-memory [post]
+func (c *ApiController) AddMemory() {
+	var memory object.Memory
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &memory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if memory.User == "" {
+		memory.User = c.getMemoryUsername()
+	}
+	if memory.User == "" {
+		c.ResponseError("The user of the memory should not be empty")
+		return
+	}
+
+	ok := c.IsCurrentUser(memory.User)
+	if !ok {
+		return
+	}
+
+	memory.Owner = "admin"
+	if memory.Name == "" {
+		memory.Name = fmt.Sprintf("memory_%s", util.GetRandomName())
+	}
+	memory.CreatedTime = util.GetCurrentTime()
+	memory.UpdatedTime = memory.CreatedTime
+
+	success, err := object.AddMemory(&memory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// DeleteMemory
+// @Title DeleteMemory
+// @Tag Memory API
+// @Description delete memory
+// @Param body body object.Memory true "The details of the memory"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-memory [post]
+func (c *ApiController) DeleteMemory() {
+	var memory object.Memory
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &memory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	originalMemory, ok := c.getCurrentUserMemory(memory.GetId())
+	if !ok {
+		return
+	}
+
+	success, err := object.DeleteMemory(originalMemory)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
@@ -100,7 +100,7 @@ func (c *ApiController) GetMessages() {
 		return
 	}
 
-	messages, err := object.GetChatMessages(chat)
+	messages, err := object.GetChatBranchMessages(chat)
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -197,18 +197,38 @@ func (c *ApiController) AddMessage() {
 		c.ResponseError(err.Error())
 		return
 	}
-	// if originMessage not nil, means edit message, delete all later messages
+
+	// An edited question is added as a new branch beside the original question, and a question sent again as it
+	// is only gets a new answer beside the original answers, so that the previous branches are kept
+	var regeneratedQuestion *object.Message
 	if originMessage != nil {
-		err = object.DeleteAllLaterMessages(id)
-		if err != nil {
-			c.ResponseError(err.Error())
+		if originMessage.Author == "AI" {
+			c.ResponseError(fmt.Sprintf("The message: %s is not a question", id))
 			return
 		}
+
+		if originMessage.Text == message.Text {
+			err = object.DeleteFailedReplies(originMessage)
+			if err != nil {
+				c.ResponseError(err.Error())
+				return
+			}
+
+			regeneratedQuestion = originMessage
+		} else {
+			message.ParentMessage, err = object.GetMessageParent(originMessage)
+			if err != nil {
+				c.ResponseError(err.Error())
+				return
+			}
+
+			message.Name = fmt.Sprintf("message_%s", util.GetRandomName())
+		}
 	}
 
 	addMessageAfterSuccess := true
 	if message.IsRegenerated {
-		messages, err := object.GetChatMessages(message.Chat)
+		messages, err := object.GetChatBranchMessages(message.Chat)
 		if err != nil {
 			c.ResponseError(err.Error())
 			return
@@ -235,6 +255,18 @@ func (c *ApiController) AddMessage() {
 				break
 			}
 		}
+
+		// The regenerated message takes the place of the deleted messages in the branch
+		if lastUserMessage != nil {
+			message.ParentMessage, err = object.GetMessageParent(lastUserMessage)
+		} else if lastAIMessage != nil {
+			message.ParentMessage, err = object.GetMessageParent(lastAIMessage)
+		}
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
 		if lastAIMessage != nil {
 			if lastAIMessage.ReplyTo == "Welcome" {
 				message.Author = "AI"
@@ -279,25 +311,31 @@ func (c *ApiController) AddMessage() {
 		}
 	}
 
-	host := c.Ctx.Request.Host
-	origin := getOriginFromHost(host)
-	err = object.RefineMessageFiles(&message, origin)
-	if err != nil {
-		c.ResponseError(err.Error())
-		return
-	}
+	var success bool
+	if regeneratedQuestion != nil {
+		message = *regeneratedQuestion
+		success = true
+	} else {
+		host := c.Ctx.Request.Host
+		origin := getOriginFromHost(host)
+		err = object.RefineMessageFiles(&message, origin)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
 
-	message.CreatedTime = util.GetCurrentTimeWithMilli()
+		message.CreatedTime = util.GetCurrentTimeWithMilli()
 
-	if message.Text == "" {
-		c.ResponseError(fmt.Sprintf("The question should not be empty for message: %v", message))
-		return
-	}
+		if message.Text == "" {
+			c.ResponseError(fmt.Sprintf("The question should not be empty for message: %v", message))
+			return
+		}
 
-	success, err := object.AddMessage(&message)
-	if err != nil {
-		c.ResponseError(err.Error())
-		return
+		success, err = object.AddMessage(&message)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
 	}
 
 	if success && addMessageAfterSuccess {
@@ -317,6 +355,7 @@ func (c *ApiController) AddMessage() {
 				User:          message.User,
 				Chat:          message.Chat,
 				ReplyTo:       message.Name,
+				ParentMessage: message.Name,
 				Author:        "AI",
 				Text:          "",
 				FileName:      message.FileName,
@@ -334,6 +373,46 @@ func (c *ApiController) AddMessage() {
 	c.ResponseOk(chat)
 }
 
+// SwitchMessageBranch
+// @Title SwitchMessageBranch
+// @Tag Message API
+// @Description switch the chat of the message to the branch of the message
+// @Param body body object.Message true "The details of the message"
+// @Success 200 {object} controllers.Response The Response object
+// @router /switch-message-branch [post]
+func (c *ApiController) SwitchMessageBranch() {
+	var message object.Message
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &message)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	id := util.GetIdFromOwnerAndName(message.Owner, message.Name)
+	branchMessage, err := object.GetMessage(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if branchMessage == nil {
+		c.ResponseError(fmt.Sprintf("The message: %s is not found", id))
+		return
+	}
+
+	ok := c.IsCurrentUser(branchMessage.User)
+	if !ok {
+		return
+	}
+
+	success, err := object.SwitchMessageBranch(branchMessage)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
 // DeleteMessage
 // @Title DeleteMessage
 // @Tag Message API
@@ -15,6 +15,7 @@
 package controllers
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -97,6 +98,7 @@ func (c *ApiController) GetMessageAnswer() {
 	}
 
 	question := store.Welcome
+	maskedQuestion := ""
 	var questionMessage *object.Message
 	if message.ReplyTo != "Welcome" {
 		questionMessage, err = object.GetMessage(util.GetId("admin", message.ReplyTo))
@@ -111,6 +113,28 @@ func (c *ApiController) GetMessageAnswer() {
 
 		question = questionMessage.Text
 
+		// The input guardrails run on the raw question, before its URLs are fetched or it reaches any provider
+		var guardrailEvents []object.GuardrailEvent
+		var isBlocked bool
+		question, guardrailEvents, isBlocked, err = object.ApplyGuardrails(store.Guardrails, object.GuardrailStageInput, question)
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+		if len(guardrailEvents) != 0 {
+			questionMessage.GuardrailEvents = append(questionMessage.GuardrailEvents, guardrailEvents...)
+			_, err = object.UpdateMessage(questionMessage.GetId(), questionMessage, false)
+			if err != nil {
+				c.ResponseErrorStream(message, err.Error())
+				return
+			}
+		}
+		if isBlocked {
+			c.ResponseBlockedStream(message, object.GetGuardrailBlockedText(object.GuardrailStageInput))
+			return
+		}
+		maskedQuestion = question
+
 		question, err = refineQuestionTextViaParsingUrlContent(question)
 		if err != nil {
 			c.ResponseErrorStream(message, err.Error())
@@ -154,7 +178,7 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
-	_, agentProviderObj, err := object.GetAgentProviderFromContext("admin", store.AgentProvider)
+	_, agentProviderObj, err := object.GetAgentProviderFromContext("admin", store.AgentProvider, c.GetSessionUser())
 	if err != nil {
 		c.ResponseErrorStream(message, err.Error())
 		return
@@ -165,13 +189,15 @@ func (c *ApiController) GetMessageAnswer() {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+	// The pooled MCP clients are released on every return, the Close() of QueryTextWithTools() then does nothing
+	defer agentClients.Close()
 
 	knowledgeCount := store.KnowledgeCount
 	if knowledgeCount <= 0 {
 		knowledgeCount = 10
 	}
 
-	knowledge, vectorScores, embeddingResult, err := object.GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount)
+	knowledge, vectorScores, embeddingResult, err := object.GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount, c.GetSessionUser())
 	if err != nil && err.Error() != "no knowledge vectors found" {
 		err = fmt.Errorf("object.GetNearestKnowledge() error, %s", err.Error())
 		c.ResponseErrorStream(message, err.Error())
@@ -181,7 +207,20 @@ func (c *ApiController) GetMessageAnswer() {
 		embeddingResult = &embedding.EmbeddingResult{}
 	}
 
-	writer := &RefinedWriter{*c.Ctx.ResponseWriter, *NewCleaner(6), []byte{}, []byte{}, []byte{}}
+	if questionMessage != nil {
+		var memories []*model.RawMessage
+		memories, err = object.GetRelevantMemories(store, chat.User, question)
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+		knowledge = append(memories, knowledge...)
+	}
+
+	writer := newRefinedWriter(*c.Ctx.ResponseWriter)
+	// The answer is held back until the output guardrails have checked it, so that the client never gets the
+	// text they mask or block
+	writer.isBuffered = object.HasGuardrails(store.Guardrails, object.GuardrailStageOutput)
 
 	if questionMessage != nil {
 		questionMessage.TokenCount = embeddingResult.TokenCount
@@ -195,11 +234,12 @@ func (c *ApiController) GetMessageAnswer() {
 		}
 	}
 
-	history, err := object.GetRecentRawMessages(chat.Name, message.CreatedTime, store.MemoryLimit)
+	history, err := object.GetChatMemory(store, chat, message)
 	if err != nil {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+	object.MaskHistoryPii(store.Guardrails, history)
 
 	fmt.Printf("Question: [%s]\n", question)
 	fmt.Printf("Knowledge: [\n")
@@ -211,30 +251,43 @@ func (c *ApiController) GetMessageAnswer() {
 	fmt.Printf("Answer: [")
 
 	if modelProvider.Type != "Dummy" && !isReasonModel(modelProvider.SubType) {
-		question, err = getQuestionWithCarriers(question, store.SuggestionCount, chat.NeedTitle)
+		question, err = getQuestionWithCarriers(question, store.SuggestionCount, chat.NeedTitle, store.Carriers)
 	}
 	if err != nil {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+	ctx, release := c.newMessageAnswerContext(message.GetId())
+	defer release()
+
 	var modelResult *model.ModelResult
+	message.AgentSteps = nil
+	message.ToolApprovals = nil
 	if agentClients != nil {
 		messages := &model.AgentMessages{
 			Messages:  []*model.RawMessage{},
 			ToolCalls: nil,
 		}
 		agentInfo := &model.AgentInfo{
-			AgentClients:  agentClients,
-			AgentMessages: messages,
+			AgentClients:    agentClients,
+			AgentMessages:   messages,
+			ApproveToolCall: c.newToolApprover(message),
+			OnAgentStep:     c.sendAgentStep,
 		}
-		modelResult, err = model.QueryTextWithTools(modelProviderObj, question, writer, history, store.Prompt, knowledge, agentInfo)
+		modelResult, err = model.QueryTextWithTools(ctx, modelProviderObj, question, writer, history, store.Prompt, knowledge, agentInfo, nil)
+		message.AgentSteps = agentInfo.AgentSteps
 	} else {
 		if isReasonModel(modelProvider.SubType) {
-			modelResult, err = QueryCarrierText(question, writer, history, store.Prompt, knowledge, modelProviderObj, chat.NeedTitle, store.SuggestionCount)
+			modelResult, err = QueryCarrierText(ctx, question, writer, history, store.Prompt, knowledge, modelProviderObj, chat.NeedTitle, store.SuggestionCount, store.Carriers)
 		} else {
-			modelResult, err = modelProviderObj.QueryText(question, writer, history, store.Prompt, knowledge, nil)
+			modelResult, err = modelProviderObj.QueryText(ctx, question, writer, history, store.Prompt, knowledge, nil, nil)
 		}
 	}
+	if ctx.Err() != nil {
+		fmt.Printf("]\n")
+		c.saveStoppedAnswer(message, chat, store, writer, modelProvider, question, modelResult)
+		return
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "write tcp") {
 			c.ResponseError(err.Error())
@@ -244,7 +297,7 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
-	if writer.writerCleaner.cleaned == false {
+	if !writer.isBuffered && writer.writerCleaner.cleaned == false {
 		cleanedData := writer.writerCleaner.GetCleanedData()
 		writer.buf = append(writer.buf, []byte(cleanedData)...)
 		jsonData, err := ConvertMessageDataToJSON(cleanedData)
@@ -265,6 +318,19 @@ func (c *ApiController) GetMessageAnswer() {
 
 	fmt.Printf("]\n")
 
+	answer, reasonText, guardrailEvents, isBlocked, err := applyOutputGuardrails(store, writer.MessageString(), writer.ReasonString())
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+	if writer.isBuffered {
+		err = writer.writeBufferedEvents(reasonText, answer)
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+	}
+
 	event := fmt.Sprintf("event: end\ndata: %s\n\n", "end")
 	_, err = c.Ctx.ResponseWriter.Write([]byte(event))
 	if err != nil {
@@ -272,20 +338,28 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
-	answer := writer.MessageString()
-	message.ReasonText = writer.ReasonString()
+	message.ReasonText = reasonText
 	message.TokenCount = modelResult.TotalTokenCount
 	message.Price = modelResult.TotalPrice
 	message.Currency = modelResult.Currency
+	message.UsedModelProvider = modelProvider.Name
+	if modelResult.Provider != "" {
+		message.UsedModelProvider = modelResult.Provider
+	}
 
 	textAnswer := answer
 	textSuggestions := []object.Suggestion{}
 	textTitle := ""
-	textAnswer, textSuggestions, textTitle, err = parseAnswerWithCarriers(answer, store.SuggestionCount, chat.NeedTitle)
+	var carrierValues map[string]string
+	textAnswer, textSuggestions, textTitle, carrierValues, err = parseAnswerWithCarriers(answer, store.SuggestionCount, chat.NeedTitle, store.Carriers)
 	if err != nil {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+	if isBlocked {
+		textSuggestions = []object.Suggestion{}
+	}
+	message.GuardrailEvents = append(message.GuardrailEvents, guardrailEvents...)
 
 	message.Text = textAnswer
 	if message.Text != "" {
@@ -294,6 +368,7 @@ func (c *ApiController) GetMessageAnswer() {
 	}
 
 	message.Suggestions = textSuggestions
+	message.CarrierValues = carrierValues
 
 	message.VectorScores = vectorScores
 	_, err = object.UpdateMessage(message.GetId(), message, false)
@@ -302,10 +377,32 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
-	chat.TokenCount += message.TokenCount
-	chat.Price += message.Price
-	if chat.Currency == "" {
-		chat.Currency = message.Currency
+	// Only the added price is converted here and the counts are added by the database, so that the costs added by the
+	// memories and the summary in the background meanwhile are kept
+	usage := &object.Chat{Owner: chat.Owner, Name: chat.Name, Currency: chat.Currency}
+	usage.TokenCount += message.TokenCount
+	_, err = addChatPrice(usage, message.Price, message.Currency)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+
+	if questionMessage != nil {
+		var added bool
+		added, err = addChatPrice(usage, questionMessage.Price, questionMessage.Currency)
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+		if added {
+			usage.TokenCount += questionMessage.TokenCount
+		}
+	}
+
+	_, err = object.AddChatUsage(chat.GetId(), 0, usage.TokenCount, usage.Price, usage.Currency)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
 	}
 
 	if chat.NeedTitle && textTitle != "" {
@@ -313,18 +410,76 @@ func (c *ApiController) GetMessageAnswer() {
 		chat.NeedTitle = false
 	}
 
-	if questionMessage != nil {
-		if chat.Currency == questionMessage.Currency {
-			chat.TokenCount += questionMessage.TokenCount
-			chat.Price += questionMessage.Price
-		}
-	}
-
 	_, err = object.UpdateChat(chat.GetId(), chat)
 	if err != nil {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+
+	// The memories are extracted and the chat is summarized aside, so that the answer is not held back by them
+	go func() {
+		summaryErr := object.UpdateChatSummary(store, message)
+		if summaryErr != nil {
+			fmt.Printf("UpdateChatSummary() error: %s\n", summaryErr.Error())
+		}
+
+		if store.EnableMemory && questionMessage != nil {
+			memoryErr := object.ExtractMemories(store, message, maskedQuestion, message.Text)
+			if memoryErr != nil {
+				fmt.Printf("ExtractMemories() error: %s\n", memoryErr.Error())
+			}
+		}
+	}()
+}
+
+// applyOutputGuardrails runs the output guardrails on the raw answer and its reasoning, a blocked answer is replaced
+// by the blocked text and loses its reasoning
+func applyOutputGuardrails(store *object.Store, answer string, reasonText string) (string, string, []object.GuardrailEvent, bool, error) {
+	answer, events, isBlocked, err := object.ApplyGuardrails(store.Guardrails, object.GuardrailStageOutput, answer)
+	if err != nil {
+		return "", "", nil, false, err
+	}
+	if isBlocked {
+		return object.GetGuardrailBlockedText(object.GuardrailStageOutput), "", events, true, nil
+	}
+	if reasonText == "" {
+		return answer, reasonText, events, false, nil
+	}
+
+	reasonText, reasonEvents, isBlocked, err := object.ApplyGuardrails(store.Guardrails, object.GuardrailStageOutput, reasonText)
+	if err != nil {
+		return "", "", nil, false, err
+	}
+	events = append(events, reasonEvents...)
+	if isBlocked {
+		return object.GetGuardrailBlockedText(object.GuardrailStageOutput), "", events, true, nil
+	}
+	return answer, reasonText, events, false, nil
+}
+
+// addChatPrice adds the price to the chat, a price with no exchange rate to the currency of the chat is left out and
+// logged, so that the chat total never mixes currencies
+func addChatPrice(chat *object.Chat, price float64, currency string) (bool, error) {
+	added, err := object.AddChatPrice(chat, price, currency)
+	if err != nil {
+		return false, err
+	}
+	if !added && price != 0 {
+		fmt.Printf("addChatPrice() error: no exchange rate from %s to %s, the price %f is left out of the chat: %s\n", currency, chat.Currency, price, chat.GetId())
+	}
+	return added, nil
+}
+
+// addChatCost adds the tokens and the price of the message to the chat in one UPDATE
+func addChatCost(chat *object.Chat, tokenCount int, price float64, currency string) error {
+	added, err := object.AddChatCost(chat, tokenCount, price, currency)
+	if err != nil {
+		return err
+	}
+	if !added && price != 0 {
+		fmt.Printf("addChatCost() error: no exchange rate from %s to %s, the price %f is left out of the chat: %s\n", currency, chat.Currency, price, chat.GetId())
+	}
+	return nil
 }
 
 // GetAnswer
@@ -459,16 +614,8 @@ func (c *ApiController) GetAnswer() {
 		return
 	}
 
-	chat.TokenCount += answerMessage.TokenCount
-	chat.Price += answerMessage.Price
-	if chat.Currency == "" {
-		chat.Currency = answerMessage.Currency
-	}
-
-	chat.UpdatedTime = util.GetCurrentTime()
-	chat.MessageCount += 2
-
-	_, err = object.UpdateChat(chat.GetId(), chat)
+	// The two messages are already counted by AddMessage()
+	err = addChatCost(chat, answerMessage.TokenCount, answerMessage.Price, answerMessage.Currency)
 	if err != nil {
 		c.ResponseOk(err.Error())
 		return
@@ -476,3 +623,18 @@ func (c *ApiController) GetAnswer() {
 
 	c.ResponseOk(answer)
 }
+
+// sendAgentStep sends a "step" event to the client when a tool call of the agent is done
+func (c *ApiController) sendAgentStep(step *model.AgentStep) error {
+	jsonData, err := json.Marshal(step)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Ctx.ResponseWriter.Write([]byte(fmt.Sprintf("event: step\ndata: %s\n\n", jsonData)))
+	if err != nil {
+		return err
+	}
+	c.Ctx.ResponseWriter.Flush()
+	return nil
+}
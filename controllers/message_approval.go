@@ -0,0 +1,125 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/object"
+)
+
+const (
+	toolApprovalTimeout      = 10 * time.Minute
+	toolApprovalPollInterval = time.Second
+)
+
+// newToolApprover returns the approver of the tool calls in the answer of the message: it stores the approval on the
+// message, sends an "approval" event with the arguments to the client and waits until the user decides via
+// ApproveToolCall(), the answer is stopped or the approval times out, which rejects the call. The decision is read
+// from the database, so it can be made on any instance
+func (c *ApiController) newToolApprover(message *object.Message) func(ctx context.Context, approval *model.ToolApproval) (bool, error) {
+	return func(ctx context.Context, approval *model.ToolApproval) (bool, error) {
+		err := object.AddMessageToolApproval(message, approval)
+		if err != nil {
+			return false, err
+		}
+
+		jsonData, err := json.Marshal(approval)
+		if err != nil {
+			return false, err
+		}
+
+		_, err = c.Ctx.ResponseWriter.Write([]byte(fmt.Sprintf("event: approval\ndata: %s\n\n", jsonData)))
+		if err != nil {
+			return false, err
+		}
+		c.Ctx.ResponseWriter.Flush()
+
+		ticker := time.NewTicker(toolApprovalPollInterval)
+		defer ticker.Stop()
+		timeout := time.After(toolApprovalTimeout)
+		for {
+			select {
+			case <-ticker.C:
+				state, err := object.GetMessageToolApprovalState(message.GetId(), approval.ToolCallId)
+				if err != nil {
+					return false, err
+				}
+				if state == model.ToolApprovalStateApproved || state == model.ToolApprovalStateRejected {
+					approval.State = state
+					return state == model.ToolApprovalStateApproved, nil
+				}
+			case <-ctx.Done():
+				// The stopped answer is saved with the approval
+				approval.State = model.ToolApprovalStateRejected
+				return false, ctx.Err()
+			case <-timeout:
+				// A decision made right before the timeout wins over it
+				state, err := object.ExpireMessageToolApproval(message.GetId(), approval.ToolCallId)
+				if err != nil {
+					return false, err
+				}
+				approval.State = state
+				return state == model.ToolApprovalStateApproved, nil
+			}
+		}
+	}
+}
+
+// ApproveToolCall
+// @Title ApproveToolCall
+// @Tag Message API
+// @Description approve or reject a tool call that the answer of the message is waiting for
+// @Param id query string true "The id of message"
+// @Param toolCallId query string true "The id of the tool call"
+// @Param approved query bool true "Whether the tool call is approved"
+// @Success 200 {object} controllers.Response The Response object
+// @router /approve-tool-call [post]
+func (c *ApiController) ApproveToolCall() {
+	id := c.Input().Get("id")
+	toolCallId := c.Input().Get("toolCallId")
+	approved := c.Input().Get("approved") == "true"
+
+	message, err := object.GetMessage(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if message == nil {
+		c.ResponseError(fmt.Sprintf("The message: %s is not found", id))
+		return
+	}
+
+	ok := c.IsCurrentUser(message.User)
+	if !ok {
+		return
+	}
+
+	ok, err = object.DecideMessageToolApproval(id, toolCallId, approved)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if !ok {
+		c.ResponseError(fmt.Sprintf("The tool call: %s of the message: %s is not waiting for approval", toolCallId, id))
+		return
+	}
+
+	c.ResponseOk(approved)
+}
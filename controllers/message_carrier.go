@@ -15,6 +15,7 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -24,7 +25,28 @@ import (
 	"github.com/casibase/casibase/object"
 )
 
-func getQuestionWithCarriers(question string, suggestionCount int, needTitle bool) (string, error) {
+func getPromptCarriers(storeCarriers []object.StoreCarrier) ([]*carrier.PromptCarrier, error) {
+	promptCarriers := []*carrier.PromptCarrier{}
+	names := map[string]bool{}
+	for _, storeCarrier := range storeCarriers {
+		if !storeCarrier.IsEnabled {
+			continue
+		}
+		if names[storeCarrier.Name] {
+			return nil, fmt.Errorf("the carrier name: %s is duplicated", storeCarrier.Name)
+		}
+		names[storeCarrier.Name] = true
+
+		promptCarrier, err := carrier.NewPromptCarrier(storeCarrier.Name, storeCarrier.Instruction, storeCarrier.ParseType, storeCarrier.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		promptCarriers = append(promptCarriers, promptCarrier)
+	}
+	return promptCarriers, nil
+}
+
+func getQuestionWithCarriers(question string, suggestionCount int, needTitle bool, storeCarriers []object.StoreCarrier) (string, error) {
 	carriedQuestion := question
 
 	suggestionCarrier, err := carrier.NewSuggestionCarrier(suggestionCount)
@@ -44,30 +66,65 @@ func getQuestionWithCarriers(question string, suggestionCount int, needTitle boo
 		return "", err
 	}
 
+	promptCarriers, err := getPromptCarriers(storeCarriers)
+	if err != nil {
+		return "", err
+	}
+
+	for _, promptCarrier := range promptCarriers {
+		carriedQuestion, err = promptCarrier.GetQuestion(carriedQuestion)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	return carriedQuestion, err
 }
 
-func parseAnswerWithCarriers(answer string, suggestionCount int, needTitle bool) (string, []object.Suggestion, string, error) {
+func parseAnswerWithCarriers(answer string, suggestionCount int, needTitle bool, storeCarriers []object.StoreCarrier) (string, []object.Suggestion, string, map[string]string, error) {
 	suggestionCarrier, err := carrier.NewSuggestionCarrier(suggestionCount)
 	if err != nil {
-		return "", nil, "", err
+		return "", nil, "", nil, err
 	}
 
 	titleCarrier, err := carrier.NewTitleCarrier(needTitle)
 	if err != nil {
-		return "", nil, "", err
+		return "", nil, "", nil, err
 	}
 
-	parsedAnswer, textArray, err := titleCarrier.ParseAnswer(answer)
+	promptCarriers, err := getPromptCarriers(storeCarriers)
 	if err != nil {
-		return "", nil, "", err
+		return "", nil, "", nil, err
+	}
+
+	// The values of the prompt carriers are removed first, they end at the dividers of the title and the suggestions
+	parsedAnswer := answer
+	var textArray []string
+	var carrierValues map[string]string
+	for _, promptCarrier := range promptCarriers {
+		parsedAnswer, textArray, err = promptCarrier.ParseAnswer(parsedAnswer)
+		if err != nil {
+			return "", nil, "", nil, err
+		}
+
+		if textArray[0] != "" {
+			if carrierValues == nil {
+				carrierValues = map[string]string{}
+			}
+			carrierValues[promptCarrier.GetName()] = textArray[0]
+		}
+	}
+
+	parsedAnswer, textArray, err = titleCarrier.ParseAnswer(parsedAnswer)
+	if err != nil {
+		return "", nil, "", nil, err
 	}
 
 	title := textArray[0]
 
 	parsedAnswer, textArray, err = suggestionCarrier.ParseAnswer(parsedAnswer)
 	if err != nil {
-		return "", nil, "", err
+		return "", nil, "", nil, err
 	}
 
 	suggestions := []object.Suggestion{}
@@ -75,7 +132,7 @@ func parseAnswerWithCarriers(answer string, suggestionCount int, needTitle bool)
 		suggestions = append(suggestions, object.Suggestion{Text: suggestionText, IsHit: false})
 	}
 
-	return parsedAnswer, suggestions, title, nil
+	return parsedAnswer, suggestions, title, carrierValues, nil
 }
 
 func isReasonModel(typ string) bool {
@@ -88,7 +145,7 @@ func isReasonModel(typ string) bool {
 	return false
 }
 
-func getResultWithSuggestionsAndTitle(writer *CarrierWriter, question string, modelProviderObj model.ModelProvider, needTitle bool, suggestionCount int) (*model.ModelResult, error) {
+func getResultWithSuggestionsAndTitle(ctx context.Context, writer *CarrierWriter, question string, modelProviderObj model.ModelProvider, needTitle bool, suggestionCount int, storeCarriers []object.StoreCarrier) (*model.ModelResult, error) {
 	var fullPrompt strings.Builder
 
 	fullPrompt.WriteString(fmt.Sprintf("User question: %s\n\n", question))
@@ -115,7 +172,15 @@ Examples of generated title:
 - Do NOT include any explanations or extra text—just output the title.`)
 	}
 
-	carrierResult, err := modelProviderObj.QueryText(fullPrompt.String(), writer, nil, "", nil, nil)
+	promptCarriers, err := getPromptCarriers(storeCarriers)
+	if err != nil {
+		return nil, err
+	}
+	for _, promptCarrier := range promptCarriers {
+		fullPrompt.WriteString(fmt.Sprintf("\n\n**Based on the user question, follow the instruction below. No need to answer user question.**\n%s\n", promptCarrier.GetInstruction()))
+	}
+
+	carrierResult, err := modelProviderObj.QueryText(ctx, fullPrompt.String(), writer, nil, "", nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +188,7 @@ Examples of generated title:
 	return carrierResult, nil
 }
 
-func QueryCarrierText(question string, writer *RefinedWriter, history []*model.RawMessage, prompt string, knowledge []*model.RawMessage, modelProviderObj model.ModelProvider, needTitle bool, suggestionCount int) (*model.ModelResult, error) {
+func QueryCarrierText(ctx context.Context, question string, writer *RefinedWriter, history []*model.RawMessage, prompt string, knowledge []*model.RawMessage, modelProviderObj model.ModelProvider, needTitle bool, suggestionCount int, storeCarriers []object.StoreCarrier) (*model.ModelResult, error) {
 	var (
 		wg         sync.WaitGroup
 		mainErr    error
@@ -136,7 +201,7 @@ func QueryCarrierText(question string, writer *RefinedWriter, history []*model.R
 	go func() {
 		defer wg.Done()
 		var err error
-		modelResult, err = modelProviderObj.QueryText(question, writer, history, prompt, knowledge, nil)
+		modelResult, err = modelProviderObj.QueryText(ctx, question, writer, history, prompt, knowledge, nil, nil)
 		if err != nil {
 			mainErr = err
 		}
@@ -149,7 +214,7 @@ func QueryCarrierText(question string, writer *RefinedWriter, history []*model.R
 	go func() {
 		defer wg.Done()
 		var err error
-		carrierResult, err = getResultWithSuggestionsAndTitle(CarrierWriter, question, modelProviderObj, needTitle, suggestionCount)
+		carrierResult, err = getResultWithSuggestionsAndTitle(ctx, CarrierWriter, question, modelProviderObj, needTitle, suggestionCount, storeCarriers)
 		if err != nil {
 			carrierErr = err
 		}
@@ -0,0 +1,159 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/object"
+)
+
+// messageStopPollInterval is how often the answer checks the stop request stored on its message
+var messageStopPollInterval = time.Second
+
+// getMessageNeedStop is replaced by the tests
+var getMessageNeedStop = object.GetMessageNeedStop
+
+// newMessageAnswerContext returns a context that is canceled when the client disconnects or when the answer of the
+// message is stopped via StopMessageAnswer(), the stop request is read from the database, so it can be made on any
+// instance
+func (c *ApiController) newMessageAnswerContext(messageId string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(c.Ctx.Request.Context())
+
+	err := object.ResetMessageStop(messageId)
+	if err != nil {
+		fmt.Printf("newMessageAnswerContext() error: %s\n", err.Error())
+	}
+
+	go watchMessageStop(ctx, cancel, messageId)
+	return ctx, cancel
+}
+
+// watchMessageStop cancels the answer once its message is requested to stop, it returns when the answer ends
+func watchMessageStop(ctx context.Context, cancel context.CancelFunc, messageId string) {
+	ticker := time.NewTicker(messageStopPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			needStop, err := getMessageNeedStop(messageId)
+			if err != nil {
+				fmt.Printf("watchMessageStop() error: %s\n", err.Error())
+				continue
+			}
+			if needStop {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// saveStoppedAnswer persists what has been generated so far for an answer whose generation was stopped
+func (c *ApiController) saveStoppedAnswer(message *object.Message, chat *object.Chat, store *object.Store, writer *RefinedWriter, modelProvider *object.Provider, question string, modelResult *model.ModelResult) {
+	answer, reasonText, guardrailEvents, _, err := applyOutputGuardrails(store, writer.MessageString(), writer.ReasonString())
+	if err != nil {
+		fmt.Printf("saveStoppedAnswer() error: %s\n", err.Error())
+		return
+	}
+
+	textAnswer, textSuggestions, _, carrierValues, err := parseAnswerWithCarriers(answer, store.SuggestionCount, chat.NeedTitle, store.Carriers)
+	if err != nil {
+		textAnswer = answer
+		textSuggestions = []object.Suggestion{}
+	}
+
+	if modelResult == nil {
+		// The provider is interrupted before reporting its usage, so the tokens are estimated locally
+		modelResult = &model.ModelResult{}
+		promptTokenCount, err := model.GetTokenSize(modelProvider.SubType, question)
+		if err == nil {
+			modelResult.PromptTokenCount = promptTokenCount
+		}
+		responseTokenCount, err := model.GetTokenSize(modelProvider.SubType, answer)
+		if err == nil {
+			modelResult.ResponseTokenCount = responseTokenCount
+		}
+		modelResult.TotalTokenCount = modelResult.PromptTokenCount + modelResult.ResponseTokenCount
+	}
+
+	message.Text = textAnswer
+	message.ReasonText = reasonText
+	message.GuardrailEvents = append(message.GuardrailEvents, guardrailEvents...)
+	message.Suggestions = textSuggestions
+	message.CarrierValues = carrierValues
+	message.State = "Stopped"
+	message.ErrorText = ""
+	message.TokenCount = modelResult.TotalTokenCount
+	message.Price = modelResult.TotalPrice
+	message.Currency = modelResult.Currency
+	message.UsedModelProvider = modelProvider.Name
+	if modelResult.Provider != "" {
+		message.UsedModelProvider = modelResult.Provider
+	}
+	_, err = object.UpdateMessage(message.GetId(), message, false)
+	if err != nil {
+		fmt.Printf("saveStoppedAnswer() error: %s\n", err.Error())
+		return
+	}
+
+	err = addChatCost(chat, message.TokenCount, message.Price, message.Currency)
+	if err != nil {
+		fmt.Printf("saveStoppedAnswer() error: %s\n", err.Error())
+		return
+	}
+
+	// The client may have gone already, so the end event is best effort
+	_, _ = c.Ctx.ResponseWriter.Write([]byte(fmt.Sprintf("event: end\ndata: %s\n\n", "stopped")))
+}
+
+// StopMessageAnswer
+// @Title StopMessageAnswer
+// @Tag Message API
+// @Description stop generating the answer of the message, the partial answer is kept
+// @Param id query string true "The id of message"
+// @Success 200 {object} controllers.Response The Response object
+// @router /stop-message-answer [post]
+func (c *ApiController) StopMessageAnswer() {
+	id := c.Input().Get("id")
+
+	message, err := object.GetMessage(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if message == nil {
+		c.ResponseError(fmt.Sprintf("The message: %s is not found", id))
+		return
+	}
+
+	ok := c.IsCurrentUser(message.User)
+	if !ok {
+		return
+	}
+
+	ok, err = object.RequestMessageStop(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(ok)
+}
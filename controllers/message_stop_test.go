@@ -0,0 +1,71 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchMessageStop(t *testing.T) {
+	oldInterval, oldGetMessageNeedStop := messageStopPollInterval, getMessageNeedStop
+	t.Cleanup(func() {
+		messageStopPollInterval, getMessageNeedStop = oldInterval, oldGetMessageNeedStop
+	})
+	messageStopPollInterval = time.Millisecond
+
+	// The stop request stored by another instance cancels the answer at the next poll
+	var polls atomic.Int32
+	getMessageNeedStop = func(id string) (bool, error) {
+		if id != "admin/message_1" {
+			t.Errorf("getMessageNeedStop() of %s, want admin/message_1", id)
+		}
+		return polls.Add(1) >= 3, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go watchMessageStop(ctx, cancel, "admin/message_1")
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchMessageStop() should cancel the answer of a message requested to stop")
+	}
+	if polls.Load() != 3 {
+		t.Errorf("getMessageNeedStop() is called %d times, want 3", polls.Load())
+	}
+
+	// The watcher ends with the answer
+	polls.Store(0)
+	getMessageNeedStop = func(id string) (bool, error) {
+		polls.Add(1)
+		return false, nil
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watchMessageStop(ctx, cancel, "admin/message_1")
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchMessageStop() should return when the answer ends")
+	}
+}
@@ -55,6 +55,31 @@ func (c *ApiController) ResponseErrorStream(message *object.Message, errorText s
 	}
 }
 
+// ResponseBlockedStream answers the message with the blocked text of the guardrails as a normal answer, it is not
+// an error of the providers, so no error email is sent for it
+func (c *ApiController) ResponseBlockedStream(message *object.Message, blockedText string) {
+	message.Text = blockedText
+	message.ErrorText = ""
+	_, err := object.UpdateMessage(message.GetId(), message, false)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+
+	jsonData, err := ConvertMessageDataToJSON(blockedText)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+
+	event := fmt.Sprintf("event: message\ndata: %s\n\nevent: end\ndata: %s\n\n", jsonData, "end")
+	_, err = c.Ctx.ResponseWriter.Write([]byte(event))
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+}
+
 func refineQuestionTextViaParsingUrlContent(question string) (string, error) {
 	re := regexp.MustCompile(`href="([^"]+)"`)
 	urls := re.FindStringSubmatch(question)
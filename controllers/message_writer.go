@@ -29,10 +29,11 @@ type RefinedWriter struct {
 	buf           []byte
 	messageBuf    []byte
 	reasonBuf     []byte
+	isBuffered    bool // Whether the text is held back from the client until writeBufferedEvents()
 }
 
 func newRefinedWriter(w context.Response) *RefinedWriter {
-	return &RefinedWriter{w, *NewCleaner(6), []byte{}, []byte{}, []byte{}}
+	return &RefinedWriter{w, *NewCleaner(6), []byte{}, []byte{}, []byte{}, false}
 }
 
 func (w *RefinedWriter) Write(p []byte) (n int, err error) {
@@ -59,6 +60,10 @@ func (w *RefinedWriter) Write(p []byte) (n int, err error) {
 		w.reasonBuf = append(w.reasonBuf, []byte(data)...)
 	}
 
+	if w.isBuffered {
+		return len(p), nil
+	}
+
 	if w.writerCleaner.cleaned == false && w.writerCleaner.dataTimes < w.writerCleaner.bufferSize {
 		w.writerCleaner.AddData(data)
 		if w.writerCleaner.dataTimes == w.writerCleaner.bufferSize {
@@ -81,6 +86,36 @@ func (w *RefinedWriter) Write(p []byte) (n int, err error) {
 	return w.ResponseWriter.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, jsonData)))
 }
 
+// writeBufferedEvents sends the reason and message text held back by a buffered writer, one event each
+func (w *RefinedWriter) writeBufferedEvents(reasonText string, messageText string) error {
+	events := []struct {
+		eventType string
+		text      string
+	}{
+		{"reason", reasonText},
+		{"message", messageText},
+	}
+
+	for _, event := range events {
+		if event.text == "" {
+			continue
+		}
+
+		jsonData, err := ConvertMessageDataToJSON(event.text)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.ResponseWriter.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event.eventType, jsonData)))
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return nil
+}
+
 func (w *RefinedWriter) String() string {
 	return string(w.buf)
 }
@@ -17,71 +17,230 @@ package controllers
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/conf"
 	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/object"
 	"github.com/casibase/casibase/util"
 	"github.com/sashabaranov/go-openai"
 )
 
-// ChatCompletions implements the OpenAI-compatible chat completions API
-// @Title ChatCompletions
-// @Tag OpenAI Compatible API
-// @Description OpenAI compatible chat completions API
-// @Param   body    body    openai.ChatCompletionRequest  true    "The OpenAI chat request"
-// @Success 200 {object} openai.ChatCompletionResponse
-// @router /api/chat/completions [post]
-func (c *ApiController) ChatCompletions() {
-	// Authenticate using API key
+type OpenAiError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// OpenAiCitation is a piece of store knowledge that the answer is based on, numbered as in the prompt
+type OpenAiCitation struct {
+	Index int     `json:"index"`
+	File  string  `json:"file"`
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+}
+
+// OpenAiChatCompletionExtension holds the fields added to the OpenAI response when a store answers
+type OpenAiChatCompletionExtension struct {
+	Citations     []*OpenAiCitation `json:"citations,omitempty"`
+	Suggestions   []string          `json:"suggestions,omitempty"`
+	CarrierValues map[string]string `json:"carrierValues,omitempty"`
+}
+
+// OpenAiChatCompletionRequest reads the response format as plain JSON, as the schema of go-openai cannot be unmarshaled,
+// and the sampling parameters as pointers, as go-openai cannot tell an explicit 0 from an unset one
+type OpenAiChatCompletionRequest struct {
+	openai.ChatCompletionRequest
+	ResponseFormat   *OpenAiResponseFormat `json:"response_format,omitempty"`
+	Temperature      *float32              `json:"temperature,omitempty"`
+	TopP             *float32              `json:"top_p,omitempty"`
+	FrequencyPenalty *float32              `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32              `json:"presence_penalty,omitempty"`
+}
+
+type OpenAiResponseFormat struct {
+	Type       string `json:"type"`
+	JsonSchema *struct {
+		Name   string                 `json:"name"`
+		Schema map[string]interface{} `json:"schema"`
+	} `json:"json_schema,omitempty"`
+}
+
+// getResponseSchema returns the schema that the answer must conform to, or nil for a text answer
+func (f *OpenAiResponseFormat) getResponseSchema() *model.ResponseSchema {
+	if f == nil {
+		return nil
+	}
+
+	if f.Type == "json_schema" && f.JsonSchema != nil {
+		return &model.ResponseSchema{Name: f.JsonSchema.Name, Schema: f.JsonSchema.Schema}
+	} else if f.Type == "json_object" {
+		return &model.ResponseSchema{Name: "json_object", Schema: map[string]interface{}{"type": "object"}}
+	}
+	return nil
+}
+
+// getQueryOptions returns the answer token limit and the sampling parameters that the request sets for the answer,
+// the sampling parameters of the request override the ones configured for the provider
+func (r *OpenAiChatCompletionRequest) getQueryOptions() *model.QueryOptions {
+	maxTokens := r.MaxCompletionTokens
+	if maxTokens == 0 {
+		maxTokens = r.MaxTokens
+	}
+
+	return &model.QueryOptions{
+		MaxTokens:        maxTokens,
+		Temperature:      r.Temperature,
+		TopP:             r.TopP,
+		FrequencyPenalty: r.FrequencyPenalty,
+		PresencePenalty:  r.PresencePenalty,
+	}
+}
+
+type OpenAiChatCompletionResponse struct {
+	openai.ChatCompletionResponse
+	*OpenAiChatCompletionExtension
+}
+
+// ResponseOpenAiError writes the error in the format of the OpenAI API, so that the OpenAI SDKs can surface it
+func (c *ApiController) ResponseOpenAiError(status int, message string) {
+	typ := "invalid_request_error"
+	if status == http.StatusUnauthorized {
+		typ = "authentication_error"
+	} else if status >= http.StatusInternalServerError {
+		typ = "api_error"
+	}
+
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = map[string]interface{}{
+		"error": OpenAiError{Message: message, Type: typ},
+	}
+	c.ServeJSON()
+}
+
+func (c *ApiController) getOpenAiApiKey() (string, bool) {
 	apiKey := c.Ctx.Request.Header.Get("Authorization")
 	if !strings.HasPrefix(apiKey, "Bearer ") {
-		c.ResponseError("Invalid API key format. Expected 'Bearer API_KEY'")
-		return
+		c.ResponseOpenAiError(http.StatusUnauthorized, "Invalid API key format. Expected 'Bearer API_KEY'")
+		return "", false
 	}
 
-	apiKey = strings.TrimPrefix(apiKey, "Bearer ")
+	return strings.TrimPrefix(apiKey, "Bearer "), true
+}
+
+// getOpenAiApiProvider authenticates the request by its API key, which is the Provider key of a provider of the category
+func (c *ApiController) getOpenAiApiProvider(category string) (*object.Provider, bool) {
+	apiKey, ok := c.getOpenAiApiKey()
+	if !ok {
+		return nil, false
+	}
 
-	// Get the model provider based on API key
-	modelProvider, err := object.GetModelProviderByProviderKey(apiKey)
+	provider, err := object.GetProviderByProviderKeyAndCategory(apiKey, category)
 	if err != nil {
-		c.ResponseError(fmt.Sprintf("Authentication failed: %s", err.Error()))
-		return
+		c.ResponseOpenAiError(http.StatusUnauthorized, fmt.Sprintf("Authentication failed: %s", err.Error()))
+		return nil, false
 	}
 
-	// Parse request body
-	var request openai.ChatCompletionRequest
-	err = json.Unmarshal(c.Ctx.Input.RequestBody, &request)
+	return provider, true
+}
+
+// addOpenAiApiUsage records a request served by the OpenAI-compatible API in a hidden API chat, so that its tokens
+// and price are counted in the usages like the messages of the chat page. The question message carries the text and,
+// like on the chat page, the embedding cost of the knowledge search
+func (c *ApiController) addOpenAiApiUsage(chatName string, storeName string, questionMessage *object.Message, answerMessage *object.Message) error {
+	userName := "api"
+	chat, err := object.GetChat(util.GetId("admin", chatName))
 	if err != nil {
-		c.ResponseError(fmt.Sprintf("Failed to parse request: %s", err.Error()))
-		return
+		return err
 	}
+	if chat == nil {
+		casdoorOrganization := conf.GetConfigString("casdoorOrganization")
+		currentTime := util.GetCurrentTime()
+		chat = &object.Chat{
+			Owner:        "admin",
+			Name:         chatName,
+			CreatedTime:  currentTime,
+			UpdatedTime:  currentTime,
+			Organization: casdoorOrganization,
+			DisplayName:  chatName,
+			Store:        storeName,
+			Category:     "API",
+			Type:         "AI",
+			User:         userName,
+			User1:        "",
+			User2:        "",
+			Users:        []string{},
+			ClientIp:     c.getClientIp(),
+			UserAgent:    c.getUserAgent(),
+			MessageCount: 0,
+			IsHidden:     true,
+		}
 
-	// Extract messages content
-	var question string
-	var systemPrompt string
+		chat.ClientIpDesc = util.GetDescFromIP(chat.ClientIp)
+		chat.UserAgentDesc = util.GetDescFromUserAgent(chat.UserAgent)
 
-	for _, msg := range request.Messages {
-		if msg.Role == "system" {
-			systemPrompt = msg.Content
-		} else if msg.Role == "user" {
-			// Keep the last user message
-			question = msg.Content
+		_, err = object.AddChat(chat)
+		if err != nil {
+			// Another request of the API may have added the chat in the meantime
+			existingChat, getErr := object.GetChat(chat.GetId())
+			if getErr != nil || existingChat == nil {
+				return err
+			}
+			chat = existingChat
 		}
 	}
 
-	if question == "" {
-		c.ResponseError("No user message found in the request")
-		return
+	questionMessage.Owner = "admin"
+	questionMessage.Name = fmt.Sprintf("message_%s", util.GetRandomName())
+	questionMessage.CreatedTime = util.GetCurrentTimeEx(chat.CreatedTime)
+	questionMessage.Organization = chat.Organization
+	questionMessage.Store = chat.Store
+	questionMessage.User = userName
+	questionMessage.Chat = chat.Name
+	questionMessage.ReplyTo = ""
+	questionMessage.Author = userName
+	if questionMessage.Currency == "" {
+		questionMessage.Currency = answerMessage.Currency
+	}
+	_, err = object.AddMessage(questionMessage)
+	if err != nil {
+		return err
 	}
 
-	// Combine system prompt with user question if available
-	if systemPrompt != "" {
-		question = fmt.Sprintf("System: %s\n\nUser: %s", systemPrompt, question)
+	answerMessage.Owner = "admin"
+	answerMessage.Name = fmt.Sprintf("message_%s", util.GetRandomName())
+	answerMessage.CreatedTime = util.GetCurrentTimeEx(chat.CreatedTime)
+	answerMessage.Organization = chat.Organization
+	answerMessage.Store = chat.Store
+	answerMessage.User = userName
+	answerMessage.Chat = chat.Name
+	answerMessage.ReplyTo = questionMessage.Name
+	answerMessage.Author = "AI"
+	_, err = object.AddMessage(answerMessage)
+	if err != nil {
+		return err
 	}
 
+	// The requests of the API share the chat, so only the added price is converted here and the counts are added by
+	// the database, instead of writing back the chat read above over the usages recorded in the meantime. The two
+	// messages are already counted by AddMessage()
+	usage := &object.Chat{Owner: chat.Owner, Name: chat.Name, Currency: chat.Currency}
+	_, err = addChatPrice(usage, questionMessage.Price, questionMessage.Currency)
+	if err != nil {
+		return err
+	}
+	_, err = addChatPrice(usage, answerMessage.Price, answerMessage.Currency)
+	if err != nil {
+		return err
+	}
+
+	_, err = object.AddChatUsage(chat.GetId(), 0, questionMessage.TokenCount+answerMessage.TokenCount, usage.Price, usage.Currency)
+	return err
+}
+
+func (c *ApiController) newOpenAiWriter(request *openai.ChatCompletionRequest, responseModel string) *OpenAIWriter {
 	// Setup for streaming if enabled
-	requestId := util.GenerateUUID()
 	if request.Stream {
 		c.Ctx.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
 		c.Ctx.ResponseWriter.Header().Set("Cache-Control", "no-cache")
@@ -89,73 +248,347 @@ func (c *ApiController) ChatCompletions() {
 	}
 
 	// Create custom writer for OpenAI format
-	writer := &OpenAIWriter{
+	return &OpenAIWriter{
 		Response:  *c.Ctx.ResponseWriter, // Embed Response by dereferencing the pointer
 		Buffer:    []byte{},
-		RequestID: requestId,
+		RequestID: util.GenerateUUID(),
 		Stream:    request.Stream,
 		Cleaner:   *NewCleaner(6),
-		Model:     request.Model,
+		Model:     responseModel,
+	}
+}
+
+// responseOpenAiQueryError reports an error of the model query, inside the stream if it has started already
+func (c *ApiController) responseOpenAiQueryError(writer *OpenAIWriter, err error) {
+	if writer.StreamSent {
+		_ = writer.writeData(map[string]interface{}{"error": OpenAiError{Message: err.Error(), Type: "api_error"}})
+		c.EnableRender = false
+		return
 	}
 
-	// Prepare empty history and knowledge for the model
-	history := []*model.RawMessage{}
-	knowledge := []*model.RawMessage{}
+	c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+}
 
-	// Call the model provider
-	modelResult, err := modelProvider.QueryText(question, writer, history, "", knowledge, nil)
-	if err != nil {
-		c.ResponseError(err.Error())
+// responseOpenAiChatCompletion sends the answer collected by the writer, as a whole or as the end of the stream
+func (c *ApiController) responseOpenAiChatCompletion(writer *OpenAIWriter, toolCalls []openai.ToolCall, modelResult *model.ModelResult, extension *OpenAiChatCompletionExtension) {
+	usage := openai.Usage{
+		PromptTokens:     modelResult.PromptTokenCount,
+		CompletionTokens: modelResult.ResponseTokenCount,
+		TotalTokens:      modelResult.TotalTokenCount,
+	}
+
+	if writer.Stream {
+		// For streaming, close the stream with the tool calls and token counts
+		writer.Extension = extension
+		err := writer.Close(toolCalls, usage)
+		if err != nil {
+			fmt.Printf("responseOpenAiChatCompletion() error: %s\n", err.Error())
+		}
+		c.EnableRender = false
 		return
 	}
 
-	// Handle response based on streaming mode
-	if !request.Stream {
-		// For non-streaming, send complete response at once
-		answer := writer.MessageString()
+	// For non-streaming, send complete response at once
+	message := openai.ChatCompletionMessage{
+		Role:             openai.ChatMessageRoleAssistant,
+		Content:          writer.MessageString(),
+		ReasoningContent: writer.ReasonString(),
+	}
+	finishReason := openai.FinishReasonStop
+	if len(toolCalls) > 0 {
+		message.ToolCalls = toolCalls
+		finishReason = openai.FinishReasonToolCalls
+	}
 
-		// Create response using go-openai structures
-		response := openai.ChatCompletionResponse{
-			ID:      "chatcmpl-" + requestId,
+	// Create response using go-openai structures
+	response := OpenAiChatCompletionResponse{
+		ChatCompletionResponse: openai.ChatCompletionResponse{
+			ID:      "chatcmpl-" + writer.RequestID,
 			Object:  "chat.completion",
 			Created: util.GetCurrentUnixTime(),
-			Model:   request.Model,
+			Model:   writer.Model,
 			Choices: []openai.ChatCompletionChoice{
 				{
-					Index: 0,
-					Message: openai.ChatCompletionMessage{
-						Role:    "assistant",
-						Content: answer,
-					},
-					FinishReason: openai.FinishReasonStop,
+					Index:        0,
+					Message:      message,
+					FinishReason: finishReason,
 				},
 			},
-			Usage: openai.Usage{
-				PromptTokens:     modelResult.PromptTokenCount,
-				CompletionTokens: modelResult.ResponseTokenCount,
-				TotalTokens:      modelResult.TotalTokenCount,
-			},
-		}
+			Usage: usage,
+		},
+		OpenAiChatCompletionExtension: extension,
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", "application/json")
+	err = c.Ctx.Output.Body(jsonResponse)
+	if err != nil {
+		fmt.Printf("responseOpenAiChatCompletion() error: %s\n", err.Error())
+	}
+	c.EnableRender = false
+}
+
+// ChatCompletions implements the OpenAI-compatible chat completions API
+// @Title ChatCompletions
+// @Tag OpenAI Compatible API
+// @Description OpenAI compatible chat completions API, the tools in the request are returned as tool calls for the client to run. A model named "store:<store name>" answers with the knowledge of the store, using the API key of the store. A JSON response format makes the answer conform to the schema.
+// @Param   body    body    controllers.OpenAiChatCompletionRequest  true    "The OpenAI chat request"
+// @Success 200 {object} controllers.OpenAiChatCompletionResponse
+// @router /api/chat/completions [post]
+func (c *ApiController) ChatCompletions() {
+	// Parse request body
+	var chatRequest OpenAiChatCompletionRequest
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &chatRequest)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusBadRequest, fmt.Sprintf("Failed to parse request: %s", err.Error()))
+		return
+	}
+
+	request := chatRequest.ChatCompletionRequest
+	options := chatRequest.getQueryOptions()
+	if _, isStore := getOpenAiStoreName(request.Model); isStore {
+		options.ResponseSchema = chatRequest.ResponseFormat.getResponseSchema()
+		c.storeChatCompletions(&request, options)
+		return
+	}
+
+	provider, ok := c.getOpenAiApiProvider("Model")
+	if !ok {
+		return
+	}
+
+	prompt, question, history, agentMessages, err := model.OpenaiMessagesToRawMessages(request.Messages)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusBadRequest, err.Error())
+		return
+	}
 
-		jsonResponse, err := json.Marshal(response)
+	modelProvider, err := provider.GetModelProvider()
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	agentInfo := &model.AgentInfo{
+		AgentMessages: &model.AgentMessages{Messages: agentMessages},
+	}
+	if len(request.Tools) > 0 && request.ToolChoice != "none" {
+		tools, err := model.OpenaiToolsToMcpTools(request.Tools)
 		if err != nil {
-			c.ResponseError(err.Error())
+			c.ResponseOpenAiError(http.StatusBadRequest, err.Error())
 			return
 		}
 
-		c.Ctx.Output.Header("Content-Type", "application/json")
-		c.Ctx.Output.Body(jsonResponse)
+		// The tools are run by the client, so there are no clients to call them here
+		agentInfo.AgentClients = &agent.AgentClients{Tools: tools}
+	}
+
+	responseModel := request.Model
+	if responseModel == "" {
+		responseModel = provider.Name
+	}
+	writer := c.newOpenAiWriter(&request, responseModel)
+
+	// The response format applies when there are no tools, whose calls are not JSON of the schema
+	if agentInfo.AgentClients == nil {
+		options.ResponseSchema = chatRequest.ResponseFormat.getResponseSchema()
+	}
+
+	ctx := c.Ctx.Request.Context()
+	knowledge := []*model.RawMessage{}
+	var modelResult *model.ModelResult
+	if options.ResponseSchema != nil {
+		modelResult, err = model.QueryStructuredOutput(ctx, modelProvider, question, writer, history, prompt, knowledge, options)
 	} else {
-		// For streaming, close the stream with token counts
-		err = writer.Close(
-			modelResult.PromptTokenCount,
-			modelResult.ResponseTokenCount,
-			modelResult.TotalTokenCount,
-		)
+		modelResult, err = modelProvider.QueryText(ctx, question, writer, history, prompt, knowledge, agentInfo, options)
+	}
+	if err != nil {
+		c.responseOpenAiQueryError(writer, err)
+		return
+	}
+
+	answerMessage := &object.Message{
+		Text:              writer.MessageString(),
+		ReasonText:        writer.ReasonString(),
+		TokenCount:        modelResult.TotalTokenCount,
+		Price:             modelResult.TotalPrice,
+		Currency:          modelResult.Currency,
+		ModelProvider:     provider.Name,
+		UsedModelProvider: provider.Name,
+	}
+	if modelResult.Provider != "" {
+		answerMessage.UsedModelProvider = modelResult.Provider
+	}
+	err = c.addOpenAiApiUsage(fmt.Sprintf("chat_api_%s", provider.Name), "", &object.Message{Text: question}, answerMessage)
+	if err != nil {
+		fmt.Printf("ChatCompletions() error: failed to record the usage, %s\n", err.Error())
+	}
+
+	c.responseOpenAiChatCompletion(writer, model.GetToolCalls(agentInfo.AgentMessages), modelResult, nil)
+}
+
+// ListModels implements the OpenAI-compatible models API
+// @Title ListModels
+// @Tag OpenAI Compatible API
+// @Description OpenAI compatible models API, it lists the model provider that the API key belongs to
+// @Success 200 {object} openai.ModelsList
+// @router /api/models [get]
+func (c *ApiController) ListModels() {
+	apiKey, ok := c.getOpenAiApiKey()
+	if !ok {
+		return
+	}
+
+	store, err := object.GetStoreByApiKey(apiKey)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if store != nil {
+		c.Data["json"] = map[string]interface{}{
+			"object": "list",
+			"data": []openai.Model{
+				{
+					ID:         fmt.Sprintf("store:%s", store.Name),
+					Object:     "model",
+					CreatedAt:  util.GetUnixTimeFromString(store.CreatedTime),
+					OwnedBy:    "store",
+					Permission: []openai.Permission{},
+					Root:       store.ModelProvider,
+				},
+			},
+		}
+		c.ServeJSON()
+		return
+	}
+
+	provider, ok := c.getOpenAiApiProvider("Model")
+	if !ok {
+		return
+	}
+
+	models := []openai.Model{
+		{
+			ID:         provider.Name,
+			Object:     "model",
+			CreatedAt:  util.GetUnixTimeFromString(provider.CreatedTime),
+			OwnedBy:    provider.Type,
+			Permission: []openai.Permission{},
+			Root:       provider.SubType,
+		},
+	}
+
+	c.Data["json"] = map[string]interface{}{
+		"object": "list",
+		"data":   models,
+	}
+	c.ServeJSON()
+}
+
+func getEmbeddingInputs(input interface{}) ([]string, error) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		res := []string{}
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("only text inputs are supported for embeddings")
+			}
+			res = append(res, text)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("only text inputs are supported for embeddings")
+	}
+}
+
+// Embeddings implements the OpenAI-compatible embeddings API
+// @Title Embeddings
+// @Tag OpenAI Compatible API
+// @Description OpenAI compatible embeddings API, the API key should be the Provider key of an embedding provider
+// @Param   body    body    openai.EmbeddingRequest  true    "The OpenAI embedding request"
+// @Success 200 {object} openai.EmbeddingResponse
+// @router /api/embeddings [post]
+func (c *ApiController) Embeddings() {
+	provider, ok := c.getOpenAiApiProvider("Embedding")
+	if !ok {
+		return
+	}
+
+	var request openai.EmbeddingRequest
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &request)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusBadRequest, fmt.Sprintf("Failed to parse request: %s", err.Error()))
+		return
+	}
+
+	inputs, err := getEmbeddingInputs(request.Input)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	embeddingProvider, err := provider.GetEmbeddingProvider()
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := []openai.Embedding{}
+	tokenCount := 0
+	price := 0.0
+	currency := ""
+	for i, input := range inputs {
+		vector, embeddingResult, err := embeddingProvider.QueryVector(input, c.Ctx.Request.Context())
 		if err != nil {
-			c.ResponseError(err.Error())
+			c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
 			return
 		}
+
+		data = append(data, openai.Embedding{
+			Object:    "embedding",
+			Embedding: vector,
+			Index:     i,
+		})
+		if embeddingResult != nil {
+			tokenCount += embeddingResult.TokenCount
+			price += embeddingResult.Price
+			currency = embeddingResult.Currency
+		}
 	}
-	c.EnableRender = false
+
+	responseModel := request.Model
+	if responseModel == "" {
+		responseModel = openai.EmbeddingModel(provider.Name)
+	}
+
+	answerMessage := &object.Message{
+		Text:              fmt.Sprintf("%d embeddings", len(data)),
+		TokenCount:        tokenCount,
+		Price:             price,
+		Currency:          currency,
+		EmbeddingProvider: provider.Name,
+	}
+	err = c.addOpenAiApiUsage(fmt.Sprintf("chat_api_%s", provider.Name), "", &object.Message{Text: strings.Join(inputs, "\n")}, answerMessage)
+	if err != nil {
+		fmt.Printf("Embeddings() error: failed to record the usage, %s\n", err.Error())
+	}
+
+	c.Data["json"] = openai.EmbeddingResponse{
+		Object: "list",
+		Data:   data,
+		Model:  responseModel,
+		Usage: openai.Usage{
+			PromptTokens: tokenCount,
+			TotalTokens:  tokenCount,
+		},
+	}
+	c.ServeJSON()
 }
@@ -0,0 +1,289 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+	"github.com/sashabaranov/go-openai"
+)
+
+var getVector = object.GetVector
+
+// getOpenAiStoreName returns the store name of a model named "store:<store name>", and whether the model is a store
+func getOpenAiStoreName(modelName string) (string, bool) {
+	if !strings.HasPrefix(modelName, "store:") {
+		return "", false
+	}
+	return strings.TrimPrefix(modelName, "store:"), true
+}
+
+// getOpenAiApiStore authenticates the request by the API key of the store named in the model
+func (c *ApiController) getOpenAiApiStore(modelName string) (*object.Store, bool) {
+	apiKey, ok := c.getOpenAiApiKey()
+	if !ok {
+		return nil, false
+	}
+
+	storeName, _ := getOpenAiStoreName(modelName)
+	store, err := object.GetStore(util.GetId("admin", storeName))
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if store == nil || store.ApiKey == "" || store.ApiKey != apiKey {
+		c.ResponseOpenAiError(http.StatusUnauthorized, fmt.Sprintf("Authentication failed: the API key is invalid for the store: %s", storeName))
+		return nil, false
+	}
+
+	return store, true
+}
+
+func getOpenAiCitations(vectorScores []object.VectorScore) ([]*OpenAiCitation, error) {
+	citations := []*OpenAiCitation{}
+	for i, vectorScore := range vectorScores {
+		vector, err := getVector(util.GetId("admin", vectorScore.Vector))
+		if err != nil {
+			return nil, err
+		}
+		if vector == nil {
+			continue
+		}
+
+		citations = append(citations, &OpenAiCitation{
+			Index: i + 1,
+			File:  vector.File,
+			Text:  vector.Text,
+			Score: vectorScore.Score,
+		})
+	}
+	return citations, nil
+}
+
+// storeChatCompletions answers a chat completion request for the "store:<store name>" model with the store
+// knowledge, the same way as the chat page does, and returns the citations and suggestions with the answer.
+// A response schema in the options makes the answer conform to it, without the tools of the store agent
+func (c *ApiController) storeChatCompletions(request *openai.ChatCompletionRequest, options *model.QueryOptions) {
+	store, ok := c.getOpenAiApiStore(request.Model)
+	if !ok {
+		return
+	}
+
+	prompt, question, history, agentMessages, err := model.OpenaiMessagesToRawMessages(request.Messages)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, guardrailEvents, isBlocked, err := object.ApplyGuardrails(store.Guardrails, object.GuardrailStageInput, question)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isBlocked {
+		c.ResponseOpenAiError(http.StatusBadRequest, object.GetGuardrailBlockedText(object.GuardrailStageInput))
+		return
+	}
+	object.MaskHistoryPii(store.Guardrails, history)
+
+	storePrompt := store.Prompt
+	if prompt != "" {
+		if storePrompt != "" {
+			storePrompt += "\n\n"
+		}
+		storePrompt += prompt
+	}
+
+	modelProvider, modelProviderObj, err := object.GetModelProviderFromContext("admin", store.ModelProvider)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	embeddingProvider, embeddingProviderObj, err := object.GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_, agentProviderObj, err := object.GetAgentProviderFromContext("admin", store.AgentProvider, nil)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	agentClients, err := object.GetAgentClients(agentProviderObj)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+	// The pooled MCP clients are released on every return, the Close() of QueryTextWithTools() then does nothing
+	defer agentClients.Close()
+
+	knowledgeCount := store.KnowledgeCount
+	if knowledgeCount <= 0 {
+		knowledgeCount = 10
+	}
+
+	// There is no signed-in user for the API, so only the files without permissions are searched
+	knowledge, vectorScores, embeddingResult, err := object.GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount, nil)
+	if err != nil && err.Error() != "no knowledge vectors found" {
+		c.ResponseOpenAiError(http.StatusInternalServerError, fmt.Sprintf("object.GetNearestKnowledge() error, %s", err.Error()))
+		return
+	}
+	if embeddingResult == nil {
+		embeddingResult = &embedding.EmbeddingResult{}
+	}
+
+	citations, err := getOpenAiCitations(vectorScores)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The answer is held back until the output guardrails have checked it
+	writer := c.newOpenAiWriter(request, request.Model)
+	writer.IsBuffered = object.HasGuardrails(store.Guardrails, object.GuardrailStageOutput)
+	ctx := c.Ctx.Request.Context()
+
+	agentInfo := &model.AgentInfo{
+		AgentClients:  agentClients,
+		AgentMessages: &model.AgentMessages{Messages: agentMessages},
+	}
+
+	var (
+		wg            sync.WaitGroup
+		modelResult   *model.ModelResult
+		carrierResult *model.ModelResult
+		carrierErr    error
+	)
+
+	promptCarriers, err := getPromptCarriers(store.Carriers)
+	if err != nil {
+		c.ResponseOpenAiError(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The suggestions and the values of the store carriers are generated aside, so that the streamed answer has no
+	// carrier text in it
+	carrierWriter := &CarrierWriter{*NewCleaner(6), []byte{}}
+	needCarriers := (store.SuggestionCount > 0 || len(promptCarriers) > 0) && modelProvider.Type != "Dummy"
+	if needCarriers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			carrierResult, carrierErr = getResultWithSuggestionsAndTitle(ctx, carrierWriter, question, modelProviderObj, false, store.SuggestionCount, store.Carriers)
+		}()
+	}
+
+	if options.ResponseSchema != nil {
+		agentClients.Close()
+		modelResult, err = model.QueryStructuredOutput(ctx, modelProviderObj, question, writer, history, storePrompt, knowledge, options)
+	} else if agentClients != nil {
+		modelResult, err = model.QueryTextWithTools(ctx, modelProviderObj, question, writer, history, storePrompt, knowledge, agentInfo, options)
+	} else {
+		modelResult, err = modelProviderObj.QueryText(ctx, question, writer, history, storePrompt, knowledge, nil, options)
+	}
+	wg.Wait()
+	if err != nil {
+		c.responseOpenAiQueryError(writer, err)
+		return
+	}
+
+	if writer.IsBuffered {
+		answer, reasonText, outputEvents, _, err := applyOutputGuardrails(store, writer.MessageString(), writer.ReasonString())
+		if err != nil {
+			c.responseOpenAiQueryError(writer, err)
+			return
+		}
+		guardrailEvents = append(guardrailEvents, outputEvents...)
+
+		err = writer.writeBufferedText(reasonText, answer)
+		if err != nil {
+			c.responseOpenAiQueryError(writer, err)
+			return
+		}
+	}
+
+	suggestions := []object.Suggestion{}
+	var carrierValues map[string]string
+	if needCarriers {
+		if carrierErr != nil {
+			fmt.Printf("storeChatCompletions() error: failed to generate suggestions and carrier values, %s\n", carrierErr.Error())
+		} else {
+			_, suggestions, _, carrierValues, err = parseAnswerWithCarriers(carrierWriter.MessageString(), store.SuggestionCount, false, store.Carriers)
+			if err != nil {
+				fmt.Printf("storeChatCompletions() error: failed to parse suggestions and carrier values, %s\n", err.Error())
+			}
+
+			modelResult.PromptTokenCount += carrierResult.PromptTokenCount
+			modelResult.ResponseTokenCount += carrierResult.ResponseTokenCount
+			modelResult.TotalPrice += carrierResult.TotalPrice
+			modelResult.TotalTokenCount += carrierResult.TotalTokenCount
+		}
+	}
+
+	extension := &OpenAiChatCompletionExtension{
+		Citations:     citations,
+		Suggestions:   []string{},
+		CarrierValues: carrierValues,
+	}
+	for _, suggestion := range suggestions {
+		extension.Suggestions = append(extension.Suggestions, suggestion.Text)
+	}
+
+	answerMessage := &object.Message{
+		Text:              writer.MessageString(),
+		ReasonText:        writer.ReasonString(),
+		TokenCount:        modelResult.TotalTokenCount,
+		Price:             modelResult.TotalPrice,
+		Currency:          modelResult.Currency,
+		ModelProvider:     modelProvider.Name,
+		UsedModelProvider: modelProvider.Name,
+		EmbeddingProvider: embeddingProvider.Name,
+		VectorScores:      vectorScores,
+		Suggestions:       suggestions,
+		CarrierValues:     carrierValues,
+		AgentSteps:        agentInfo.AgentSteps,
+		GuardrailEvents:   guardrailEvents,
+	}
+	if modelResult.Provider != "" {
+		answerMessage.UsedModelProvider = modelResult.Provider
+	}
+
+	// The embedding cost goes with the question like on the chat page, so that a different currency is converted
+	// into the one of the chat instead of being mixed into the price of the answer
+	questionMessage := &object.Message{
+		Text:       question,
+		TokenCount: embeddingResult.TokenCount,
+		Price:      embeddingResult.Price,
+		Currency:   embeddingResult.Currency,
+	}
+	err = c.addOpenAiApiUsage(fmt.Sprintf("chat_api_store_%s", store.Name), store.Name, questionMessage, answerMessage)
+	if err != nil {
+		fmt.Printf("storeChatCompletions() error: failed to record the usage, %s\n", err.Error())
+	}
+
+	// The tools of the store agent are run by the server, so no tool calls are returned to the client
+	c.responseOpenAiChatCompletion(writer, nil, modelResult, extension)
+}
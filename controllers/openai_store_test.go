@@ -0,0 +1,88 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/casibase/casibase/object"
+)
+
+func TestGetOpenAiStoreName(t *testing.T) {
+	tests := []struct {
+		modelName string
+		storeName string
+		isStore   bool
+	}{
+		{"store:store-built-in", "store-built-in", true},
+		{"store:", "", true},
+		{"gpt-4o", "", false},
+		{"my-store:v1", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		storeName, isStore := getOpenAiStoreName(test.modelName)
+		if storeName != test.storeName || isStore != test.isStore {
+			t.Errorf("getOpenAiStoreName(%q) = (%q, %v), want (%q, %v)", test.modelName, storeName, isStore, test.storeName, test.isStore)
+		}
+	}
+}
+
+func TestGetOpenAiCitations(t *testing.T) {
+	oldGetVector := getVector
+	t.Cleanup(func() {
+		getVector = oldGetVector
+	})
+
+	vectors := map[string]*object.Vector{
+		"admin/vector_1": {Owner: "admin", Name: "vector_1", File: "a.md", Text: "alpha"},
+		"admin/vector_3": {Owner: "admin", Name: "vector_3", File: "c.md", Text: "gamma"},
+	}
+	getVector = func(id string) (*object.Vector, error) {
+		if id == "admin/vector_error" {
+			return nil, fmt.Errorf("database error")
+		}
+		return vectors[id], nil
+	}
+
+	// A deleted vector is left out, the others keep the index of their knowledge in the answer
+	citations, err := getOpenAiCitations([]object.VectorScore{
+		{Vector: "vector_1", Score: 0.9},
+		{Vector: "vector_2", Score: 0.8},
+		{Vector: "vector_3", Score: 0.7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*OpenAiCitation{
+		{Index: 1, File: "a.md", Text: "alpha", Score: 0.9},
+		{Index: 3, File: "c.md", Text: "gamma", Score: 0.7},
+	}
+	if !reflect.DeepEqual(citations, want) {
+		t.Errorf("getOpenAiCitations() = %+v, want %+v", citations, want)
+	}
+
+	citations, err = getOpenAiCitations(nil)
+	if err != nil || len(citations) != 0 || citations == nil {
+		t.Errorf("getOpenAiCitations(nil) = (%v, %v), want an empty list", citations, err)
+	}
+
+	_, err = getOpenAiCitations([]object.VectorScore{{Vector: "vector_error"}})
+	if err == nil {
+		t.Error("getOpenAiCitations() should return the error of getting a vector")
+	}
+}
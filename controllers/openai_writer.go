@@ -24,22 +24,31 @@ import (
 	"github.com/sashabaranov/go-openai"
 )
 
+type openAiStreamUsageResponse struct {
+	openai.ChatCompletionStreamResponse
+	*OpenAiChatCompletionExtension
+}
+
 // OpenAIWriter implements a writer that formats responses in OpenAI format
 type OpenAIWriter struct {
 	context.Response
 	Cleaner    Cleaner
 	Buffer     []byte
 	MessageBuf []byte
+	ReasonBuf  []byte
 	RequestID  string
 	Stream     bool
 	StreamSent bool
+	IsBuffered bool // Whether the streamed text is held back from the client until writeBufferedText()
 	Model      string
+	Extension  *OpenAiChatCompletionExtension
 }
 
 // Write processes incoming data chunks and formats them for OpenAI compatibility
 func (w *OpenAIWriter) Write(p []byte) (n int, err error) {
 	// Parse the incoming SSE message format
 	var content string
+	var reasonContent string
 
 	if bytes.HasPrefix(p, []byte("event: message\ndata: ")) {
 		prefix := []byte("event: message\ndata: ")
@@ -49,10 +58,11 @@ func (w *OpenAIWriter) Write(p []byte) (n int, err error) {
 		// Add content to message buffer
 		w.MessageBuf = append(w.MessageBuf, []byte(content)...)
 	} else if bytes.HasPrefix(p, []byte("event: reason\ndata: ")) {
-		// We don't expose reason data in OpenAI format, but we'll store it
+		// Reason data is exposed as reasoning_content, like DeepSeek and other reasoning models do
 		prefix := []byte("event: reason\ndata: ")
 		suffix := []byte("\n\n")
-		content = string(bytes.TrimSuffix(bytes.TrimPrefix(p, prefix), suffix))
+		reasonContent = string(bytes.TrimSuffix(bytes.TrimPrefix(p, prefix), suffix))
+		w.ReasonBuf = append(w.ReasonBuf, []byte(reasonContent)...)
 	} else {
 		// If we can't parse, just store the raw bytes and attempt to clean
 		content = w.Cleaner.CleanString(string(p))
@@ -64,16 +74,33 @@ func (w *OpenAIWriter) Write(p []byte) (n int, err error) {
 	// Always store the original bytes
 	w.Buffer = append(w.Buffer, p...)
 
-	// For non-streaming, just collect the data
-	if !w.Stream {
+	// For non-streaming or buffered streaming, just collect the data
+	if !w.Stream || w.IsBuffered {
 		return len(p), nil
 	}
 
 	// Skip empty content
-	if content == "" {
+	if content == "" && reasonContent == "" {
 		return len(p), nil
 	}
 
+	err = w.writeChunk(openai.ChatCompletionStreamChoiceDelta{
+		Content:          content,
+		ReasoningContent: reasonContent,
+	}, openai.FinishReasonNull)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeChunk sends a chat completion chunk, the first chunk of the stream also carries the assistant role
+func (w *OpenAIWriter) writeChunk(delta openai.ChatCompletionStreamChoiceDelta, finishReason openai.FinishReason) error {
+	if !w.StreamSent {
+		delta.Role = openai.ChatMessageRoleAssistant
+	}
+
 	// Create SSE chunk using go-openai library structure
 	chunk := openai.ChatCompletionStreamResponse{
 		ID:      "chatcmpl-" + w.RequestID,
@@ -82,30 +109,50 @@ func (w *OpenAIWriter) Write(p []byte) (n int, err error) {
 		Model:   w.Model,
 		Choices: []openai.ChatCompletionStreamChoice{
 			{
-				Index: 0,
-				Delta: openai.ChatCompletionStreamChoiceDelta{
-					Content: content,
-				},
-				FinishReason: openai.FinishReasonNull,
+				Index:        0,
+				Delta:        delta,
+				FinishReason: finishReason,
 			},
 		},
 	}
 
-	jsonData, err := json.Marshal(chunk)
+	err := w.writeData(chunk)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	w.StreamSent = true
+	return nil
+}
+
+func (w *OpenAIWriter) writeData(data interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
 	}
 
 	// Send as SSE data chunk - use ResponseWriter to avoid recursion
 	_, err = w.ResponseWriter.Write([]byte(fmt.Sprintf("data: %s\n\n", jsonData)))
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	w.StreamSent = true
 	w.Flush()
+	return nil
+}
 
-	return len(p), nil
+// writeBufferedText replaces the collected text with the checked one, and sends it in one chunk for a buffered stream
+func (w *OpenAIWriter) writeBufferedText(reasonText string, messageText string) error {
+	w.MessageBuf = []byte(messageText)
+	w.ReasonBuf = []byte(reasonText)
+	if !w.Stream || (messageText == "" && reasonText == "") {
+		return nil
+	}
+
+	return w.writeChunk(openai.ChatCompletionStreamChoiceDelta{
+		Content:          messageText,
+		ReasoningContent: reasonText,
+	}, openai.FinishReasonNull)
 }
 
 // MessageString returns the complete buffered message
@@ -113,65 +160,57 @@ func (w *OpenAIWriter) MessageString() string {
 	return string(w.MessageBuf)
 }
 
-// Close finalizes the stream by sending completion message and DONE marker
-func (w *OpenAIWriter) Close(promptTokens, completionTokens, totalTokens int) error {
+// ReasonString returns the complete buffered reasoning
+func (w *OpenAIWriter) ReasonString() string {
+	return string(w.ReasonBuf)
+}
+
+// Close finalizes the stream by sending the finish reason, the tool calls requested by the model,
+// the usage chunk and the DONE marker
+func (w *OpenAIWriter) Close(toolCalls []openai.ToolCall, usage openai.Usage) error {
 	if !w.Stream {
 		return nil
 	}
 
-	if w.StreamSent {
-		// Send final message with finish_reason
-		chunk := openai.ChatCompletionStreamResponse{
+	delta := openai.ChatCompletionStreamChoiceDelta{}
+	finishReason := openai.FinishReasonStop
+	if len(toolCalls) > 0 {
+		for i := range toolCalls {
+			index := i
+			toolCalls[i].Index = &index
+		}
+		delta.ToolCalls = toolCalls
+		finishReason = openai.FinishReasonToolCalls
+	}
+
+	err := w.writeChunk(delta, finishReason)
+	if err != nil {
+		return err
+	}
+
+	// The usage chunk has no choices, as the "include_usage" stream option of OpenAI sends it,
+	// and it carries the extension fields such as the citations
+	err = w.writeData(openAiStreamUsageResponse{
+		ChatCompletionStreamResponse: openai.ChatCompletionStreamResponse{
 			ID:      "chatcmpl-" + w.RequestID,
 			Object:  "chat.completion.chunk",
 			Created: util.GetCurrentUnixTime(),
 			Model:   w.Model,
-			Choices: []openai.ChatCompletionStreamChoice{
-				{
-					Index:        0,
-					Delta:        openai.ChatCompletionStreamChoiceDelta{}, // Empty delta
-					FinishReason: openai.FinishReasonStop,
-				},
-			},
-		}
-
-		jsonData, err := json.Marshal(chunk)
-		if err != nil {
-			return err
-		}
-
-		_, err = w.ResponseWriter.Write([]byte(fmt.Sprintf("data: %s\n\n", jsonData)))
-		if err != nil {
-			return err
-		}
-
-		// Send usage information as a custom message
-		usage := map[string]interface{}{
-			"usage": openai.Usage{
-				PromptTokens:     promptTokens,
-				CompletionTokens: completionTokens,
-				TotalTokens:      totalTokens,
-			},
-		}
-
-		usageData, err := json.Marshal(usage)
-		if err != nil {
-			return err
-		}
-
-		_, err = w.ResponseWriter.Write([]byte(fmt.Sprintf("data: %s\n\n", usageData)))
-		if err != nil {
-			return err
-		}
-
-		// Final [DONE] marker for SSE
-		_, err = w.ResponseWriter.Write([]byte("data: [DONE]\n\n"))
-		if err != nil {
-			return err
-		}
+			Choices: []openai.ChatCompletionStreamChoice{},
+			Usage:   &usage,
+		},
+		OpenAiChatCompletionExtension: w.Extension,
+	})
+	if err != nil {
+		return err
+	}
 
-		w.Flush()
+	// Final [DONE] marker for SSE
+	_, err = w.ResponseWriter.Write([]byte("data: [DONE]\n\n"))
+	if err != nil {
+		return err
 	}
 
+	w.Flush()
 	return nil
 }
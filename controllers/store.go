@@ -45,7 +45,7 @@ func (c *ApiController) GetGlobalStores() {
 			return
 		}
 
-		c.ResponseOk(stores)
+		c.ResponseOk(object.GetMaskedStores(stores, c.GetSessionUser()))
 	} else {
 		if !c.RequireAdmin() {
 			return
@@ -75,7 +75,7 @@ func (c *ApiController) GetGlobalStores() {
 			return
 		}
 
-		c.ResponseOk(stores, paginator.Nums())
+		c.ResponseOk(object.GetMaskedStores(stores, c.GetSessionUser()), paginator.Nums())
 	}
 }
 
@@ -95,7 +95,7 @@ func (c *ApiController) GetStores() {
 		return
 	}
 
-	c.ResponseOk(stores)
+	c.ResponseOk(object.GetMaskedStores(stores, c.GetSessionUser()))
 }
 
 // GetStore
@@ -121,6 +121,8 @@ func (c *ApiController) GetStore() {
 	}
 
 	if store != nil {
+		store = object.GetMaskedStore(store, c.GetSessionUser())
+
 		host := c.Ctx.Request.Host
 		origin := getOriginFromHost(host)
 		err = store.Populate(origin)
@@ -128,6 +130,8 @@ func (c *ApiController) GetStore() {
 			c.ResponseOk(store, err.Error())
 			return
 		}
+
+		object.PopulateStoreWatchStatus(store)
 	}
 
 	c.ResponseOk(store)
@@ -157,6 +161,18 @@ func (c *ApiController) UpdateStore() {
 		return
 	}
 
+	_, err = getPromptCarriers(store.Carriers)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	err = object.CheckGuardrails(store.Guardrails)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	if oldStore.IsDefault && !store.IsDefault {
 		c.ResponseError("given that there must be one default store in Casibase, you cannot set this store to non-default. You can directly set another store as default")
 		return
@@ -205,6 +221,18 @@ func (c *ApiController) AddStore() {
 		return
 	}
 
+	_, err = getPromptCarriers(store.Carriers)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	err = object.CheckGuardrails(store.Guardrails)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	if store.ModelProvider == "" {
 		var modelProvider *object.Provider
 		modelProvider, err = object.GetDefaultModelProvider()
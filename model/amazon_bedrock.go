@@ -24,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 )
 
 type AmazonBedrockModelProvider struct {
@@ -107,18 +109,24 @@ func (p *AmazonBedrockModelProvider) calculatePrice(modelResult *ModelResult) er
 	return nil
 }
 
-func (p *AmazonBedrockModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("us-west-2"))
+func (p *AmazonBedrockModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-west-2"))
 	if err != nil {
 		return nil, err
 	}
 	client := bedrockruntime.NewFromConfig(cfg)
 
 	maxTokens := getContextLength(p.subType)
+	if options.MaxTokens > 0 {
+		maxTokens = options.MaxTokens
+	}
 
+	temperature := getSamplingValue(options.Temperature, float32(p.temperature))
 	requestBody, err := json.Marshal(map[string]interface{}{
 		"prompt":      prompt + question,
-		"temperature": p.temperature,
+		"temperature": temperature,
 		"max_tokens":  maxTokens,
 	})
 	if err != nil {
@@ -137,7 +145,11 @@ func (p *AmazonBedrockModelProvider) QueryText(question string, writer io.Writer
 		}
 	}
 
-	resp, err := client.InvokeModel(context.TODO(), &bedrockruntime.InvokeModelInput{
+	if tools := getAgentTools(agentInfo); len(tools) > 0 {
+		return p.queryTextWithConverse(ctx, client, question, writer, history, prompt, knowledgeMessages, agentInfo, maxTokens, temperature)
+	}
+
+	resp, err := client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
 		ModelId:     aws.String(p.subType),
 		Body:        requestBody,
 		ContentType: aws.String("application/json"),
@@ -169,3 +181,132 @@ func (p *AmazonBedrockModelProvider) QueryText(question string, writer io.Writer
 
 	return modelResult, nil
 }
+
+// queryTextWithConverse answers with the Converse API of Bedrock, which takes the tools of the agent in the same
+// form for all the models that support tool use
+func (p *AmazonBedrockModelProvider) queryTextWithConverse(ctx context.Context, client *bedrockruntime.Client, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, maxTokens int, temperature float32) (*ModelResult, error) {
+	var system []types.SystemContentBlock
+	for _, systemMessage := range getSystemMessages(prompt, knowledgeMessages) {
+		system = append(system, &types.SystemContentBlockMemberText{Value: systemMessage.Text})
+	}
+
+	// The messages must start with the user and alternate, so the history messages of the same role are joined
+	var messages []types.Message
+	appendMessage := func(role types.ConversationRole, content types.ContentBlock) {
+		if len(messages) > 0 && messages[len(messages)-1].Role == role {
+			messages[len(messages)-1].Content = append(messages[len(messages)-1].Content, content)
+			return
+		}
+		messages = append(messages, types.Message{Role: role, Content: []types.ContentBlock{content}})
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		role := types.ConversationRoleUser
+		if history[i].Author == "AI" {
+			role = types.ConversationRoleAssistant
+		}
+		if len(messages) == 0 && role == types.ConversationRoleAssistant {
+			continue
+		}
+		appendMessage(role, &types.ContentBlockMemberText{Value: history[i].Text})
+	}
+	appendMessage(types.ConversationRoleUser, &types.ContentBlockMemberText{Value: question})
+
+	for _, toolStep := range getAgentToolSteps(agentInfo) {
+		appendMessage(types.ConversationRoleAssistant, &types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+			ToolUseId: aws.String(toolStep.ToolCall.Id),
+			Name:      aws.String(toolStep.ToolCall.Name),
+			Input:     document.NewLazyDocument(toolStep.ToolCall.getArgumentsMap()),
+		}})
+		appendMessage(types.ConversationRoleUser, &types.ContentBlockMemberToolResult{Value: types.ToolResultBlock{
+			ToolUseId: aws.String(toolStep.ToolCall.Id),
+			Content:   []types.ToolResultContentBlock{&types.ToolResultContentBlockMemberText{Value: toolStep.Result}},
+		}})
+	}
+
+	toolConfig := &types.ToolConfiguration{}
+	for _, tool := range getAgentTools(agentInfo) {
+		parameters, err := getToolParameters(tool)
+		if err != nil {
+			return nil, err
+		}
+
+		toolSpec := types.ToolSpecification{
+			Name:        aws.String(tool.Name),
+			InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(parameters)},
+		}
+		if tool.Description != "" {
+			toolSpec.Description = aws.String(tool.Description)
+		}
+		toolConfig.Tools = append(toolConfig.Tools, &types.ToolMemberToolSpec{Value: toolSpec})
+	}
+
+	resp, err := client.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(p.subType),
+		Messages: messages,
+		System:   system,
+		InferenceConfig: &types.InferenceConfiguration{
+			MaxTokens:   aws.Int32(int32(maxTokens)),
+			Temperature: aws.Float32(temperature),
+		},
+		ToolConfig: toolConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, ok := resp.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected output of Bedrock: %T", resp.Output)
+	}
+
+	var answer strings.Builder
+	toolCalls := []ToolCall{}
+	for _, content := range output.Value.Content {
+		switch block := content.(type) {
+		case *types.ContentBlockMemberText:
+			answer.WriteString(block.Value)
+		case *types.ContentBlockMemberToolUse:
+			var arguments map[string]interface{}
+			if block.Value.Input != nil {
+				err = block.Value.Input.UnmarshalSmithyDocument(&arguments)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			argumentsBytes, err := json.Marshal(arguments)
+			if err != nil {
+				return nil, err
+			}
+			toolCalls = append(toolCalls, newToolCall(aws.ToString(block.Value.ToolUseId), aws.ToString(block.Value.Name), string(argumentsBytes)))
+		}
+	}
+
+	if answer.Len() > 0 {
+		err = flushDataThink(answer.String(), "message", writer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = setAgentToolCalls(agentInfo, toolCalls, writer)
+	if err != nil {
+		return nil, err
+	}
+
+	modelResult, err := getDefaultModelResult(p.subType, question, answer.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Usage != nil {
+		modelResult.PromptTokenCount = int(aws.ToInt32(resp.Usage.InputTokens))
+		modelResult.ResponseTokenCount = int(aws.ToInt32(resp.Usage.OutputTokens))
+		modelResult.TotalTokenCount = modelResult.PromptTokenCount + modelResult.ResponseTokenCount
+	}
+
+	if err := p.calculatePrice(modelResult); err != nil {
+		return nil, err
+	}
+
+	return modelResult, nil
+}
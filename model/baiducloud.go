@@ -126,8 +126,9 @@ func (p *BaiduCloudModelProvider) calculatePrice(modelResult *ModelResult) error
 	return nil
 }
 
-func (p *BaiduCloudModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	ctx := context.Background()
+func (p *BaiduCloudModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
 		return nil, fmt.Errorf("writer does not implement http.Flusher")
@@ -166,15 +167,20 @@ func (p *BaiduCloudModelProvider) QueryText(question string, writer io.Writer, h
 	modelResult.PromptTokenCount = promptTokenCount
 	modelResult.TotalTokenCount = modelResult.PromptTokenCount + modelResult.ResponseTokenCount
 
-	resp, err := chat.Stream(ctx, &qianfan.ChatCompletionV2Request{
+	request := &qianfan.ChatCompletionV2Request{
 		Model:       p.subType,
 		Messages:    messages,
-		Temperature: p.temperature,
-		TopP:        p.topP,
+		Temperature: float64(getSamplingValue(options.Temperature, float32(p.temperature))),
+		TopP:        float64(getSamplingValue(options.TopP, float32(p.topP))),
 		StreamOptions: &qianfan.StreamOptions{
 			IncludeUsage: true,
 		},
-	})
+	}
+	if options.MaxTokens > 0 {
+		request.MaxCompletionTokens = options.MaxTokens
+	}
+
+	resp, err := chat.Stream(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,6 +25,8 @@ import (
 	"github.com/leverly/ChatGLM/client"
 )
 
+// ChatGLMModelProvider answers with the asynchronous API of ChatGLM. Its client is not context-aware, so a stopped
+// answer is no longer waited for, but the generation goes on upstream and is still charged
 type ChatGLMModelProvider struct {
 	subType      string
 	clientSecret string
@@ -63,7 +66,7 @@ func (p *ChatGLMModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *ChatGLMModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *ChatGLMModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
 	proxy := client.NewChatGLMClient(p.clientSecret, 30*time.Second)
 	messages := []client.Message{{Role: "user", Content: question}}
 	taskId, err := proxy.AsyncInvoke(p.subType, 0.2, messages)
@@ -96,11 +99,22 @@ func (p *ChatGLMModelProvider) QueryText(question string, writer io.Writer, hist
 		}
 	}
 
+	// A canceled request is not waited for or written out, see ChatGLMModelProvider
+	err = ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := proxy.AsyncInvokeTask(p.subType, taskId)
 	if err != nil {
 		return nil, err
 	}
 
+	err = ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
 	content := (*response.Choices)[0].Content
 
 	err = flushData(content)
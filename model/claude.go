@@ -87,7 +87,14 @@ func (p *ClaudeModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+// supportsResponseSchema reports whether the answer can be forced into a tool input, which must be an object
+func (p *ClaudeModelProvider) supportsResponseSchema(schema *ResponseSchema) bool {
+	return schema.isObject()
+}
+
+func (p *ClaudeModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	client := anthropic.NewClient(
 		option.WithAPIKey(p.secretKey),
 		option.WithHTTPClient(proxy.ProxyHttpClient),
@@ -106,6 +113,9 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 	}
 
 	maxTokens := getContextLength(p.subType)
+	if options.MaxTokens > 0 {
+		maxTokens = options.MaxTokens
+	}
 
 	var textBlockList []anthropic.TextBlockParam
 	systemMessages := getSystemMessages(prompt, knowledgeMessages)
@@ -123,6 +133,13 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 	}
 	messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(question)))
 
+	// The tool calls of the previous rounds are replayed as tool uses of the assistant followed by their results
+	toolSteps := getAgentToolSteps(agentInfo)
+	for _, toolStep := range toolSteps {
+		messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewToolUseBlock(toolStep.ToolCall.Id, toolStep.ToolCall.getArgumentsMap(), toolStep.ToolCall.Name)))
+		messages = append(messages, anthropic.NewUserMessage(anthropic.NewToolResultBlock(toolStep.ToolCall.Id, toolStep.Result, false)))
+	}
+
 	messageParams := anthropic.MessageNewParams{
 		MaxTokens:     int64(maxTokens),
 		Messages:      messages,
@@ -130,14 +147,53 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		StopSequences: []string{"```\n"},
 		System:        textBlockList,
 	}
-	if p.enableThinking {
+	if options.Temperature != nil {
+		messageParams.Temperature = anthropic.Float(float64(*options.Temperature))
+	}
+	if options.TopP != nil {
+		messageParams.TopP = anthropic.Float(float64(*options.TopP))
+	}
+	if options.TopK != nil {
+		messageParams.TopK = anthropic.Int(int64(*options.TopK))
+	}
+	schema := options.ResponseSchema
+	isAgent := false
+	if schema != nil && schema.isObject() {
+		// The answer is forced into the input of a tool that takes the schema, thinking is not allowed then
+		inputSchema := getClaudeToolInputSchema(schema.Schema)
+		messageParams.Tools = []anthropic.ToolUnionParam{{OfTool: &anthropic.ToolParam{Name: schema.getName(), InputSchema: inputSchema}}}
+		messageParams.ToolChoice = anthropic.ToolChoiceUnionParam{OfTool: &anthropic.ToolChoiceToolParam{Name: schema.getName()}}
+	} else if tools := getAgentTools(agentInfo); len(tools) > 0 {
+		isAgent = true
+		for _, tool := range tools {
+			parameters, err := getToolParameters(tool)
+			if err != nil {
+				return nil, err
+			}
+
+			toolParam := &anthropic.ToolParam{Name: tool.Name, InputSchema: getClaudeToolInputSchema(parameters)}
+			if tool.Description != "" {
+				toolParam.Description = anthropic.String(tool.Description)
+			}
+			messageParams.Tools = append(messageParams.Tools, anthropic.ToolUnionParam{OfTool: toolParam})
+		}
+	}
+
+	// Thinking needs the thinking blocks of the previous rounds to be sent back with the tool uses, which are not
+	// kept, so it is only enabled before any tool is called
+	if messageParams.ToolChoice.OfTool == nil && p.enableThinking && len(toolSteps) == 0 {
+		budgetTokens := p.budgetTokens
+		if options.ThinkingBudget > 0 {
+			budgetTokens = options.ThinkingBudget
+		}
+
 		messageParams.Thinking = anthropic.ThinkingConfigParamUnion{
 			OfEnabled: &anthropic.ThinkingConfigEnabledParam{
-				BudgetTokens: int64(p.budgetTokens),
+				BudgetTokens: int64(budgetTokens),
 			},
 		}
 	}
-	stream := client.Messages.NewStreaming(context.TODO(), messageParams)
+	stream := client.Messages.NewStreaming(ctx, messageParams)
 
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
@@ -153,6 +209,9 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 	}
 
 	modelResult := &ModelResult{}
+	toolCalls := []ToolCall{}
+	toolCallArguments := map[int64]*strings.Builder{}
+	toolCallIndexes := map[int64]int{}
 	for stream.Next() {
 		event := stream.Current()
 
@@ -160,7 +219,20 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		case anthropic.MessageStartEvent:
 			inputTokens := int(eventVariant.Message.Usage.InputTokens)
 			modelResult.PromptTokenCount = inputTokens
+		case anthropic.ContentBlockStartEvent:
+			if toolUse, ok := eventVariant.ContentBlock.AsAny().(anthropic.ToolUseBlock); ok && isAgent {
+				toolCallIndexes[eventVariant.Index] = len(toolCalls)
+				toolCallArguments[eventVariant.Index] = &strings.Builder{}
+				toolCalls = append(toolCalls, ToolCall{Id: toolUse.ID, Name: toolUse.Name})
+			}
 		case anthropic.ContentBlockDeltaEvent:
+			if arguments, ok := toolCallArguments[eventVariant.Index]; ok {
+				if deltaVariant, ok := eventVariant.Delta.AsAny().(anthropic.InputJSONDelta); ok {
+					arguments.WriteString(deltaVariant.PartialJSON)
+				}
+				continue
+			}
+
 			switch deltaVariant := eventVariant.Delta.AsAny().(type) {
 			case anthropic.ThinkingDelta:
 				err := flushData("reason", deltaVariant.Thinking)
@@ -172,6 +244,11 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 				if err != nil {
 					return nil, err
 				}
+			case anthropic.InputJSONDelta:
+				err := flushData("message", deltaVariant.PartialJSON)
+				if err != nil {
+					return nil, err
+				}
 			}
 		case anthropic.MessageDeltaEvent:
 			outputTokens := int(eventVariant.Usage.OutputTokens)
@@ -189,5 +266,38 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		return nil, err
 	}
 
+	if isAgent {
+		for index, i := range toolCallIndexes {
+			toolCalls[i] = newToolCall(toolCalls[i].Id, toolCalls[i].Name, toolCallArguments[index].String())
+		}
+
+		err = setAgentToolCalls(agentInfo, toolCalls, writer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return modelResult, nil
 }
+
+// getClaudeToolInputSchema converts a JSON schema object to the input schema of a Claude tool
+func getClaudeToolInputSchema(schema map[string]interface{}) anthropic.ToolInputSchemaParam {
+	inputSchema := anthropic.ToolInputSchemaParam{
+		Properties:  schema["properties"],
+		ExtraFields: map[string]any{},
+	}
+	for key, value := range schema {
+		if key == "required" {
+			if required, ok := value.([]interface{}); ok {
+				for _, item := range required {
+					if name, ok := item.(string); ok {
+						inputSchema.Required = append(inputSchema.Required, name)
+					}
+				}
+			}
+		} else if key != "type" && key != "properties" {
+			inputSchema.ExtraFields[key] = value
+		}
+	}
+	return inputSchema
+}
@@ -90,11 +90,12 @@ func (p *CohereModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *CohereModelProvider) QueryText(message string, writer io.Writer, chat_history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *CohereModelProvider) QueryText(ctx context.Context, message string, writer io.Writer, chat_history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	client := cohereclient.NewClient(
 		cohereclient.WithToken(p.secretKey),
 	)
-	ctx := context.Background()
 
 	// if p.maxTokens > 0, use p.maxTokens, otherwise use model's default Maxtokens
 	maxTokens := getContextLength(p.subType)
@@ -109,6 +110,10 @@ func (p *CohereModelProvider) QueryText(message string, writer io.Writer, chat_h
 			return nil, fmt.Errorf("exceed max tokens")
 		}
 	}
+	if options.MaxTokens > 0 {
+		maxTokens = options.MaxTokens
+	}
+
 	generation, err := client.Generate(
 		ctx,
 		&cohere.GenerateRequest{
@@ -15,6 +15,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"io"
 )
@@ -65,7 +66,7 @@ func (p *DeepSeekProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *DeepSeekProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *DeepSeekProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
 	const BaseUrl = "https://api.deepseek.com/v1"
 
 	var localType string
@@ -79,7 +80,7 @@ func (p *DeepSeekProvider) QueryText(question string, writer io.Writer, history
 		return nil, err
 	}
 
-	modelResult, err := localProvider.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo)
+	modelResult, err := localProvider.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,7 @@
 package model
 
 import (
+	"context"
 	"io"
 	"strings"
 )
@@ -39,7 +40,7 @@ This is a dummy module provider.
 `
 }
 
-func (p *DummyModelProvider) QueryText(message string, writer io.Writer, chat_history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *DummyModelProvider) QueryText(ctx context.Context, message string, writer io.Writer, chat_history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
 	answer := "this is the answer for \"" + message + "\""
 	if strings.HasPrefix(message, "$CasibaseDryRun$") {
 		return &ModelResult{}, nil
@@ -16,6 +16,7 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -202,8 +203,13 @@ func (p *GeminiModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	ctx := context.Background()
+func (p *GeminiModelProvider) supportsResponseSchema(schema *ResponseSchema) bool {
+	return true
+}
+
+func (p *GeminiModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	// Access your API key as an environment variable (see "Set up your API key" above)
 	client, err := genai.NewClient(ctx,
 		&genai.ClientConfig{
@@ -239,11 +245,51 @@ func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, histo
 		return nil, err
 	}
 
+	var config *genai.GenerateContentConfig
+	if options.MaxTokens > 0 {
+		config = &genai.GenerateContentConfig{MaxOutputTokens: int32(options.MaxTokens)}
+	}
+	if options.Temperature != nil || options.TopP != nil || options.TopK != nil {
+		if config == nil {
+			config = &genai.GenerateContentConfig{}
+		}
+		config.Temperature = options.Temperature
+		config.TopP = options.TopP
+		if options.TopK != nil {
+			config.TopK = genai.Ptr(float32(*options.TopK))
+		}
+	}
+	if schema := options.ResponseSchema; schema != nil {
+		if config == nil {
+			config = &genai.GenerateContentConfig{}
+		}
+		config.ResponseMIMEType = "application/json"
+		config.ResponseSchema = JsonSchemaToGenaiSchema(schema.Schema)
+	}
+
 	messages := GenaiRawMessagesToMessages(question, history)
-	resp, err := model.GenerateContent(ctx, p.subType, messages, nil)
+	messages = append(messages, GenaiAgentToolStepsToMessages(getAgentToolSteps(agentInfo))...)
+
+	tools := getAgentTools(agentInfo)
+	if len(tools) > 0 {
+		if config == nil {
+			config = &genai.GenerateContentConfig{}
+		}
+
+		functionDeclarations, err := GenaiToolsToFunctionDeclarations(tools)
+		if err != nil {
+			return nil, err
+		}
+		config.Tools = []*genai.Tool{{FunctionDeclarations: functionDeclarations}}
+	}
+
+	resp, err := model.GenerateContent(ctx, p.subType, messages, config)
 	if err != nil {
 		return nil, err
 	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil, fmt.Errorf("no candidates in the response of Gemini")
+	}
 
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
@@ -252,6 +298,9 @@ func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, histo
 
 	flushData := func(data []*genai.Part) error {
 		for _, message := range data {
+			if message.Text == "" {
+				continue
+			}
 			if _, err := fmt.Fprintf(writer, "event: message\ndata: %s\n\n", message.Text); err != nil {
 				return err
 			}
@@ -278,5 +327,21 @@ func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, histo
 		return nil, err
 	}
 
+	if len(tools) > 0 {
+		toolCalls := []ToolCall{}
+		for _, functionCall := range resp.FunctionCalls() {
+			arguments, err := json.Marshal(functionCall.Args)
+			if err != nil {
+				return nil, err
+			}
+			toolCalls = append(toolCalls, newToolCall(functionCall.ID, functionCall.Name, string(arguments)))
+		}
+
+		err = setAgentToolCalls(agentInfo, toolCalls, writer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return modelResult, nil
 }
@@ -14,7 +14,14 @@
 
 package model
 
-import genai "google.golang.org/genai"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	genai "google.golang.org/genai"
+)
 
 func GenaiRawMessagesToMessages(question string, history []*RawMessage) []*genai.Content {
 	var messages []*genai.Content
@@ -34,3 +41,112 @@ func GenaiRawMessagesToMessages(question string, history []*RawMessage) []*genai
 	})
 	return messages
 }
+
+// GenaiAgentToolStepsToMessages replays the tool calls of the previous rounds as function calls of the model
+// followed by their responses
+func GenaiAgentToolStepsToMessages(toolSteps []*agentToolStep) []*genai.Content {
+	var messages []*genai.Content
+	for _, toolStep := range toolSteps {
+		functionCall := genai.NewPartFromFunctionCall(toolStep.ToolCall.Name, toolStep.ToolCall.getArgumentsMap())
+		functionCall.FunctionCall.ID = toolStep.ToolCall.Id
+		messages = append(messages, genai.NewContentFromParts([]*genai.Part{functionCall}, genai.RoleModel))
+
+		// The response must be an object, the result is wrapped in it unless it is one already
+		response := map[string]any{}
+		err := json.Unmarshal([]byte(toolStep.Result), &response)
+		if err != nil {
+			response = map[string]any{"output": toolStep.Result}
+		}
+		functionResponse := genai.NewPartFromFunctionResponse(toolStep.ToolCall.Name, response)
+		functionResponse.FunctionResponse.ID = toolStep.ToolCall.Id
+		messages = append(messages, genai.NewContentFromParts([]*genai.Part{functionResponse}, genai.RoleUser))
+	}
+	return messages
+}
+
+// GenaiToolsToFunctionDeclarations converts the tools of the agent clients to the function declarations of Gemini
+func GenaiToolsToFunctionDeclarations(tools []*protocol.Tool) ([]*genai.FunctionDeclaration, error) {
+	res := []*genai.FunctionDeclaration{}
+	for _, tool := range tools {
+		parameters, err := getToolParameters(tool)
+		if err != nil {
+			return nil, err
+		}
+
+		functionDeclaration := &genai.FunctionDeclaration{
+			Name:        tool.Name,
+			Description: tool.Description,
+		}
+		// Gemini refuses an object schema without properties, so a tool without parameters has no schema
+		if properties, ok := parameters["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+			functionDeclaration.Parameters = JsonSchemaToGenaiSchema(parameters)
+		}
+		res = append(res, functionDeclaration)
+	}
+	return res, nil
+}
+
+// JsonSchemaToGenaiSchema converts a JSON schema to the OpenAPI subset that Gemini accepts as the response schema
+func JsonSchemaToGenaiSchema(schema map[string]interface{}) *genai.Schema {
+	res := &genai.Schema{}
+	if schema == nil {
+		return res
+	}
+
+	res.Description, _ = schema["description"].(string)
+	res.Format, _ = schema["format"].(string)
+	res.Title, _ = schema["title"].(string)
+
+	for _, typ := range getJsonSchemaTypes(schema) {
+		if typ == "null" {
+			nullable := true
+			res.Nullable = &nullable
+		} else {
+			res.Type = genai.Type(strings.ToUpper(typ))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, item := range enum {
+			res.Enum = append(res.Enum, fmt.Sprint(item))
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		res.Properties = map[string]*genai.Schema{}
+		for name, property := range properties {
+			propertySchema, _ := property.(map[string]interface{})
+			res.Properties[name] = JsonSchemaToGenaiSchema(propertySchema)
+		}
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok {
+				res.Required = append(res.Required, name)
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		res.Items = JsonSchemaToGenaiSchema(items)
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if subSchemas, ok := schema[key].([]interface{}); ok {
+			for _, subSchema := range subSchemas {
+				subSchemaMap, _ := subSchema.(map[string]interface{})
+				res.AnyOf = append(res.AnyOf, JsonSchemaToGenaiSchema(subSchemaMap))
+			}
+		}
+	}
+
+	if minimum, ok := getJsonSchemaNumber(schema, "minimum"); ok {
+		res.Minimum = &minimum
+	}
+	if maximum, ok := getJsonSchemaNumber(schema, "maximum"); ok {
+		res.Maximum = &maximum
+	}
+
+	return res
+}
@@ -47,8 +47,9 @@ func (p *HuggingFaceModelProvider) calculatePrice(modelResult *ModelResult) erro
 	return nil
 }
 
-func (p *HuggingFaceModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	ctx := context.Background()
+func (p *HuggingFaceModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	client := huggingface.NewInferenceClient(p.secretKey, func(o *huggingface.InferenceClientOptions) {
 		o.HTTPClient = proxy.ProxyHttpClient
 	})
@@ -65,11 +66,16 @@ func (p *HuggingFaceModelProvider) QueryText(question string, writer io.Writer,
 		}
 	}
 
+	parameters := huggingface.TextGenerationParameters{
+		Temperature: huggingface.PTR(float64(getSamplingValue(options.Temperature, p.temperature))),
+	}
+	if options.MaxTokens > 0 {
+		parameters.MaxNewTokens = huggingface.PTR(options.MaxTokens)
+	}
+
 	resp, err := client.TextGeneration(ctx, &huggingface.TextGenerationRequest{
-		Inputs: question,
-		Parameters: huggingface.TextGenerationParameters{
-			Temperature: huggingface.PTR(float64(p.temperature)),
-		},
+		Inputs:     question,
+		Parameters: parameters,
 		Options: huggingface.Options{
 			WaitForModel: huggingface.PTR(true),
 		},
@@ -184,13 +184,14 @@ func (p *iFlytekModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *iFlytekModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *iFlytekModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	baseUrl, domain, err := p.getBaseUrl()
 	_, client, err := spark.NewClient(spark.WithBaseURL(baseUrl), spark.WithApiKey(p.apiKey), spark.WithApiSecret(p.secretKey), spark.WithAppId(p.appID), spark.WithAPIDomain(domain))
 	if err != nil {
 		return nil, err
 	}
-	ctx := context.Background()
 
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
@@ -214,6 +215,10 @@ func (p *iFlytekModelProvider) QueryText(question string, writer io.Writer, hist
 		Domain:   &domain,
 		Messages: chatMessages,
 	}
+	if options.MaxTokens > 0 {
+		maxTokens := int64(options.MaxTokens)
+		r.MaxTokens = &maxTokens
+	}
 
 	flushData := func(data string) error {
 		if _, err = fmt.Fprintf(writer, "event: message\ndata: %s\n\n", data); err != nil {
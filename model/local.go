@@ -17,6 +17,7 @@ package model
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
@@ -43,6 +44,7 @@ type LocalModelProvider struct {
 	inputPricePerThousandTokens  float64
 	outputPricePerThousandTokens float64
 	currency                     string
+	isOllama                     bool
 }
 
 func NewLocalModelProvider(typ string, subType string, secretKey string, temperature float32, topP float32, frequencyPenalty float32, presencePenalty float32, providerUrl string, compatibleProvider string, inputPricePerThousandTokens float64, outputPricePerThousandTokens float64, Currency string) (*LocalModelProvider, error) {
@@ -63,6 +65,22 @@ func NewLocalModelProvider(typ string, subType string, secretKey string, tempera
 	return p, nil
 }
 
+// newOllamaModelProvider returns a provider for the OpenAI-compatible API of Ollama, which maps the JSON schema
+// response format to its "format" option
+func newOllamaModelProvider(subType string, temperature float32, topP float32, providerUrl string, inputPricePerThousandTokens float64, outputPricePerThousandTokens float64, Currency string) (*LocalModelProvider, error) {
+	p, err := NewLocalModelProvider("Custom-think", "custom-model", "randomString", temperature, topP, 0, 0, providerUrl, subType, inputPricePerThousandTokens, outputPricePerThousandTokens, Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	p.isOllama = true
+	return p, nil
+}
+
+func (p *LocalModelProvider) supportsResponseSchema(schema *ResponseSchema) bool {
+	return p.isOllama
+}
+
 func getLocalClientFromUrl(authToken string, url string) *openai.Client {
 	config := openai.DefaultConfig(authToken)
 	config.BaseURL = url
@@ -156,7 +174,9 @@ func flushDataThink(data string, eventType string, writer io.Writer) error {
 	return nil
 }
 
-func (p *LocalModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *LocalModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	var client *openai.Client
 	var flushData interface{} // Can be either flushData or flushDataThink
 
@@ -177,7 +197,6 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 		flushData = flushDataThink
 	}
 
-	ctx := context.Background()
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
 		return nil, fmt.Errorf("writer does not implement http.Flusher")
@@ -190,10 +209,10 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 		model = "gpt-3.5-turbo"
 	}
 
-	temperature := p.temperature
-	topP := p.topP
-	frequencyPenalty := p.frequencyPenalty
-	presencePenalty := p.presencePenalty
+	temperature := getOpenAiSamplingValue(options.Temperature, p.temperature)
+	topP := getOpenAiSamplingValue(options.TopP, p.topP)
+	frequencyPenalty := getOpenAiSamplingValue(options.FrequencyPenalty, p.frequencyPenalty)
+	presencePenalty := getOpenAiSamplingValue(options.PresencePenalty, p.presencePenalty)
 
 	maxTokens := getContextLength(model)
 
@@ -239,6 +258,23 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 		}
 
 		req := ChatCompletionRequest(model, messages, temperature, topP, frequencyPenalty, presencePenalty)
+		if options.MaxTokens > 0 {
+			req.MaxTokens = options.MaxTokens
+		}
+		if schema := options.ResponseSchema; schema != nil && p.isOllama {
+			schemaBytes, err := json.Marshal(schema.Schema)
+			if err != nil {
+				return nil, err
+			}
+
+			req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   schema.getName(),
+					Schema: json.RawMessage(schemaBytes),
+				},
+			}
+		}
 		if agentInfo != nil && agentInfo.AgentClients != nil {
 			tools, err := reverseToolsToOpenAi(agentInfo.AgentClients.Tools)
 			if err != nil {
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/ThinkInAIXYZ/go-mcp/client"
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
@@ -35,8 +37,40 @@ type AgentMessages struct {
 type AgentInfo struct {
 	AgentClients  *agent.AgentClients
 	AgentMessages *AgentMessages
+	// ApproveToolCall asks the user whether a tool call with the "Approval" policy can be made, such calls are
+	// denied if it is nil
+	ApproveToolCall func(ctx context.Context, approval *ToolApproval) (bool, error)
+	// AgentSteps are the tool calls made for the answer, OnAgentStep is called after each of them if it is not nil
+	AgentSteps  []*AgentStep
+	OnAgentStep func(step *AgentStep) error
 }
 
+// AgentStep is a tool call made by the agent for an answer, together with its result
+type AgentStep struct {
+	ToolCallId  string `json:"toolCallId"`
+	ToolName    string `json:"toolName"`
+	Arguments   string `json:"arguments"`
+	Result      string `json:"result"`
+	Error       string `json:"error"`
+	Duration    int64  `json:"duration"`
+	CreatedTime string `json:"createdTime"`
+}
+
+// ToolApproval is a tool call that waits for the approval of the user, its state is one of "Pending", "Approved"
+// and "Rejected"
+type ToolApproval struct {
+	ToolCallId string `json:"toolCallId"`
+	ToolName   string `json:"toolName"`
+	Arguments  string `json:"arguments"`
+	State      string `json:"state"`
+}
+
+const (
+	ToolApprovalStatePending  = "Pending"
+	ToolApprovalStateApproved = "Approved"
+	ToolApprovalStateRejected = "Rejected"
+)
+
 type ToolCallResponse struct {
 	Success  bool        `json:"success"`
 	Data     interface{} `json:"data"`
@@ -47,15 +81,10 @@ type ToolCallResponse struct {
 func reverseToolsToOpenAi(tools []*protocol.Tool) ([]openai.Tool, error) {
 	var openaiTools []openai.Tool
 	for _, tool := range tools {
-		schemaBytes, err := json.Marshal(tool.InputSchema)
+		parameters, err := getToolParameters(tool)
 		if err != nil {
 			return nil, err
 		}
-
-		var parameters map[string]interface{}
-		if err := json.Unmarshal(schemaBytes, &parameters); err != nil {
-			return nil, err
-		}
 		openaiTools = append(openaiTools, openai.Tool{
 			Type: "function",
 			Function: &openai.FunctionDefinition{
@@ -68,6 +97,71 @@ func reverseToolsToOpenAi(tools []*protocol.Tool) ([]openai.Tool, error) {
 	return openaiTools, nil
 }
 
+// OpenaiToolsToMcpTools converts the function tools of an OpenAI chat request into the tool representation of the agent clients
+func OpenaiToolsToMcpTools(tools []openai.Tool) ([]*protocol.Tool, error) {
+	res := []*protocol.Tool{}
+	for _, tool := range tools {
+		if tool.Type != openai.ToolTypeFunction || tool.Function == nil {
+			return nil, fmt.Errorf("the tool type: %s is not supported", tool.Type)
+		}
+
+		inputSchema := protocol.InputSchema{Type: protocol.Object}
+		if tool.Function.Parameters != nil {
+			schemaBytes, err := json.Marshal(tool.Function.Parameters)
+			if err != nil {
+				return nil, err
+			}
+
+			err = json.Unmarshal(schemaBytes, &inputSchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the parameters of tool: %s, %v", tool.Function.Name, err)
+			}
+		}
+
+		res = append(res, &protocol.Tool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: inputSchema,
+		})
+	}
+	return res, nil
+}
+
+// GetToolCalls returns the tool calls requested by the model in the last query, whichever API the provider uses
+func GetToolCalls(agentMessages *AgentMessages) []openai.ToolCall {
+	if agentMessages == nil || agentMessages.ToolCalls == nil {
+		return []openai.ToolCall{}
+	}
+
+	if toolCalls, ok := agentMessages.ToolCalls.([]openai.ToolCall); ok {
+		return toolCalls
+	}
+
+	res := []openai.ToolCall{}
+	if toolCalls, ok := agentMessages.ToolCalls.([]ToolCall); ok {
+		for _, toolCall := range toolCalls {
+			res = append(res, toolCall.toOpenAi())
+		}
+		return res
+	}
+
+	if responseFunctionToolCalls, ok := agentMessages.ToolCalls.([]responses.ResponseFunctionToolCall); ok {
+		for _, responseFunctionToolCall := range responseFunctionToolCalls {
+			id := responseFunctionToolCall.CallID
+			if id == "" {
+				id = responseFunctionToolCall.ID
+			}
+
+			res = append(res, openai.ToolCall{
+				ID:       id,
+				Type:     "function",
+				Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
+			})
+		}
+	}
+	return res
+}
+
 func handleToolCalls(toolCalls []openai.ToolCall, flushData interface{}, writer io.Writer) error {
 	if toolCalls == nil {
 		return nil
@@ -89,6 +183,15 @@ func handleToolCallsParameters(toolCall openai.ToolCall, toolCalls []openai.Tool
 		toolCallsMap = make(map[int]int)
 	}
 
+	// Ollama may send each tool call whole without an index
+	if toolCall.Index == nil {
+		if toolCall.ID == "" {
+			toolCall.ID = newToolCall("", toolCall.Function.Name, toolCall.Function.Arguments).Id
+		}
+		toolCalls = append(toolCalls, toolCall)
+		return toolCalls, toolCallsMap
+	}
+
 	idx := *toolCall.Index
 	if existingIdx, exists := toolCallsMap[idx]; exists {
 		if toolCall.Function.Name != "" {
@@ -105,9 +208,11 @@ func handleToolCallsParameters(toolCall openai.ToolCall, toolCalls []openai.Tool
 	return toolCalls, toolCallsMap
 }
 
-func QueryTextWithTools(p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func QueryTextWithTools(ctx context.Context, p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	defer agentInfo.AgentClients.Close()
+
 	var messages []*RawMessage
-	modelResult, err := p.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo)
+	modelResult, err := p.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
 	if err != nil {
 		return nil, err
 	}
@@ -116,17 +221,7 @@ func QueryTextWithTools(p ModelProvider, question string, writer io.Writer, hist
 		return modelResult, nil
 	}
 
-	toolCalls, ok := agentInfo.AgentMessages.ToolCalls.([]openai.ToolCall)
-	if !ok {
-		responseFunctionToolCalls := agentInfo.AgentMessages.ToolCalls.([]responses.ResponseFunctionToolCall)
-		for _, responseFunctionToolCall := range responseFunctionToolCalls {
-			toolCalls = append(toolCalls, openai.ToolCall{
-				ID:       responseFunctionToolCall.ID,
-				Type:     "function",
-				Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
-			})
-		}
-	}
+	toolCalls := GetToolCalls(agentInfo.AgentMessages)
 
 	for len(toolCalls) > 0 {
 		for _, toolCall := range toolCalls {
@@ -143,33 +238,45 @@ func QueryTextWithTools(p ModelProvider, question string, writer io.Writer, hist
 				ToolCall: toolCall,
 			})
 
-			messages, err = callTools(toolCall, toolName, mcpClient, messages)
+			step := &AgentStep{
+				ToolCallId:  toolCall.ID,
+				ToolName:    toolCall.Function.Name,
+				Arguments:   toolCall.Function.Arguments,
+				CreatedTime: time.Now().Format(time.RFC3339),
+			}
+			startTime := time.Now()
+
+			rejection, err := checkToolCallPolicy(ctx, toolCall, agentInfo)
+			if err != nil {
+				return nil, err
+			}
+			if rejection != "" {
+				messages = append(messages, createToolErrorMessage(toolCall, rejection))
+				step.Error = rejection
+			} else {
+				var response *ToolCallResponse
+				messages, response, err = callTools(ctx, toolCall, toolName, mcpClient, messages)
+				if err != nil {
+					return nil, err
+				}
+				step.Result = getToolResultText(response.Data)
+				step.Error = response.Error
+			}
+
+			step.Duration = time.Since(startTime).Milliseconds()
+			err = recordAgentStep(agentInfo, step)
 			if err != nil {
 				return nil, err
 			}
 		}
 		agentInfo.AgentMessages.Messages = messages
-		modelResult, err = p.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo)
+		modelResult, err = p.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
 		if err != nil {
 			return nil, err
 		}
-		toolCalls, ok = agentInfo.AgentMessages.ToolCalls.([]openai.ToolCall)
-		if !ok {
-			toolCalls = []openai.ToolCall{}
-			responseFunctionToolCalls := agentInfo.AgentMessages.ToolCalls.([]responses.ResponseFunctionToolCall)
-			for _, responseFunctionToolCall := range responseFunctionToolCalls {
-				toolCalls = append(toolCalls, openai.ToolCall{
-					ID:       responseFunctionToolCall.ID,
-					Type:     "function",
-					Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
-				})
-			}
-		}
+		toolCalls = GetToolCalls(agentInfo.AgentMessages)
 	}
 
-	for _, mcpClient := range agentInfo.AgentClients.Clients {
-		mcpClient.Close()
-	}
 	return modelResult, nil
 }
 
@@ -181,12 +288,120 @@ func createToolMessage(toolCall openai.ToolCall, text string) *RawMessage {
 	}
 }
 
-func callTools(toolCall openai.ToolCall, functionName string, mcpClient *client.Client, messages []*RawMessage) ([]*RawMessage, error) {
+// checkToolCallPolicy returns why the tool call can't be made according to its policy, or an empty string if it can
+func checkToolCallPolicy(ctx context.Context, toolCall openai.ToolCall, agentInfo *AgentInfo) (string, error) {
+	switch agentInfo.AgentClients.GetToolPolicy(toolCall.Function.Name) {
+	case agent.ToolPolicyDeny:
+		return "The tool call is denied by the policy of the tool", nil
+	case agent.ToolPolicyApproval:
+		if agentInfo.ApproveToolCall == nil {
+			return "The tool call requires the approval of the user, which is not available here", nil
+		}
+
+		approval := &ToolApproval{
+			ToolCallId: toolCall.ID,
+			ToolName:   toolCall.Function.Name,
+			Arguments:  toolCall.Function.Arguments,
+		}
+		approved, err := agentInfo.ApproveToolCall(ctx, approval)
+		if err != nil {
+			return "", err
+		}
+		if !approved {
+			return "The tool call is rejected by the user", nil
+		}
+		return "", nil
+	default:
+		return "", nil
+	}
+}
+
+func createToolErrorMessage(toolCall openai.ToolCall, errorText string) *RawMessage {
+	response := &ToolCallResponse{
+		Success:  false,
+		Error:    errorText,
+		ToolName: toolCall.Function.Name,
+	}
+
+	responseJson, err := json.Marshal(response)
+	if err != nil {
+		return createToolMessage(toolCall, errorText)
+	}
+	return createToolMessage(toolCall, string(responseJson))
+}
+
+func recordAgentStep(agentInfo *AgentInfo, step *AgentStep) error {
+	agentInfo.AgentSteps = append(agentInfo.AgentSteps, step)
+	if agentInfo.OnAgentStep != nil {
+		return agentInfo.OnAgentStep(step)
+	}
+	return nil
+}
+
+const agentStepTextLimit = 500
+
+func truncateAgentStepText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= agentStepTextLimit {
+		return text
+	}
+	return string(runes[:agentStepTextLimit]) + "..."
+}
+
+// GetAgentStepsText describes the tool calls made for a previous answer, it is put before the answer in the history
+// so that the model knows what it already called
+func GetAgentStepsText(steps []*AgentStep) string {
+	if len(steps) == 0 {
+		return ""
+	}
+
+	lines := []string{"[Tools called for this answer]"}
+	for i, step := range steps {
+		line := fmt.Sprintf("%d. %s(%s)", i+1, step.ToolName, truncateAgentStepText(step.Arguments))
+		if step.Error != "" {
+			line += " failed: " + truncateAgentStepText(step.Error)
+		} else {
+			line += " returned: " + truncateAgentStepText(step.Result)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getToolResultText returns the text of the content returned by a tool, the content other than text is kept as JSON
+func getToolResultText(data interface{}) string {
+	if data == nil {
+		return ""
+	}
+
+	if contents, ok := data.([]protocol.Content); ok {
+		texts := []string{}
+		for _, content := range contents {
+			if textContent, ok := content.(*protocol.TextContent); ok {
+				texts = append(texts, textContent.Text)
+				continue
+			}
+
+			contentBytes, err := json.Marshal(content)
+			if err == nil {
+				texts = append(texts, string(contentBytes))
+			}
+		}
+		return strings.Join(texts, "\n")
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+	return string(dataBytes)
+}
+
+func callTools(ctx context.Context, toolCall openai.ToolCall, functionName string, mcpClient *client.Client, messages []*RawMessage) ([]*RawMessage, *ToolCallResponse, error) {
 	var arguments map[string]interface{}
-	ctx := context.Background()
 
 	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &arguments); err != nil {
-		return nil, fmt.Errorf("failed to parse tool arguments: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse tool arguments: %v", err)
 	}
 
 	req := &protocol.CallToolRequest{
@@ -212,9 +427,9 @@ func callTools(toolCall openai.ToolCall, functionName string, mcpClient *client.
 
 	responseJson, err := json.Marshal(response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal tool response: %v", err)
+		return nil, nil, fmt.Errorf("failed to marshal tool response: %v", err)
 	}
 
 	messages = append(messages, createToolMessage(toolCall, string(responseJson)))
-	return messages, nil
+	return messages, response, nil
 }
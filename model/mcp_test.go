@@ -0,0 +1,108 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/agent"
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestCheckToolCallPolicy(t *testing.T) {
+	agentClients := &agent.AgentClients{
+		Policies: map[string]string{
+			"tickets__close_ticket":  agent.ToolPolicyApproval,
+			"deploy__delete_cluster": agent.ToolPolicyDeny,
+		},
+	}
+	newToolCall := func(name string) openai.ToolCall {
+		return openai.ToolCall{ID: "call_1", Function: openai.FunctionCall{Name: name, Arguments: `{"id": 1}`}}
+	}
+
+	rejection, err := checkToolCallPolicy(context.Background(), newToolCall("tickets__get_ticket"), &AgentInfo{AgentClients: agentClients})
+	if err != nil || rejection != "" {
+		t.Errorf("the tool without a policy should be called automatically, got: %q, %v", rejection, err)
+	}
+
+	rejection, err = checkToolCallPolicy(context.Background(), newToolCall("deploy__delete_cluster"), &AgentInfo{AgentClients: agentClients})
+	if err != nil || rejection == "" {
+		t.Errorf("the denied tool should be rejected, got: %q, %v", rejection, err)
+	}
+
+	rejection, err = checkToolCallPolicy(context.Background(), newToolCall("tickets__close_ticket"), &AgentInfo{AgentClients: agentClients})
+	if err != nil || rejection == "" {
+		t.Errorf("the tool requiring approval should be rejected without an approver, got: %q, %v", rejection, err)
+	}
+
+	var gotApproval *ToolApproval
+	for _, approved := range []bool{true, false} {
+		agentInfo := &AgentInfo{
+			AgentClients: agentClients,
+			ApproveToolCall: func(ctx context.Context, approval *ToolApproval) (bool, error) {
+				gotApproval = approval
+				return approved, nil
+			},
+		}
+		rejection, err = checkToolCallPolicy(context.Background(), newToolCall("tickets__close_ticket"), agentInfo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (rejection == "") != approved {
+			t.Errorf("approved: %v, got rejection: %q", approved, rejection)
+		}
+		if gotApproval == nil || gotApproval.ToolCallId != "call_1" || gotApproval.Arguments != `{"id": 1}` {
+			t.Errorf("unexpected approval: %+v", gotApproval)
+		}
+	}
+}
+
+func TestGetAgentStepsText(t *testing.T) {
+	if GetAgentStepsText(nil) != "" {
+		t.Errorf("the text of no steps should be empty")
+	}
+
+	steps := []*AgentStep{
+		{ToolName: "tickets__get_ticket", Arguments: `{"id":1}`, Result: strings.Repeat("a", agentStepTextLimit+10)},
+		{ToolName: "deploy__delete_cluster", Arguments: "{}", Error: "The tool call is denied by the policy of the tool"},
+	}
+	text := GetAgentStepsText(steps)
+	lines := strings.Split(text, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got: %q", text)
+	}
+	if !strings.HasPrefix(lines[1], `1. tickets__get_ticket({"id":1}) returned: aaa`) || !strings.HasSuffix(lines[1], "...") {
+		t.Errorf("unexpected line: %q", lines[1])
+	}
+	if lines[2] != "2. deploy__delete_cluster({}) failed: The tool call is denied by the policy of the tool" {
+		t.Errorf("unexpected line: %q", lines[2])
+	}
+}
+
+func TestGetToolResultText(t *testing.T) {
+	contents := []protocol.Content{
+		&protocol.TextContent{Type: "text", Text: "first"},
+		&protocol.TextContent{Type: "text", Text: "second"},
+	}
+	if text := getToolResultText(contents); text != "first\nsecond" {
+		t.Errorf("unexpected text: %q", text)
+	}
+	if text := getToolResultText(nil); text != "" {
+		t.Errorf("unexpected text: %q", text)
+	}
+}
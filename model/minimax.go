@@ -73,8 +73,9 @@ func (p *MiniMaxModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *MiniMaxModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	ctx := context.Background()
+func (p *MiniMaxModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	client, err := minimax.New(
 		minimax.WithApiToken(p.apiKey),
 		minimax.WithGroupId(p.groupID),
@@ -103,7 +104,7 @@ func (p *MiniMaxModelProvider) QueryText(question string, writer io.Writer, hist
 			},
 		},
 		Model:       p.subType,
-		Temperature: p.temperature,
+		Temperature: getSamplingValue(options.Temperature, p.temperature),
 	}
 	res, err := client.ChatCompletions(ctx, req)
 	if err != nil {
@@ -15,23 +15,21 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/gage-technologies/mistral-go"
 )
 
 type MistralModelProvider struct {
-	client    *mistral.MistralClient
+	apiKey    string
 	modelName string
 }
 
 func NewMistralProvider(apiKey, modelName string) (*MistralModelProvider, error) {
-	client := mistral.NewMistralClientDefault(apiKey)
-
 	return &MistralModelProvider{
-		client:    client,
+		apiKey:    apiKey,
 		modelName: modelName,
 	}, nil
 }
@@ -84,30 +82,22 @@ func (c *MistralModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (c *MistralModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	chatRes, err := c.client.Chat(c.modelName, []mistral.ChatMessage{{Content: question, Role: mistral.RoleUser}}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error getting chat completion: %v", err)
-	}
-
-	respText := chatRes.Choices[0].Message.Content
-	respText = strings.TrimSpace(respText)
-
-	_, err = fmt.Fprint(writer, respText)
+func (c *MistralModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	// The Mistral client is not context-aware while the Mistral API is OpenAI-compatible, so the answer is streamed
+	// with the OpenAI client, which stops the generation when the request is canceled
+	localProvider, err := NewLocalModelProvider("Custom", "custom-model", c.apiKey, 0, 0, 0, 0, mistral.Endpoint+"/v1", c.modelName, 0, 0, "USD")
 	if err != nil {
-		return nil, fmt.Errorf("failed to write response: %v", err)
+		return nil, err
 	}
 
-	modelResult, err := getDefaultModelResult(c.modelName, question, respText)
+	modelResult, err := localProvider.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
 	if err != nil {
 		return nil, err
 	}
 
 	err = c.calculatePrice(modelResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate price: %v", err)
+		return nil, err
 	}
-	modelResult.PromptTokenCount += len(question)
-
 	return modelResult, nil
 }
@@ -71,7 +71,9 @@ func (p *MoonshotModelProvider) calculatePrice(modelResult *ModelResult) error {
 	return nil
 }
 
-func (p *MoonshotModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *MoonshotModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	if p.secretKey == "" {
 		return nil, errors.New("missing moonshot_key")
 	}
@@ -115,11 +117,16 @@ func (p *MoonshotModelProvider) QueryText(question string, writer io.Writer, his
 	})
 
 	// Chat completions
-	resp, err := cli.Chat().Completions(context.Background(), &moonshot.ChatCompletionsRequest{
+	request := &moonshot.ChatCompletionsRequest{
 		Model:       moonshot.ChatCompletionsModelID(p.subType),
 		Messages:    messages,
-		Temperature: p.temperature,
-	})
+		Temperature: float64(getSamplingValue(options.Temperature, float32(p.temperature))),
+	}
+	if options.MaxTokens > 0 {
+		request.MaxTokens = int64(options.MaxTokens)
+	}
+
+	resp, err := cli.Chat().Completions(ctx, request)
 	if err != nil {
 		return nil, err
 	}
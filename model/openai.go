@@ -204,24 +204,31 @@ func GetOpenAiClientFromToken(authToken string) openai.Client {
 	return c
 }
 
-func (p *OpenAiModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+// supportsResponseSchema reports whether the model answers with the schema natively, the structured outputs of
+// the Responses API require an object at the root
+func (p *OpenAiModelProvider) supportsResponseSchema(schema *ResponseSchema) bool {
+	return getOpenAiModelType(p.subType) == "Chat" && schema.isObject()
+}
+
+func (p *OpenAiModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	var client openai.Client
 	var flushData interface{}
 
 	client = GetOpenAiClientFromToken(p.secretKey)
 	flushData = flushDataOpenai
 
-	ctx := context.Background()
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
 		return nil, fmt.Errorf("writer does not implement http.Flusher")
 	}
 
 	model := p.subType
-	temperature := p.temperature
-	topP := p.topP
-	frequencyPenalty := p.frequencyPenalty
-	presencePenalty := p.presencePenalty
+	temperature := getSamplingValue(options.Temperature, p.temperature)
+	topP := getSamplingValue(options.TopP, p.topP)
+	frequencyPenalty := getSamplingValue(options.FrequencyPenalty, p.frequencyPenalty)
+	presencePenalty := getSamplingValue(options.PresencePenalty, p.presencePenalty)
 
 	maxTokens := getContextLength(model)
 
@@ -274,6 +281,19 @@ func (p *OpenAiModelProvider) QueryText(question string, writer io.Writer, histo
 			Temperature:  param.NewOpt[float64](float64(temperature)),
 			TopP:         param.NewOpt[float64](float64(topP)),
 		}
+		if options.MaxTokens > 0 {
+			req.MaxOutputTokens = param.NewOpt[int64](int64(options.MaxTokens))
+		}
+		if schema := options.ResponseSchema; schema != nil && schema.isObject() {
+			req.Text = responses.ResponseTextConfigParam{
+				Format: responses.ResponseFormatTextConfigUnionParam{
+					OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
+						Name:   schema.getName(),
+						Schema: schema.Schema,
+					},
+				},
+			}
+		}
 		if agentInfo != nil && agentInfo.AgentClients != nil {
 			tools, err := reverseMcpToolsToOpenAi(agentInfo.AgentClients.Tools)
 			if err != nil {
@@ -316,6 +336,7 @@ func (p *OpenAiModelProvider) QueryText(question string, writer io.Writer, histo
 				modelResult.ResponseTokenCount = int(variant.Response.Usage.OutputTokens)
 				modelResult.PromptTokenCount = int(variant.Response.Usage.InputTokens)
 				modelResult.TotalTokenCount = int(variant.Response.Usage.TotalTokens)
+				modelResult.CachedTokenCount = int(variant.Response.Usage.InputTokensDetails.CachedTokens)
 				break
 			}
 		}
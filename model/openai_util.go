@@ -15,6 +15,10 @@
 package model
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/pkoukk/tiktoken-go"
 	"github.com/sashabaranov/go-openai"
 )
@@ -134,6 +138,90 @@ func OpenaiRawMessagesToMessages(messages []*RawMessage) []openai.ChatCompletion
 	return res
 }
 
+func getOpenaiMessageText(message openai.ChatCompletionMessage) string {
+	if len(message.MultiContent) == 0 {
+		return message.Content
+	}
+
+	texts := []string{}
+	for _, part := range message.MultiContent {
+		if part.Type == openai.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		} else if part.Type == openai.ChatMessagePartTypeImageURL && part.ImageURL != nil {
+			// Images are carried as <img> tags in the text, the same way as the chat page sends them
+			texts = append(texts, fmt.Sprintf("<img src=\"%s\" />", part.ImageURL.URL))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func openaiMessageToRawMessages(message openai.ChatCompletionMessage) []*RawMessage {
+	text := getOpenaiMessageText(message)
+	if message.Role == openai.ChatMessageRoleTool {
+		return []*RawMessage{{Text: text, Author: "Tool", ToolCallID: message.ToolCallID}}
+	} else if message.Role != openai.ChatMessageRoleAssistant {
+		return []*RawMessage{{Text: text, Author: openai.ChatMessageRoleUser}}
+	}
+
+	if len(message.ToolCalls) == 0 {
+		return []*RawMessage{{Text: text, Author: "AI"}}
+	}
+
+	// One raw message per tool call, the same way as QueryTextWithTools() records them
+	res := []*RawMessage{}
+	for i, toolCall := range message.ToolCalls {
+		toolCall.Index = nil
+		rawMessage := &RawMessage{Author: "AI", ToolCall: toolCall}
+		if i == 0 {
+			rawMessage.Text = text
+		}
+		res = append(res, rawMessage)
+	}
+	return res
+}
+
+// OpenaiMessagesToRawMessages splits the messages of an OpenAI chat request into the system prompt, the question (the last
+// user message), the history before the question (newest first, like GetRecentRawMessages() returns) and the tool call
+// turns after the question, which are passed to the providers as agent messages
+func OpenaiMessagesToRawMessages(messages []openai.ChatCompletionMessage) (string, string, []*RawMessage, []*RawMessage, error) {
+	questionIndex := -1
+	for i, message := range messages {
+		if message.Role == openai.ChatMessageRoleUser {
+			questionIndex = i
+		}
+	}
+	if questionIndex == -1 {
+		return "", "", nil, nil, fmt.Errorf("no user message found in the request")
+	}
+
+	prompts := []string{}
+	history := []*RawMessage{}
+	agentMessages := []*RawMessage{}
+	for i, message := range messages {
+		if message.Role == openai.ChatMessageRoleSystem || message.Role == openai.ChatMessageRoleDeveloper {
+			prompts = append(prompts, getOpenaiMessageText(message))
+		} else if i < questionIndex {
+			history = append(history, openaiMessageToRawMessages(message)...)
+		} else if i > questionIndex {
+			agentMessages = append(agentMessages, openaiMessageToRawMessages(message)...)
+		}
+	}
+
+	prompt := strings.Join(prompts, "\n\n")
+	question := getOpenaiMessageText(messages[questionIndex])
+	history = reverseMessages(history)
+	return prompt, question, history, agentMessages, nil
+}
+
+// getOpenAiSamplingValue returns the value of a sampling parameter for the requests of the go-openai client, which
+// omits the parameters of 0, so an explicit 0 is sent as the smallest float32 like the client documents
+func getOpenAiSamplingValue(value *float32, configuredValue float32) float32 {
+	if value != nil && *value == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+	return getSamplingValue(value, configuredValue)
+}
+
 func ChatCompletionRequest(model string, messages []openai.ChatCompletionMessage, temperature float32, topP float32, frequencyPenalty float32, presencePenalty float32) openai.ChatCompletionRequest {
 	res := openai.ChatCompletionRequest{
 		Model:            model,
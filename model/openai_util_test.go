@@ -0,0 +1,81 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestOpenaiMessagesToRawMessages(t *testing.T) {
+	toolCall := openai.ToolCall{ID: "call_1", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}}
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: "You are a weather bot."},
+		{Role: openai.ChatMessageRoleUser, Content: "Hi"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "Hello!"},
+		{Role: openai.ChatMessageRoleUser, Content: "What's the weather in Paris?"},
+		{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{toolCall}},
+		{Role: openai.ChatMessageRoleTool, Content: "Sunny", ToolCallID: "call_1"},
+	}
+
+	prompt, question, history, agentMessages, err := OpenaiMessagesToRawMessages(messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prompt != "You are a weather bot." || question != "What's the weather in Paris?" {
+		t.Fatalf("prompt = %q, question = %q", prompt, question)
+	}
+
+	// The history is newest first
+	if len(history) != 2 || history[0].Author != "AI" || history[0].Text != "Hello!" || history[1].Text != "Hi" {
+		t.Fatalf("unexpected history: %v", history)
+	}
+
+	if len(agentMessages) != 2 || agentMessages[0].ToolCall.ID != "call_1" || agentMessages[1].Author != "Tool" || agentMessages[1].ToolCallID != "call_1" {
+		t.Fatalf("unexpected agent messages: %v", agentMessages)
+	}
+
+	_, _, _, _, err = OpenaiMessagesToRawMessages(messages[:1])
+	if err == nil {
+		t.Fatal("OpenaiMessagesToRawMessages() should fail without a user message")
+	}
+}
+
+func TestGetOpenAiSamplingValue(t *testing.T) {
+	zero, half := float32(0), float32(0.5)
+	options := getQueryOptions(&QueryOptions{Temperature: &zero, TopP: &half})
+
+	// The go-openai client omits 0, so an explicit 0 is sent as the smallest float32
+	if value := getOpenAiSamplingValue(options.Temperature, 0.7); value != math.SmallestNonzeroFloat32 {
+		t.Errorf("temperature = %v, want %v", value, math.SmallestNonzeroFloat32)
+	}
+	if value := getSamplingValue(options.Temperature, 0.7); value != 0 {
+		t.Errorf("temperature = %v, want 0", value)
+	}
+	if value := getOpenAiSamplingValue(options.TopP, 0.9); value != 0.5 {
+		t.Errorf("top_p = %v, want 0.5", value)
+	}
+
+	// The parameters not set by the request keep the configured values
+	if value := getOpenAiSamplingValue(options.FrequencyPenalty, 0.3); value != 0.3 {
+		t.Errorf("frequency_penalty = %v, want 0.3", value)
+	}
+	if value := getOpenAiSamplingValue(getQueryOptions(nil).Temperature, 0.7); value != 0.7 {
+		t.Errorf("temperature = %v, want 0.7", value)
+	}
+}
@@ -126,10 +126,11 @@ func (p *OpenRouterModelProvider) getProxyClientFromToken() *openrouter.Client {
 	return c
 }
 
-func (p *OpenRouterModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (p *OpenRouterModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	client := p.getProxyClientFromToken()
 
-	ctx := context.Background()
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
 		return nil, fmt.Errorf("writer does not implement http.Flusher")
@@ -164,8 +165,18 @@ func (p *OpenRouterModelProvider) QueryText(question string, writer io.Writer, h
 		return nil, fmt.Errorf("The token count: [%d] exceeds the model: [%s]'s maximum token count: [%d]", tokenCount, model, contextLength)
 	}
 
+	if options.MaxTokens > 0 && options.MaxTokens < maxTokens {
+		maxTokens = options.MaxTokens
+	}
+
 	temperature := p.temperature
+	if options.Temperature != nil {
+		temperature = options.Temperature
+	}
 	topP := p.topP
+	if options.TopP != nil {
+		topP = options.TopP
+	}
 
 	respStream, err := client.CreateChatCompletionStream(
 		ctx,
@@ -0,0 +1,84 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// ModelPrice is a price of the pricing catalog, it replaces the built-in price of a provider
+type ModelPrice struct {
+	InputPricePerThousandTokens       float64
+	OutputPricePerThousandTokens      float64
+	CachedInputPricePerThousandTokens float64
+	PricePerImage                     float64
+	Currency                          string
+}
+
+// CalculatePrice sets the price of the result by its token and image counts, the cached prompt tokens
+// are charged at the cached input price if there is one
+func (price *ModelPrice) CalculatePrice(modelResult *ModelResult) {
+	cachedInputPrice := price.CachedInputPricePerThousandTokens
+	if cachedInputPrice == 0 {
+		cachedInputPrice = price.InputPricePerThousandTokens
+	}
+
+	inputPrice := getPrice(modelResult.PromptTokenCount-modelResult.CachedTokenCount, price.InputPricePerThousandTokens)
+	cachedPrice := getPrice(modelResult.CachedTokenCount, cachedInputPrice)
+	outputPrice := getPrice(modelResult.ResponseTokenCount, price.OutputPricePerThousandTokens)
+	imagePrice := float64(modelResult.ImageCount) * price.PricePerImage
+
+	modelResult.TotalPrice = AddPrices(AddPrices(inputPrice, cachedPrice), AddPrices(outputPrice, imagePrice))
+	modelResult.Currency = price.Currency
+}
+
+// PricedModelProvider answers with the wrapped provider and charges the price of the pricing catalog
+type PricedModelProvider struct {
+	ModelProvider
+	price *ModelPrice
+}
+
+func NewPricedModelProvider(p ModelProvider, price *ModelPrice) *PricedModelProvider {
+	return &PricedModelProvider{ModelProvider: p, price: price}
+}
+
+func (p *PricedModelProvider) GetPricing() string {
+	return fmt.Sprintf(`Pricing catalog:
+
+| Input price per 1K tokens | Cached input price per 1K tokens | Output price per 1K tokens | Price per image | Currency |
+|---------------------------|----------------------------------|----------------------------|-----------------|----------|
+| %v | %v | %v | %v | %s |
+
+%s`, p.price.InputPricePerThousandTokens, p.price.CachedInputPricePerThousandTokens, p.price.OutputPricePerThousandTokens, p.price.PricePerImage, p.price.Currency, p.ModelProvider.GetPricing())
+}
+
+func (p *PricedModelProvider) supportsResponseSchema(schema *ResponseSchema) bool {
+	provider, ok := p.ModelProvider.(structuredOutputProvider)
+	return ok && provider.supportsResponseSchema(schema)
+}
+
+func (p *PricedModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	modelResult, err := p.ModelProvider.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if modelResult != nil {
+		p.price.CalculatePrice(modelResult)
+	}
+	return modelResult, nil
+}
@@ -15,6 +15,7 @@
 package model
 
 import (
+	"context"
 	"io"
 )
 
@@ -22,9 +23,12 @@ type ModelResult struct {
 	PromptTokenCount   int
 	ResponseTokenCount int
 	TotalTokenCount    int
+	CachedTokenCount   int
 	ImageCount         int
 	TotalPrice         float64
 	Currency           string
+	Provider           string
+	StructuredOutput   interface{}
 }
 
 func newModelResult(promptTokenCount int, responseTokenCount int, totalTokenCount int) *ModelResult {
@@ -35,16 +39,55 @@ func newModelResult(promptTokenCount int, responseTokenCount int, totalTokenCoun
 	}
 }
 
+// QueryOptions holds the options that a request sets for one answer, QueryText() gets them like the AgentInfo.
+// A nil QueryOptions sets nothing, and a nil sampling parameter keeps the value configured for the provider,
+// so that an explicit 0 can be told apart from an unset parameter
+type QueryOptions struct {
+	// MaxTokens limits the number of tokens generated for the answer, the MiniMax and ChatGLM providers ignore it
+	// as their clients have no such limit
+	MaxTokens int
+
+	// The sampling parameters, the ChatGLM, Cohere and iFlytek providers ignore them
+	Temperature      *float32
+	TopP             *float32
+	TopK             *int
+	FrequencyPenalty *float32
+	PresencePenalty  *float32
+
+	// ThinkingBudget is the number of tokens the provider may think with, only the Claude provider has such a budget
+	ThinkingBudget int
+
+	// ResponseSchema asks for an answer conforming to the JSON schema, the providers that support it natively apply it
+	// to their requests, see QueryStructuredOutput()
+	ResponseSchema *ResponseSchema
+}
+
+// getQueryOptions returns the options of the request, or empty ones if the request sets none
+func getQueryOptions(options *QueryOptions) *QueryOptions {
+	if options == nil {
+		return &QueryOptions{}
+	}
+	return options
+}
+
+// getSamplingValue returns the value of a sampling parameter set by the request, or the configured one if it is not set
+func getSamplingValue(value *float32, configuredValue float32) float32 {
+	if value == nil {
+		return configuredValue
+	}
+	return *value
+}
+
 type ModelProvider interface {
 	GetPricing() string
-	QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error)
+	QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error)
 }
 
 func GetModelProvider(typ string, subType string, clientId string, clientSecret string, userKey string, temperature float32, topP float32, topK int, frequencyPenalty float32, presencePenalty float32, providerUrl string, apiVersion string, compatibleProvider string, inputPricePerThousandTokens float64, outputPricePerThousandTokens float64, Currency string, enableThinking bool) (ModelProvider, error) {
 	var p ModelProvider
 	var err error
 	if typ == "Ollama" {
-		p, err = NewLocalModelProvider("Custom-think", "custom-model", "randomString", temperature, topP, 0, 0, providerUrl, subType, inputPricePerThousandTokens, outputPricePerThousandTokens, Currency)
+		p, err = newOllamaModelProvider(subType, temperature, topP, providerUrl, inputPricePerThousandTokens, outputPricePerThousandTokens, Currency)
 	} else if typ == "Local" {
 		p, err = NewLocalModelProvider(typ, subType, clientSecret, temperature, topP, frequencyPenalty, presencePenalty, providerUrl, compatibleProvider, inputPricePerThousandTokens, outputPricePerThousandTokens, Currency)
 	} else if typ == "OpenAI" {
@@ -0,0 +1,252 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// A target that failed recently is only tried after the healthy ones
+	routerFailureCooldown = 30 * time.Second
+	routerLatencyAlpha    = 0.3
+)
+
+type RouterTarget struct {
+	Name     string
+	Weight   int
+	Provider ModelProvider
+}
+
+// RouterModelProvider dispatches a query to one of its targets according to the policy in subType
+// ("Failover", "Round Robin", "Weighted" or "Latency"), and fails over to the next target on errors
+// such as rate limits, as long as nothing has been written to the client yet
+type RouterModelProvider struct {
+	name    string
+	subType string
+	targets []*RouterTarget
+}
+
+type routerTargetStat struct {
+	latency     time.Duration
+	lastFailure time.Time
+}
+
+var (
+	routerStats          = map[string]*routerTargetStat{}
+	routerCounters       = map[string]int{}
+	routerStatsMutex     sync.Mutex
+	routerRandom         = rand.New(rand.NewSource(time.Now().UnixNano()))
+	routerRandomMutex    sync.Mutex
+	routerSupportedTypes = []string{"Failover", "Round Robin", "Weighted", "Latency"}
+)
+
+func NewRouterModelProvider(name string, subType string, targets []*RouterTarget) (*RouterModelProvider, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("the router model provider: %s should have at least one target provider", name)
+	}
+
+	if subType == "" {
+		subType = "Failover"
+	}
+	isSupported := false
+	for _, typ := range routerSupportedTypes {
+		if typ == subType {
+			isSupported = true
+			break
+		}
+	}
+	if !isSupported {
+		return nil, fmt.Errorf("the routing policy: %s is not supported, expected one of: %s", subType, strings.Join(routerSupportedTypes, ", "))
+	}
+
+	p := &RouterModelProvider{
+		name:    name,
+		subType: subType,
+		targets: targets,
+	}
+	return p, nil
+}
+
+func (p *RouterModelProvider) GetPricing() string {
+	res := []string{}
+	for _, target := range p.targets {
+		res = append(res, fmt.Sprintf("%s:\n%s", target.Name, target.Provider.GetPricing()))
+	}
+	return strings.Join(res, "\n")
+}
+
+func getRouterTargetStat(name string) routerTargetStat {
+	routerStatsMutex.Lock()
+	defer routerStatsMutex.Unlock()
+
+	stat, ok := routerStats[name]
+	if !ok {
+		return routerTargetStat{}
+	}
+	return *stat
+}
+
+func recordRouterTargetResult(name string, latency time.Duration, err error) {
+	routerStatsMutex.Lock()
+	defer routerStatsMutex.Unlock()
+
+	stat, ok := routerStats[name]
+	if !ok {
+		stat = &routerTargetStat{}
+		routerStats[name] = stat
+	}
+
+	if err != nil {
+		stat.lastFailure = time.Now()
+		return
+	}
+
+	stat.lastFailure = time.Time{}
+	if stat.latency == 0 {
+		stat.latency = latency
+	} else {
+		stat.latency = time.Duration(routerLatencyAlpha*float64(latency) + (1-routerLatencyAlpha)*float64(stat.latency))
+	}
+}
+
+func (p *RouterModelProvider) nextCounter() int {
+	routerStatsMutex.Lock()
+	defer routerStatsMutex.Unlock()
+
+	res := routerCounters[p.name]
+	routerCounters[p.name] = res + 1
+	return res
+}
+
+// getWeightedOrder draws the targets one by one without replacement, each with a chance proportional to its weight
+func getWeightedOrder(targets []*RouterTarget) []*RouterTarget {
+	routerRandomMutex.Lock()
+	defer routerRandomMutex.Unlock()
+
+	remaining := append([]*RouterTarget{}, targets...)
+	res := []*RouterTarget{}
+	for len(remaining) > 0 {
+		total := 0
+		for _, target := range remaining {
+			total += max(target.Weight, 1)
+		}
+
+		r := routerRandom.Intn(total)
+		for i, target := range remaining {
+			r -= max(target.Weight, 1)
+			if r < 0 {
+				res = append(res, target)
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// getOrderedTargets returns the targets in the order they should be tried
+func (p *RouterModelProvider) getOrderedTargets() []*RouterTarget {
+	targets := append([]*RouterTarget{}, p.targets...)
+
+	switch p.subType {
+	case "Round Robin":
+		offset := p.nextCounter() % len(targets)
+		targets = append(targets[offset:], targets[:offset]...)
+	case "Weighted":
+		targets = getWeightedOrder(targets)
+	case "Latency":
+		// Targets without a measured latency come first so that they get measured
+		sort.SliceStable(targets, func(i, j int) bool {
+			return getRouterTargetStat(targets[i].Name).latency < getRouterTargetStat(targets[j].Name).latency
+		})
+	}
+
+	healthy := []*RouterTarget{}
+	failed := []*RouterTarget{}
+	for _, target := range targets {
+		stat := getRouterTargetStat(target.Name)
+		if !stat.lastFailure.IsZero() && time.Since(stat.lastFailure) < routerFailureCooldown {
+			failed = append(failed, target)
+		} else {
+			healthy = append(healthy, target)
+		}
+	}
+	return append(healthy, failed...)
+}
+
+// routerWriter remembers whether a target has started answering and when, failing over after that
+// would send a mixed answer to the client
+type routerWriter struct {
+	writer     io.Writer
+	start      time.Time
+	firstWrite time.Duration
+	written    bool
+}
+
+func (w *routerWriter) Write(p []byte) (int, error) {
+	if !w.written && len(p) > 0 {
+		w.written = true
+		w.firstWrite = time.Since(w.start)
+	}
+	return w.writer.Write(p)
+}
+
+func (w *routerWriter) Flush() {
+	if flusher, ok := w.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (p *RouterModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	errors := []string{}
+	for _, target := range p.getOrderedTargets() {
+		w := &routerWriter{writer: writer, start: time.Now()}
+		modelResult, err := target.Provider.QueryText(ctx, question, w, history, prompt, knowledgeMessages, agentInfo, options)
+
+		latency := w.firstWrite
+		if !w.written {
+			latency = time.Since(w.start)
+		}
+		if ctx.Err() == nil {
+			recordRouterTargetResult(target.Name, latency, err)
+		}
+
+		if err == nil {
+			if modelResult == nil {
+				modelResult = &ModelResult{}
+			}
+			modelResult.Provider = target.Name
+			return modelResult, nil
+		}
+
+		if ctx.Err() != nil || w.written {
+			return nil, err
+		}
+
+		fmt.Printf("RouterModelProvider.QueryText() error, router: %s, provider: %s, %s\n", p.name, target.Name, err.Error())
+		errors = append(errors, fmt.Sprintf("%s: %s", target.Name, err.Error()))
+	}
+
+	return nil, fmt.Errorf("all the providers of router: %s failed, %s", p.name, strings.Join(errors, "; "))
+}
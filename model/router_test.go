@@ -0,0 +1,121 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type routerTestProvider struct {
+	answer string
+	err    error
+	calls  int
+}
+
+func (p *routerTestProvider) GetPricing() string {
+	return ""
+}
+
+func (p *routerTestProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	p.calls++
+	if p.answer != "" {
+		_, err := writer.Write([]byte(p.answer))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ModelResult{TotalTokenCount: 1}, nil
+}
+
+func TestRouterModelProviderFailover(t *testing.T) {
+	limited := &routerTestProvider{err: fmt.Errorf("error, status code: 429, message: Rate limit reached")}
+	healthy := &routerTestProvider{answer: "hello"}
+	p, err := NewRouterModelProvider("router_failover", "Failover", []*RouterTarget{
+		{Name: "router_failover_limited", Provider: limited},
+		{Name: "router_failover_healthy", Provider: healthy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	modelResult, err := p.QueryText(context.Background(), "hi", &buf, nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modelResult.Provider != "router_failover_healthy" || buf.String() != "hello" {
+		t.Fatalf("QueryText() answered by %s with %q, want router_failover_healthy with %q", modelResult.Provider, buf.String(), "hello")
+	}
+
+	// The limited target is in cooldown now, so the healthy one is tried first
+	_, err = p.QueryText(context.Background(), "hi", &bytes.Buffer{}, nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limited.calls != 1 || healthy.calls != 2 {
+		t.Fatalf("calls = %d, %d, want 1, 2", limited.calls, healthy.calls)
+	}
+}
+
+func TestRouterModelProviderNoFailoverAfterWrite(t *testing.T) {
+	broken := &routerTestProvider{answer: "partial", err: fmt.Errorf("stream closed")}
+	healthy := &routerTestProvider{answer: "hello"}
+	p, err := NewRouterModelProvider("router_written", "Failover", []*RouterTarget{
+		{Name: "router_written_broken", Provider: broken},
+		{Name: "router_written_healthy", Provider: healthy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.QueryText(context.Background(), "hi", &bytes.Buffer{}, nil, "", nil, nil, nil)
+	if err == nil || healthy.calls != 0 {
+		t.Fatalf("QueryText() should fail without failover once the answer has started, err = %v", err)
+	}
+}
+
+func TestRouterModelProviderRoundRobin(t *testing.T) {
+	targets := []*RouterTarget{}
+	providers := []*routerTestProvider{}
+	for i := 0; i < 3; i++ {
+		provider := &routerTestProvider{answer: "hello"}
+		providers = append(providers, provider)
+		targets = append(targets, &RouterTarget{Name: fmt.Sprintf("router_round_robin_%d", i), Provider: provider})
+	}
+
+	p, err := NewRouterModelProvider("router_round_robin", "Round Robin", targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 6; i++ {
+		_, err = p.QueryText(context.Background(), "hi", &bytes.Buffer{}, nil, "", nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, provider := range providers {
+		if provider.calls != 2 {
+			t.Errorf("calls of target %d = %d, want 2", i, provider.calls)
+		}
+	}
+}
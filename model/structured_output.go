@@ -0,0 +1,392 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const structuredOutputMaxAttempts = 3
+
+// ResponseSchema is the JSON schema that the answer of a structured output query must conform to
+type ResponseSchema struct {
+	Name   string
+	Schema map[string]interface{}
+}
+
+func (schema *ResponseSchema) getName() string {
+	if schema.Name == "" {
+		return "response"
+	}
+	return schema.Name
+}
+
+func (schema *ResponseSchema) isObject() bool {
+	typ, _ := schema.Schema["type"].(string)
+	return typ == "object"
+}
+
+// structuredOutputProvider is implemented by the providers that can constrain their answer to a JSON schema natively
+type structuredOutputProvider interface {
+	supportsResponseSchema(schema *ResponseSchema) bool
+}
+
+// structuredOutputWriter collects the answer text, dropping the reasoning
+type structuredOutputWriter struct {
+	buf []byte
+}
+
+func (w *structuredOutputWriter) Write(p []byte) (int, error) {
+	prefix := []byte("event: message\ndata: ")
+	if bytes.HasPrefix(p, prefix) {
+		w.buf = append(w.buf, bytes.TrimSuffix(bytes.TrimPrefix(p, prefix), []byte("\n\n"))...)
+	} else if !bytes.HasPrefix(p, []byte("event: ")) {
+		w.buf = append(w.buf, p...)
+	}
+	return len(p), nil
+}
+
+func (w *structuredOutputWriter) Flush() {}
+
+func getStructuredOutputPrompt(prompt string, schema *ResponseSchema) (string, error) {
+	schemaBytes, err := json.Marshal(schema.Schema)
+	if err != nil {
+		return "", err
+	}
+
+	res := fmt.Sprintf("Answer with only a JSON value that conforms to the following JSON schema, without any explanation or code fence:\n%s", string(schemaBytes))
+	if prompt != "" {
+		res = prompt + "\n\n" + res
+	}
+	return res, nil
+}
+
+// getStructuredOutputText extracts the JSON text from an answer, which may be wrapped in a code fence or other text
+func getStructuredOutputText(answer string) string {
+	text := strings.TrimSpace(answer)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+		text = strings.TrimSpace(text)
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+
+	end := strings.LastIndexAny(text, "}]")
+	if end < start {
+		return text
+	}
+	return text[start : end+1]
+}
+
+// ParseStructuredOutput parses the JSON of an answer and validates it against the schema
+func ParseStructuredOutput(answer string, schema *ResponseSchema) (string, interface{}, error) {
+	text := getStructuredOutputText(answer)
+
+	var value interface{}
+	err := json.Unmarshal([]byte(text), &value)
+	if err != nil {
+		return "", nil, fmt.Errorf("the answer is not valid JSON: %s", err.Error())
+	}
+
+	err = ValidateJsonSchema(value, schema.Schema)
+	if err != nil {
+		return "", nil, err
+	}
+	return text, value, nil
+}
+
+// QueryStructuredOutput queries the provider for an answer conforming to the ResponseSchema of the options. The providers
+// that support it natively get the schema in their requests, the others are asked for it in the prompt and retried with
+// the validation error until the answer conforms. The JSON of the answer is written to the writer and its parsed value
+// is returned in the StructuredOutput of the result.
+func QueryStructuredOutput(ctx context.Context, p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, options *QueryOptions) (*ModelResult, error) {
+	schema := options.ResponseSchema
+
+	provider, ok := p.(structuredOutputProvider)
+	if !ok || !provider.supportsResponseSchema(schema) {
+		var err error
+		prompt, err = getStructuredOutputPrompt(prompt, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ModelResult{}
+	attemptQuestion := question
+	attemptHistory := history
+	var lastErr error
+	for i := 0; i < structuredOutputMaxAttempts; i++ {
+		w := &structuredOutputWriter{}
+		modelResult, err := p.QueryText(ctx, attemptQuestion, w, attemptHistory, prompt, knowledgeMessages, nil, options)
+		if err != nil {
+			return nil, err
+		}
+
+		res.PromptTokenCount += modelResult.PromptTokenCount
+		res.ResponseTokenCount += modelResult.ResponseTokenCount
+		res.TotalTokenCount += modelResult.TotalTokenCount
+		res.TotalPrice = AddPrices(res.TotalPrice, modelResult.TotalPrice)
+		res.Currency = modelResult.Currency
+		res.Provider = modelResult.Provider
+
+		answer := string(w.buf)
+		text, value, err := ParseStructuredOutput(answer, schema)
+		if err != nil {
+			lastErr = err
+
+			// The history is newest first, so the failed answer goes before the question it answers
+			attemptHistory = append([]*RawMessage{{Text: answer, Author: "AI"}, {Text: attemptQuestion, Author: "User"}}, attemptHistory...)
+			attemptQuestion = fmt.Sprintf("Your previous answer does not conform to the JSON schema: %s. Answer the question again with only the JSON value that conforms to the schema:\n%s", err.Error(), question)
+			continue
+		}
+
+		if _, err = fmt.Fprintf(writer, "event: message\ndata: %s\n\n", text); err != nil {
+			return nil, err
+		}
+		if flusher, ok := writer.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		res.StructuredOutput = value
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("failed to get an answer conforming to the JSON schema after %d attempts: %s", structuredOutputMaxAttempts, lastErr.Error())
+}
+
+func getJsonSchemaTypes(schema map[string]interface{}) []string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return []string{typ}
+	case []interface{}:
+		res := []string{}
+		for _, item := range typ {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+func isJsonSchemaType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func getJsonSchemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	number, ok := schema[key].(float64)
+	if ok {
+		return number, true
+	}
+	integer, ok := schema[key].(int)
+	return float64(integer), ok
+}
+
+func countJsonSchemaMatches(value interface{}, subSchemas []interface{}, path string) int {
+	count := 0
+	for _, subSchema := range subSchemas {
+		subSchemaMap, _ := subSchema.(map[string]interface{})
+		if validateJsonSchema(value, subSchemaMap, path) == nil {
+			count += 1
+		}
+	}
+	return count
+}
+
+// isJsonValueEqual compares two JSON values by type and value, so that the string "1" is not the number 1, the
+// values are normalized through JSON first, as a schema built in Go may hold an int where the answer holds a float64
+func isJsonValueEqual(a interface{}, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJsonValue(a), normalizeJsonValue(b))
+}
+
+func normalizeJsonValue(value interface{}) interface{} {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var res interface{}
+	err = json.Unmarshal(valueBytes, &res)
+	if err != nil {
+		return value
+	}
+	return res
+}
+
+// ValidateJsonSchema validates a decoded JSON value against the commonly used keywords of a JSON schema:
+// type, enum, const, properties, required, additionalProperties, items, anyOf, oneOf and the length and range limits
+func ValidateJsonSchema(value interface{}, schema map[string]interface{}) error {
+	return validateJsonSchema(value, schema, "$")
+}
+
+func validateJsonSchema(value interface{}, schema map[string]interface{}, path string) error {
+	if schema == nil {
+		return nil
+	}
+
+	types := getJsonSchemaTypes(schema)
+	if len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if isJsonSchemaType(value, typ) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s should be of type %s", path, strings.Join(types, " or "))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, item := range enum {
+			if isJsonValueEqual(item, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s should be one of %v", path, enum)
+		}
+	}
+
+	if constValue, ok := schema["const"]; ok && !isJsonValueEqual(constValue, value) {
+		return fmt.Errorf("%s should be %v", path, constValue)
+	}
+
+	if subSchemas, ok := schema["anyOf"].([]interface{}); ok && countJsonSchemaMatches(value, subSchemas, path) == 0 {
+		return fmt.Errorf("%s does not match any schema of anyOf", path)
+	}
+
+	if subSchemas, ok := schema["oneOf"].([]interface{}); ok {
+		count := countJsonSchemaMatches(value, subSchemas, path)
+		if count == 0 {
+			return fmt.Errorf("%s does not match any schema of oneOf", path)
+		} else if count > 1 {
+			return fmt.Errorf("%s matches %d schemas of oneOf, but should match exactly one", path, count)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, item := range required {
+				name, _ := item.(string)
+				if _, ok := v[name]; !ok {
+					return fmt.Errorf("%s.%s is required", path, name)
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := []string{}
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+				err := validateJsonSchema(v[name], propertySchema, path+"."+name)
+				if err != nil {
+					return err
+				}
+			} else if _, ok := properties[name]; !ok {
+				switch additionalProperties := schema["additionalProperties"].(type) {
+				case bool:
+					if !additionalProperties {
+						return fmt.Errorf("%s.%s is not allowed", path, name)
+					}
+				case map[string]interface{}:
+					err := validateJsonSchema(v[name], additionalProperties, path+"."+name)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	case []interface{}:
+		if minItems, ok := getJsonSchemaNumber(schema, "minItems"); ok && float64(len(v)) < minItems {
+			return fmt.Errorf("%s should have at least %v items", path, minItems)
+		}
+		if maxItems, ok := getJsonSchemaNumber(schema, "maxItems"); ok && float64(len(v)) > maxItems {
+			return fmt.Errorf("%s should have at most %v items", path, maxItems)
+		}
+
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				err := validateJsonSchema(item, items, fmt.Sprintf("%s[%d]", path, i))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := getJsonSchemaNumber(schema, "minLength"); ok && length < minLength {
+			return fmt.Errorf("%s should have at least %v characters", path, minLength)
+		}
+		if maxLength, ok := getJsonSchemaNumber(schema, "maxLength"); ok && length > maxLength {
+			return fmt.Errorf("%s should have at most %v characters", path, maxLength)
+		}
+	case float64:
+		if minimum, ok := getJsonSchemaNumber(schema, "minimum"); ok && v < minimum {
+			return fmt.Errorf("%s should be at least %v", path, minimum)
+		}
+		if maximum, ok := getJsonSchemaNumber(schema, "maximum"); ok && v > maximum {
+			return fmt.Errorf("%s should be at most %v", path, maximum)
+		}
+	}
+
+	return nil
+}
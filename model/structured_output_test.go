@@ -0,0 +1,186 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testResponseSchema = &ResponseSchema{
+	Name: "person",
+	Schema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"age":  map[string]interface{}{"type": "integer", "minimum": float64(0)},
+			"role": map[string]interface{}{"type": "string", "enum": []interface{}{"admin", "user"}},
+		},
+		"required":             []interface{}{"name", "age"},
+		"additionalProperties": false,
+	},
+}
+
+func TestParseStructuredOutput(t *testing.T) {
+	scenarios := []struct {
+		answer string
+		valid  bool
+	}{
+		{`{"name": "Alice", "age": 30}`, true},
+		{"```json\n{\"name\": \"Alice\", \"age\": 30, \"role\": \"admin\"}\n```", true},
+		{`Here you are: {"name": "Alice", "age": 30}`, true},
+		{`{"name": "Alice"}`, false},
+		{`{"name": "Alice", "age": 30.5}`, false},
+		{`{"name": "Alice", "age": -1}`, false},
+		{`{"name": "Alice", "age": 30, "role": "guest"}`, false},
+		{`{"name": "Alice", "age": 30, "email": "a@b.c"}`, false},
+		{`not json`, false},
+	}
+
+	for _, scenario := range scenarios {
+		_, value, err := ParseStructuredOutput(scenario.answer, testResponseSchema)
+		if scenario.valid && (err != nil || value == nil) {
+			t.Errorf("ParseStructuredOutput(%q) error: %v", scenario.answer, err)
+		} else if !scenario.valid && err == nil {
+			t.Errorf("ParseStructuredOutput(%q) should fail", scenario.answer)
+		}
+	}
+}
+
+func TestValidateJsonSchemaOneOf(t *testing.T) {
+	schema := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "integer"},
+			map[string]interface{}{"type": "string"},
+		},
+	}
+
+	scenarios := []struct {
+		value interface{}
+		valid bool
+	}{
+		{"text", true},
+		{1.5, true},
+		{float64(2), false},
+		{true, false},
+	}
+
+	for _, scenario := range scenarios {
+		err := ValidateJsonSchema(scenario.value, schema)
+		if scenario.valid && err != nil {
+			t.Errorf("ValidateJsonSchema(%v) error: %v", scenario.value, err)
+		} else if !scenario.valid && err == nil {
+			t.Errorf("ValidateJsonSchema(%v) should fail", scenario.value)
+		}
+	}
+}
+
+// structuredOutputTestProvider answers with the queued answers in turn
+type structuredOutputTestProvider struct {
+	answers   []string
+	questions []string
+	prompts   []string
+	options   []*QueryOptions
+}
+
+func (p *structuredOutputTestProvider) GetPricing() string {
+	return ""
+}
+
+func (p *structuredOutputTestProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	answer := p.answers[len(p.questions)]
+	p.questions = append(p.questions, question)
+	p.prompts = append(p.prompts, prompt)
+	p.options = append(p.options, options)
+
+	_, err := fmt.Fprintf(writer, "event: message\ndata: %s\n\n", answer)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelResult{PromptTokenCount: 10, ResponseTokenCount: 5, TotalTokenCount: 15}, nil
+}
+
+func TestValidateJsonSchemaEnumAndConst(t *testing.T) {
+	scenarios := []struct {
+		schema map[string]interface{}
+		value  interface{}
+		valid  bool
+	}{
+		{map[string]interface{}{"enum": []interface{}{float64(1)}}, float64(1), true},
+		{map[string]interface{}{"enum": []interface{}{1}}, float64(1), true},
+		{map[string]interface{}{"enum": []interface{}{float64(1)}}, "1", false},
+		{map[string]interface{}{"enum": []interface{}{true}}, "true", false},
+		{map[string]interface{}{"enum": []interface{}{nil}}, "<nil>", false},
+		{map[string]interface{}{"enum": []interface{}{[]interface{}{"a"}}}, []interface{}{"a"}, true},
+		{map[string]interface{}{"const": "true"}, true, false},
+		{map[string]interface{}{"const": map[string]interface{}{"a": float64(1)}}, map[string]interface{}{"a": float64(1)}, true},
+		{map[string]interface{}{"const": map[string]interface{}{"a": float64(1)}}, map[string]interface{}{"a": "1"}, false},
+	}
+
+	for _, scenario := range scenarios {
+		err := validateJsonSchema(scenario.value, scenario.schema, "$")
+		if scenario.valid && err != nil {
+			t.Errorf("validateJsonSchema(%#v, %v) error: %v", scenario.value, scenario.schema, err)
+		} else if !scenario.valid && err == nil {
+			t.Errorf("validateJsonSchema(%#v, %v) should fail", scenario.value, scenario.schema)
+		}
+	}
+}
+
+func TestQueryStructuredOutput(t *testing.T) {
+	provider := &structuredOutputTestProvider{answers: []string{`{"name": "Alice"}`, `{"name": "Alice", "age": 30}`}}
+	writer := &structuredOutputWriter{}
+
+	maxTokens := 100
+	modelResult, err := QueryStructuredOutput(context.Background(), provider, "Who is Alice?", writer, nil, "", nil, &QueryOptions{MaxTokens: maxTokens, ResponseSchema: testResponseSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each attempt gets the options of the request, with the schema for the providers that apply it natively
+	for _, options := range provider.options {
+		if options == nil || options.MaxTokens != maxTokens || options.ResponseSchema != testResponseSchema {
+			t.Fatalf("unexpected options: %+v", options)
+		}
+	}
+
+	if len(provider.questions) != 2 || !strings.Contains(provider.questions[1], "$.age is required") {
+		t.Fatalf("unexpected questions: %v", provider.questions)
+	}
+	if !strings.Contains(provider.prompts[0], `"additionalProperties":false`) {
+		t.Fatalf("the schema should be in the prompt: %q", provider.prompts[0])
+	}
+	if modelResult.TotalTokenCount != 30 {
+		t.Fatalf("TotalTokenCount = %d, want 30", modelResult.TotalTokenCount)
+	}
+
+	value, ok := modelResult.StructuredOutput.(map[string]interface{})
+	if !ok || value["name"] != "Alice" || value["age"] != float64(30) {
+		t.Fatalf("unexpected structured output: %v", modelResult.StructuredOutput)
+	}
+	if string(writer.buf) != `{"name": "Alice", "age": 30}` {
+		t.Fatalf("unexpected answer: %s", string(writer.buf))
+	}
+
+	provider = &structuredOutputTestProvider{answers: []string{"no", "no", "no"}}
+	_, err = QueryStructuredOutput(context.Background(), provider, "Who is Alice?", writer, nil, "", nil, &QueryOptions{ResponseSchema: testResponseSchema})
+	if err == nil {
+		t.Fatal("QueryStructuredOutput() should fail when no answer conforms")
+	}
+}
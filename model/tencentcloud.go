@@ -15,6 +15,7 @@
 package model
 
 import (
+	"context"
 	"io"
 	"strings"
 )
@@ -44,7 +45,7 @@ func (c *TencentCloudClient) GetPricing() string {
 	return `Pricing information for Tencent Cloud models is not yet available.`
 }
 
-func (c *TencentCloudClient) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
+func (c *TencentCloudClient) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
 	baseUrl := c.endpoint
 	// Get model name
 	model := ""
@@ -60,7 +61,7 @@ func (c *TencentCloudClient) QueryText(question string, writer io.Writer, histor
 		return nil, err
 	}
 
-	modelResult, err := localProvider.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo)
+	modelResult, err := localProvider.QueryText(ctx, question, writer, history, prompt, knowledgeMessages, agentInfo, options)
 	if err != nil {
 		return nil, err
 	}
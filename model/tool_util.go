@@ -0,0 +1,127 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/util"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ToolCall is a tool call requested by a model in the provider-neutral form, the providers without an
+// OpenAI-compatible API return their tool calls to the agent loop in this form
+type ToolCall struct {
+	Id        string
+	Name      string
+	Arguments string
+}
+
+// agentToolStep is a tool call made in a previous round of the agent loop, together with its result
+type agentToolStep struct {
+	ToolCall ToolCall
+	Result   string
+}
+
+func newToolCall(id string, name string, arguments string) ToolCall {
+	if id == "" {
+		id = "call_" + util.GenerateId()
+	}
+	if arguments == "" {
+		arguments = "{}"
+	}
+	return ToolCall{Id: id, Name: name, Arguments: arguments}
+}
+
+func (toolCall ToolCall) toOpenAi() openai.ToolCall {
+	return openai.ToolCall{
+		ID:       toolCall.Id,
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: toolCall.Name, Arguments: toolCall.Arguments},
+	}
+}
+
+// getArgumentsMap returns the arguments of the tool call as a JSON object
+func (toolCall ToolCall) getArgumentsMap() map[string]interface{} {
+	arguments := map[string]interface{}{}
+	err := json.Unmarshal([]byte(toolCall.Arguments), &arguments)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	return arguments
+}
+
+func getAgentTools(agentInfo *AgentInfo) []*protocol.Tool {
+	if agentInfo == nil || agentInfo.AgentClients == nil {
+		return nil
+	}
+	return agentInfo.AgentClients.Tools
+}
+
+// getToolParameters returns the input schema of the tool as a JSON schema object
+func getToolParameters(tool *protocol.Tool) (map[string]interface{}, error) {
+	schemaBytes, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var parameters map[string]interface{}
+	err = json.Unmarshal(schemaBytes, &parameters)
+	if err != nil {
+		return nil, err
+	}
+	return parameters, nil
+}
+
+// getAgentToolSteps pairs the tool calls in the agent messages with their results, in the order they were made
+func getAgentToolSteps(agentInfo *AgentInfo) []*agentToolStep {
+	res := []*agentToolStep{}
+	if agentInfo == nil || agentInfo.AgentMessages == nil {
+		return res
+	}
+
+	stepMap := map[string]*agentToolStep{}
+	for _, message := range agentInfo.AgentMessages.Messages {
+		if message.ToolCall.ID != "" {
+			step := &agentToolStep{ToolCall: newToolCall(message.ToolCall.ID, message.ToolCall.Function.Name, message.ToolCall.Function.Arguments)}
+			stepMap[step.ToolCall.Id] = step
+			res = append(res, step)
+		} else if message.ToolCallID != "" {
+			if step, ok := stepMap[message.ToolCallID]; ok {
+				step.Result = message.Text
+			}
+		}
+	}
+	return res
+}
+
+// setAgentToolCalls hands the tool calls of the last query over to the agent loop
+func setAgentToolCalls(agentInfo *AgentInfo, toolCalls []ToolCall, writer io.Writer) error {
+	if agentInfo == nil || agentInfo.AgentMessages == nil {
+		return nil
+	}
+
+	for _, toolCall := range toolCalls {
+		err := flushDataThink("\n"+"Call result from "+toolCall.Name+"\n", "reason", writer)
+		if err != nil {
+			return err
+		}
+	}
+
+	agentInfo.AgentMessages.ToolCalls = toolCalls
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestGetAgentToolSteps(t *testing.T) {
+	toolCall := openai.ToolCall{ID: "call_1", Function: openai.FunctionCall{Name: "tickets__get_ticket", Arguments: `{"id": 1}`}}
+	agentInfo := &AgentInfo{AgentMessages: &AgentMessages{Messages: []*RawMessage{
+		{Text: "Call result from tickets__get_ticket", Author: "AI", ToolCall: toolCall},
+		{Text: `{"success":true}`, Author: "Tool", ToolCallID: "call_1"},
+		{Text: "Call result from time__now", Author: "AI", ToolCall: openai.ToolCall{ID: "call_2", Function: openai.FunctionCall{Name: "time__now"}}},
+		{Text: "12:00", Author: "Tool", ToolCallID: "call_2"},
+	}}}
+
+	toolSteps := getAgentToolSteps(agentInfo)
+	if len(toolSteps) != 2 {
+		t.Fatalf("expected 2 tool steps, got: %d", len(toolSteps))
+	}
+	if toolSteps[0].ToolCall.Name != "tickets__get_ticket" || toolSteps[0].Result != `{"success":true}` || toolSteps[0].ToolCall.getArgumentsMap()["id"] != 1.0 {
+		t.Errorf("unexpected tool step: %+v", toolSteps[0])
+	}
+	if toolSteps[1].ToolCall.Arguments != "{}" || toolSteps[1].Result != "12:00" {
+		t.Errorf("unexpected tool step: %+v", toolSteps[1])
+	}
+}
+
+func TestGetToolCallsOfNeutralToolCalls(t *testing.T) {
+	agentMessages := &AgentMessages{ToolCalls: []ToolCall{newToolCall("", "tickets__get_ticket", "")}}
+	toolCalls := GetToolCalls(agentMessages)
+	if len(toolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got: %d", len(toolCalls))
+	}
+	if toolCalls[0].ID == "" || toolCalls[0].Type != openai.ToolTypeFunction || toolCalls[0].Function.Name != "tickets__get_ticket" || toolCalls[0].Function.Arguments != "{}" {
+		t.Errorf("unexpected tool call: %+v", toolCalls[0])
+	}
+}
+
+func TestHandleToolCallsParametersWithoutIndex(t *testing.T) {
+	var toolCalls []openai.ToolCall
+	var toolCallsMap map[int]int
+	for _, name := range []string{"a__one", "b__two"} {
+		toolCalls, toolCallsMap = handleToolCallsParameters(openai.ToolCall{Function: openai.FunctionCall{Name: name, Arguments: "{}"}}, toolCalls, toolCallsMap)
+	}
+	if len(toolCalls) != 2 || toolCalls[0].ID == "" || toolCalls[0].ID == toolCalls[1].ID {
+		t.Errorf("unexpected tool calls: %+v", toolCalls)
+	}
+}
+
+func TestToolSchemaConversions(t *testing.T) {
+	tools := []*protocol.Tool{
+		{
+			Name:        "tickets__get_ticket",
+			Description: "Get a ticket",
+			InputSchema: protocol.InputSchema{
+				Type:       protocol.Object,
+				Properties: map[string]*protocol.Property{"id": {Type: protocol.Integer}},
+				Required:   []string{"id"},
+			},
+		},
+		{Name: "time__now", InputSchema: protocol.InputSchema{Type: protocol.Object}},
+	}
+
+	functionDeclarations, err := GenaiToolsToFunctionDeclarations(tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if functionDeclarations[0].Parameters == nil || functionDeclarations[0].Parameters.Properties["id"] == nil || len(functionDeclarations[0].Parameters.Required) != 1 {
+		t.Errorf("unexpected function declaration: %+v", functionDeclarations[0])
+	}
+	if functionDeclarations[1].Parameters != nil {
+		t.Errorf("the tool without parameters should have no schema, got: %+v", functionDeclarations[1].Parameters)
+	}
+
+	parameters, err := getToolParameters(tools[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputSchema := getClaudeToolInputSchema(parameters)
+	if len(inputSchema.Required) != 1 || inputSchema.Required[0] != "id" || inputSchema.Properties == nil {
+		t.Errorf("unexpected input schema: %+v", inputSchema)
+	}
+}
@@ -153,13 +153,28 @@ func getSystemMessages(prompt string, knowledgeMessages []*RawMessage) []*RawMes
 	}
 
 	res := []*RawMessage{{Text: prompt, Author: "System"}}
-	for i, message := range knowledgeMessages {
+	knowledgeIndex := 0
+	memoryIndex := 0
+	for _, message := range knowledgeMessages {
+		// The memories are facts about the user from the previous chats, not knowledge of the store
+		if message.Author == "Memory" {
+			memoryTag := "Memory about the user"
+			if containsZh(prompt) {
+				memoryTag = "用户记忆"
+			}
+
+			memoryIndex++
+			res = append(res, &RawMessage{Text: fmt.Sprintf("%s %d: %s", memoryTag, memoryIndex, message.Text), Author: "System"})
+			continue
+		}
+
 		knowledgeTag := "Knowledge"
 		if containsZh(prompt) {
 			knowledgeTag = "知识"
 		}
 
-		newMessage := &RawMessage{Text: fmt.Sprintf("%s %d: %s", knowledgeTag, i+1, message.Text), Author: "System"}
+		knowledgeIndex++
+		newMessage := &RawMessage{Text: fmt.Sprintf("%s %d: %s", knowledgeTag, knowledgeIndex, message.Text), Author: "System"}
 		res = append(res, newMessage)
 	}
 
@@ -134,8 +134,9 @@ func (p *VolcengineModelProvider) calculatePrice(modelResult *ModelResult) error
 	return nil
 }
 
-func (p *VolcengineModelProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo) (*ModelResult, error) {
-	ctx := context.Background()
+func (p *VolcengineModelProvider) QueryText(ctx context.Context, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, options *QueryOptions) (*ModelResult, error) {
+	options = getQueryOptions(options)
+
 	flusher, ok := writer.(http.Flusher)
 	if !ok {
 		return nil, fmt.Errorf("writer does not implement http.Flusher")
@@ -151,14 +152,18 @@ func (p *VolcengineModelProvider) QueryText(question string, writer io.Writer, h
 			},
 		},
 	}
+	// The client omits the parameters of 0 like the go-openai one
 	request := model.ChatCompletionRequest{
 		Model:         p.endpointID,
 		Messages:      messages,
-		Temperature:   p.temperature,
-		TopP:          p.topP,
+		Temperature:   getOpenAiSamplingValue(options.Temperature, p.temperature),
+		TopP:          getOpenAiSamplingValue(options.TopP, p.topP),
 		Stream:        true,
 		StreamOptions: &model.StreamOptions{IncludeUsage: true},
 	}
+	if options.MaxTokens > 0 {
+		request.MaxTokens = options.MaxTokens
+	}
 
 	flushData := func(data string) error {
 		if _, err := fmt.Fprintf(writer, "event: message\ndata: %s\n\n", data); err != nil {
@@ -279,4 +279,19 @@ func (a *Adapter) createTable() {
 	if err != nil {
 		panic(err)
 	}
+
+	err = a.engine.Sync2(new(Pricing))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(ExchangeRate))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(Memory))
+	if err != nil {
+		panic(err)
+	}
 }
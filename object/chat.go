@@ -17,6 +17,7 @@ package object
 import (
 	"fmt"
 
+	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/util"
 	"xorm.io/core"
 )
@@ -48,6 +49,12 @@ type Chat struct {
 	IsHidden      bool     `json:"isHidden"`
 	IsDeleted     bool     `json:"isDeleted"`
 	NeedTitle     bool     `json:"needTitle"`
+	Summary       string   `xorm:"mediumtext" json:"summary"`
+	SummaryTime   string   `xorm:"varchar(100)" json:"summaryTime"`
+	ActiveMessage string   `xorm:"varchar(100)" json:"activeMessage"`
+
+	ShareId         string `xorm:"varchar(100) index" json:"shareId"`
+	ShareExpireTime string `xorm:"varchar(100)" json:"shareExpireTime"`
 }
 
 func GetGlobalChats() ([]*Chat, error) {
@@ -99,7 +106,10 @@ func UpdateChat(id string, chat *Chat) (bool, error) {
 		return false, nil
 	}
 
-	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(chat)
+	// The active message is only changed by the messages, the share link by sharing, the summary by
+	// UpdateChatSummary() and the counts by AddChatUsage(), so that a stale chat does not switch the branch back,
+	// revoke the share link, undo a summary or roll the counts back
+	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Omit("active_message", "share_id", "share_expire_time", "summary", "summary_time", "message_count", "token_count", "price", "currency").Update(chat)
 	if err != nil {
 		return false, err
 	}
@@ -108,6 +118,46 @@ func UpdateChat(id string, chat *Chat) (bool, error) {
 	return true, nil
 }
 
+// AddChatUsage adds the messages, tokens and price of an answer to the chat in one UPDATE, so that the answers
+// recorded in the same chat at the same time don't overwrite the counts of each other. The price is in the currency
+// of the chat, which is set to the given one if the chat has none yet
+func AddChatUsage(id string, messageCount int, tokenCount int, price float64, currency string) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	if currency != "" {
+		_, err := adapter.engine.Where("owner = ? and name = ? and currency = ?", owner, name, "").Cols("currency").Update(&Chat{Currency: currency})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	affected, err := adapter.engine.ID(core.PK{owner, name}).Incr("message_count", messageCount).Incr("token_count", tokenCount).Incr("price", price).Cols("updated_time").Update(&Chat{UpdatedTime: util.GetCurrentTime()})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// AddChatCost adds the tokens and the price of a call to the chat with AddChatUsage(), the price is converted into
+// the currency of the chat. It returns false and leaves the price out if there is no exchange rate for the currency
+func AddChatCost(chat *Chat, tokenCount int, price float64, currency string) (bool, error) {
+	usage := &Chat{Currency: chat.Currency}
+	added, err := AddChatPrice(usage, price, currency)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = AddChatUsage(chat.GetId(), 0, tokenCount, usage.Price, usage.Currency)
+	if err != nil {
+		return false, err
+	}
+
+	chat.TokenCount += tokenCount
+	chat.Price = model.AddPrices(chat.Price, usage.Price)
+	chat.Currency = usage.Currency
+	return added, nil
+}
+
 func AddChat(chat *Chat) (bool, error) {
 	//if chat.Type == "AI" && chat.User2 == "" {
 	//	provider, err := GetDefaultModelProvider()
@@ -0,0 +1,401 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/util"
+)
+
+const (
+	ChatExportVersion = 1
+
+	ChatExportFormatMarkdown = "Markdown"
+	ChatExportFormatJson     = "JSON"
+	ChatExportFormatHtml     = "HTML"
+)
+
+var reAttachmentUrl = regexp.MustCompile(`(?:src|href)="(https?://[^"]+)"`)
+
+type ChatExportCitation struct {
+	Vector string  `json:"vector"`
+	File   string  `json:"file"`
+	Text   string  `json:"text"`
+	Score  float32 `json:"score"`
+}
+
+type ChatExportAttachment struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type ChatExportMessage struct {
+	Name        string                `json:"name"`
+	CreatedTime string                `json:"createdTime"`
+	Author      string                `json:"author"`
+	Text        string                `json:"text"`
+	ReasonText  string                `json:"reasonText"`
+	Attachment  *ChatExportAttachment `json:"attachment"`
+	Citations   []*ChatExportCitation `json:"citations"`
+}
+
+type ChatExport struct {
+	Version      int                  `json:"version"`
+	ExportedTime string               `json:"exportedTime"`
+	Name         string               `json:"name"`
+	DisplayName  string               `json:"displayName"`
+	Store        string               `json:"store"`
+	Category     string               `json:"category"`
+	CreatedTime  string               `json:"createdTime"`
+	UpdatedTime  string               `json:"updatedTime"`
+	Messages     []*ChatExportMessage `json:"messages"`
+}
+
+// getChatExportAttachment returns the file attached to the question, the AI answers carry the file name of their
+// questions but have no attachment of their own
+func getChatExportAttachment(message *Message) *ChatExportAttachment {
+	if message.FileName == "" || message.Author == "AI" {
+		return nil
+	}
+
+	attachment := &ChatExportAttachment{Name: message.FileName}
+	matches := reAttachmentUrl.FindStringSubmatch(message.Text)
+	if len(matches) > 1 {
+		attachment.Url = matches[1]
+	}
+	return attachment
+}
+
+func getChatExportCitations(message *Message) ([]*ChatExportCitation, error) {
+	citations := []*ChatExportCitation{}
+	for _, vectorScore := range message.VectorScores {
+		vector, err := GetVector(util.GetId("admin", vectorScore.Vector))
+		if err != nil {
+			return nil, err
+		}
+		if vector == nil {
+			continue
+		}
+
+		citations = append(citations, &ChatExportCitation{
+			Vector: vector.Name,
+			File:   vector.File,
+			Text:   vector.Text,
+			Score:  vectorScore.Score,
+		})
+	}
+	return citations, nil
+}
+
+// GetChatExport returns the chat with the messages of its active branch, the hidden, deleted and failed messages
+// are left out
+func GetChatExport(chat *Chat) (*ChatExport, error) {
+	messages, err := GetChatBranchMessages(chat.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ChatExport{
+		Version:      ChatExportVersion,
+		ExportedTime: util.GetCurrentTime(),
+		Name:         chat.Name,
+		DisplayName:  chat.DisplayName,
+		Store:        chat.Store,
+		Category:     chat.Category,
+		CreatedTime:  chat.CreatedTime,
+		UpdatedTime:  chat.UpdatedTime,
+		Messages:     []*ChatExportMessage{},
+	}
+
+	for _, message := range messages {
+		if message.IsHidden || message.IsDeleted || message.Text == "" {
+			continue
+		}
+
+		citations, err := getChatExportCitations(message)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Messages = append(res.Messages, &ChatExportMessage{
+			Name:        message.Name,
+			CreatedTime: message.CreatedTime,
+			Author:      message.Author,
+			Text:        message.Text,
+			ReasonText:  message.ReasonText,
+			Attachment:  getChatExportAttachment(message),
+			Citations:   citations,
+		})
+	}
+	return res, nil
+}
+
+// ParseChatExport parses the JSON of an exported chat and checks that it can be imported
+func ParseChatExport(data []byte) (*ChatExport, error) {
+	var res ChatExport
+	err := json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Version == 0 || res.Version > ChatExportVersion {
+		return nil, fmt.Errorf("The chat export version: %d is not supported", res.Version)
+	}
+	if len(res.Messages) == 0 {
+		return nil, fmt.Errorf("The chat export has no messages")
+	}
+
+	for i, message := range res.Messages {
+		if message == nil || message.Text == "" {
+			return nil, fmt.Errorf("The message: %d of the chat export has no text", i+1)
+		}
+	}
+	return &res, nil
+}
+
+func getMarkdownQuote(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getCitationSnippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) > 200 {
+		return string(runes[:200]) + "..."
+	}
+	return text
+}
+
+// GetMarkdown renders the exported chat as a Markdown document, the reasoning is quoted before the answer
+func (chatExport *ChatExport) GetMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", chatExport.DisplayName))
+	sb.WriteString(fmt.Sprintf("- Store: %s\n", chatExport.Store))
+	sb.WriteString(fmt.Sprintf("- Created time: %s\n", chatExport.CreatedTime))
+	sb.WriteString(fmt.Sprintf("- Exported time: %s\n", chatExport.ExportedTime))
+
+	for _, message := range chatExport.Messages {
+		sb.WriteString(fmt.Sprintf("\n---\n\n### %s\n\n", message.Author))
+		if message.CreatedTime != "" {
+			sb.WriteString(fmt.Sprintf("*%s*\n\n", message.CreatedTime))
+		}
+
+		if message.ReasonText != "" {
+			sb.WriteString("> **Reasoning**\n>\n")
+			sb.WriteString(getMarkdownQuote(message.ReasonText))
+			sb.WriteString("\n\n")
+		}
+
+		sb.WriteString(strings.TrimSpace(message.Text))
+		sb.WriteString("\n")
+
+		if message.Attachment != nil {
+			if message.Attachment.Url != "" {
+				sb.WriteString(fmt.Sprintf("\n**Attachment**: [%s](%s)\n", message.Attachment.Name, message.Attachment.Url))
+			} else {
+				sb.WriteString(fmt.Sprintf("\n**Attachment**: %s\n", message.Attachment.Name))
+			}
+		}
+
+		if len(message.Citations) != 0 {
+			sb.WriteString("\n**Citations**:\n\n")
+			for i, citation := range message.Citations {
+				sb.WriteString(fmt.Sprintf("%d. %s (%.2f): %s\n", i+1, citation.File, citation.Score, getCitationSnippet(citation.Text)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func isImageAttachment(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".bmp", ".svg":
+		return true
+	default:
+		return false
+	}
+}
+
+const chatExportHtmlStyle = `body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif;max-width:860px;margin:0 auto;padding:24px;color:#1f1f1f;background:#fff}
+h1{font-size:24px}
+.meta{color:#8c8c8c;font-size:13px}
+.message{border-radius:8px;padding:12px 16px;margin:16px 0;background:#f5f5f5}
+.message.user{background:#e6f4ff}
+.author{font-weight:600}
+.time{color:#8c8c8c;font-size:12px;margin-left:8px}
+.text{white-space:pre-wrap;word-wrap:break-word;margin-top:8px}
+details{margin-top:8px;color:#595959}
+.reason{white-space:pre-wrap;border-left:3px solid #d9d9d9;padding-left:12px}
+.citations{margin-top:8px;font-size:13px;color:#595959}
+img{max-width:100%}`
+
+// GetHtml renders the exported chat as a standalone HTML page. The texts are escaped, so that the page does not run
+// anything from the messages
+func (chatExport *ChatExport) GetHtml() string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(chatExport.DisplayName)))
+	sb.WriteString(fmt.Sprintf("<style>\n%s\n</style>\n</head>\n<body>\n", chatExportHtmlStyle))
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(chatExport.DisplayName)))
+	sb.WriteString(fmt.Sprintf("<div class=\"meta\">%s · %s</div>\n", html.EscapeString(chatExport.Store), html.EscapeString(chatExport.CreatedTime)))
+
+	for _, message := range chatExport.Messages {
+		class := "message user"
+		if message.Author == "AI" {
+			class = "message"
+		}
+
+		sb.WriteString(fmt.Sprintf("<div class=\"%s\">\n", class))
+		sb.WriteString(fmt.Sprintf("<span class=\"author\">%s</span><span class=\"time\">%s</span>\n", html.EscapeString(message.Author), html.EscapeString(message.CreatedTime)))
+
+		if message.ReasonText != "" {
+			sb.WriteString(fmt.Sprintf("<details><summary>Reasoning</summary><div class=\"reason\">%s</div></details>\n", html.EscapeString(strings.TrimSpace(message.ReasonText))))
+		}
+
+		if message.Attachment != nil && message.Attachment.Url != "" && isImageAttachment(message.Attachment.Name) {
+			sb.WriteString(fmt.Sprintf("<div class=\"text\"><img src=\"%s\" alt=\"%s\"></div>\n", html.EscapeString(message.Attachment.Url), html.EscapeString(message.Attachment.Name)))
+		} else {
+			sb.WriteString(fmt.Sprintf("<div class=\"text\">%s</div>\n", html.EscapeString(strings.TrimSpace(message.Text))))
+			if message.Attachment != nil {
+				if message.Attachment.Url != "" {
+					sb.WriteString(fmt.Sprintf("<div class=\"citations\">Attachment: <a href=\"%s\">%s</a></div>\n", html.EscapeString(message.Attachment.Url), html.EscapeString(message.Attachment.Name)))
+				} else {
+					sb.WriteString(fmt.Sprintf("<div class=\"citations\">Attachment: %s</div>\n", html.EscapeString(message.Attachment.Name)))
+				}
+			}
+		}
+
+		if len(message.Citations) != 0 {
+			sb.WriteString("<div class=\"citations\">Citations:\n<ol>\n")
+			for _, citation := range message.Citations {
+				sb.WriteString(fmt.Sprintf("<li><b>%s</b> (%.2f): %s</li>\n", html.EscapeString(citation.File), citation.Score, html.EscapeString(getCitationSnippet(citation.Text))))
+			}
+			sb.WriteString("</ol>\n</div>\n")
+		}
+		sb.WriteString("</div>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// getImportedVectorScores returns the vector scores of the citations whose vectors exist in the store and are
+// permitted to the importing user, the vector names of a crafted export can't reach any other vector
+func getImportedVectorScores(storeName string, citations []*ChatExportCitation, user *casdoorsdk.User) ([]VectorScore, error) {
+	res := []VectorScore{}
+	vectors := []*Vector{}
+	scoreMap := map[string]float32{}
+	for _, citation := range citations {
+		vector, err := GetVector(util.GetId("admin", citation.Vector))
+		if err != nil {
+			return nil, err
+		}
+		if vector == nil || vector.Store != storeName {
+			continue
+		}
+
+		vectors = append(vectors, vector)
+		scoreMap[vector.Name] = citation.Score
+	}
+	if len(vectors) == 0 {
+		return res, nil
+	}
+
+	permittedVectors, err := getPermittedVectors(storeName, vectors, user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vector := range permittedVectors {
+		res = append(res, VectorScore{Vector: vector.Name, Score: scoreMap[vector.Name]})
+	}
+	return res, nil
+}
+
+// ImportChatExport adds the chat and the messages of the exported chat, the messages are chained into one branch
+// with new names. The citations whose vectors no longer exist or are not permitted to the user are dropped
+func ImportChatExport(chat *Chat, chatExport *ChatExport, user *casdoorsdk.User) (bool, error) {
+	chat.DisplayName = chatExport.DisplayName
+	if chat.DisplayName == "" {
+		chat.DisplayName = chat.Name
+	}
+	if chatExport.Category != "" {
+		chat.Category = chatExport.Category
+	}
+	chat.NeedTitle = false
+
+	affected, err := AddChat(chat)
+	if err != nil {
+		return false, err
+	}
+	if !affected {
+		return false, nil
+	}
+
+	question := ""
+	createdTime := chat.CreatedTime
+	for _, exportMessage := range chatExport.Messages {
+		author := chat.User
+		replyTo := ""
+		if exportMessage.Author == "AI" {
+			author = "AI"
+			replyTo = question
+		}
+
+		createdTime = util.GetCurrentTimeBasedOnLastMilli(createdTime)
+		message := &Message{
+			Owner:        chat.Owner,
+			Name:         fmt.Sprintf("message_%s", util.GetRandomName()),
+			CreatedTime:  createdTime,
+			Organization: chat.Organization,
+			Store:        chat.Store,
+			User:         chat.User,
+			Chat:         chat.Name,
+			ReplyTo:      replyTo,
+			Author:       author,
+			Text:         exportMessage.Text,
+			ReasonText:   exportMessage.ReasonText,
+		}
+		if exportMessage.Attachment != nil && author != "AI" {
+			message.FileName = exportMessage.Attachment.Name
+		}
+
+		message.VectorScores, err = getImportedVectorScores(chat.Store, exportMessage.Citations, user)
+		if err != nil {
+			return false, err
+		}
+
+		_, err = AddMessage(message)
+		if err != nil {
+			return false, err
+		}
+
+		if author != "AI" {
+			question = message.Name
+		}
+	}
+	return true, nil
+}
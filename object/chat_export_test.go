@@ -0,0 +1,136 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func getTestChatExport() *ChatExport {
+	return &ChatExport{
+		Version:     ChatExportVersion,
+		DisplayName: "Pets <script>",
+		Store:       "store-built-in",
+		CreatedTime: "2025-01-01T00:00:00Z",
+		Messages: []*ChatExportMessage{
+			{
+				Author:     "alice",
+				Text:       "What is a <b>cat</b>?",
+				Attachment: &ChatExportAttachment{Name: "cat.png", Url: "https://example.com/cat.png"},
+			},
+			{
+				Author:     "AI",
+				Text:       "A small pet.",
+				ReasonText: "The user asks about cats.\nCats are pets.",
+				Citations:  []*ChatExportCitation{{Vector: "vector_1", File: "pets.md", Text: "Cats are\nsmall pets.", Score: 0.875}},
+			},
+		},
+	}
+}
+
+func TestChatExportMarkdown(t *testing.T) {
+	markdown := getTestChatExport().GetMarkdown()
+
+	for _, expected := range []string{
+		"# Pets <script>\n",
+		"### alice\n\nWhat is a <b>cat</b>?\n",
+		"**Attachment**: [cat.png](https://example.com/cat.png)\n",
+		"> **Reasoning**\n>\n> The user asks about cats.\n> Cats are pets.\n\nA small pet.\n",
+		"1. pets.md (0.88): Cats are small pets.\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("expected the markdown to contain %q, got:\n%s", expected, markdown)
+		}
+	}
+}
+
+func TestChatExportHtml(t *testing.T) {
+	page := getTestChatExport().GetHtml()
+
+	if strings.Contains(page, "<script>") || strings.Contains(page, "<b>cat</b>") {
+		t.Errorf("expected the texts to be escaped, got:\n%s", page)
+	}
+
+	for _, expected := range []string{
+		"<title>Pets &lt;script&gt;</title>",
+		"<img src=\"https://example.com/cat.png\" alt=\"cat.png\">",
+		"<details><summary>Reasoning</summary><div class=\"reason\">The user asks about cats.\nCats are pets.</div></details>",
+		"<li><b>pets.md</b> (0.88): Cats are small pets.</li>",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("expected the HTML to contain %q, got:\n%s", expected, page)
+		}
+	}
+}
+
+func TestParseChatExport(t *testing.T) {
+	data, err := json.Marshal(getTestChatExport())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chatExport, err := ParseChatExport(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chatExport.Messages) != 2 || chatExport.Messages[1].Citations[0].Vector != "vector_1" {
+		t.Errorf("unexpected chat export: %v", chatExport)
+	}
+
+	for _, data := range []string{
+		`{"messages": [{"author": "alice", "text": "Hi"}]}`,
+		`{"version": 99, "messages": [{"author": "alice", "text": "Hi"}]}`,
+		`{"version": 1, "messages": []}`,
+		`{"version": 1, "messages": [{"author": "alice", "text": ""}]}`,
+		`[]`,
+	} {
+		_, err = ParseChatExport([]byte(data))
+		if err == nil {
+			t.Errorf("expected an error for the chat export: %s", data)
+		}
+	}
+}
+
+func TestChatIsShareExpired(t *testing.T) {
+	for _, test := range []struct {
+		expireTime string
+		expected   bool
+	}{
+		{"", false},
+		{"2000-01-01T00:00:00Z", true},
+		{"2999-01-01T00:00:00Z", false},
+	} {
+		chat := &Chat{ShareExpireTime: test.expireTime}
+		if chat.IsShareExpired() != test.expected {
+			t.Errorf("expected IsShareExpired() of %q to be %v", test.expireTime, test.expected)
+		}
+	}
+}
+
+func TestGetChatExportAttachment(t *testing.T) {
+	question := &Message{Author: "alice", FileName: "cat.png", Text: `<img src="https://example.com/cat.png">`}
+	attachment := getChatExportAttachment(question)
+	if attachment == nil || attachment.Name != "cat.png" || attachment.Url != "https://example.com/cat.png" {
+		t.Errorf("getChatExportAttachment() = %+v, want cat.png", attachment)
+	}
+
+	// The answer inherits the file name of its question but has no attachment
+	answer := &Message{Author: "AI", FileName: "cat.png", Text: "A small pet."}
+	if attachment = getChatExportAttachment(answer); attachment != nil {
+		t.Errorf("getChatExportAttachment() = %+v for an AI answer, want nil", attachment)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/model"
+	"xorm.io/core"
+)
+
+const (
+	MemoryStrategyWindow  = "Window"
+	MemoryStrategySummary = "Summary"
+)
+
+// chatSummaryBatchSize is the number of messages folded into the summary by one query of the memory provider
+const chatSummaryBatchSize = 20
+
+const chatSummaryPrompt = `You maintain the long-term memory of a conversation between a user and an AI assistant. Merge the previous summary and the new messages into one concise summary. Keep the facts, decisions, names, numbers, preferences and open questions that may matter later, and drop greetings and small talk. Write the summary in the language of the conversation and output the summary only.`
+
+// GetChatMemory returns the history for the answer message of the chat, newest first like GetRecentRawMessages().
+// With the "Summary" memory strategy of the store, the rolling summary stored on the chat by UpdateChatSummary() is
+// added as the oldest history message
+func GetChatMemory(store *Store, chat *Chat, message *Message) ([]*model.RawMessage, error) {
+	branch, err := GetMessageBranch(message)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := getRecentRawMessages(branch, store.MemoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if store.MemoryStrategy == MemoryStrategySummary && chat.Summary != "" {
+		summaryMessage := &model.RawMessage{
+			Text:   fmt.Sprintf("Summary of the earlier conversation:\n%s", chat.Summary),
+			Author: "System",
+		}
+		history = append(history, summaryMessage)
+	}
+	return history, nil
+}
+
+// getUnsummarizedMessages returns the messages of the answered branch that the memory window of the next question
+// leaves out and that are not in the last summary, oldest first
+func getUnsummarizedMessages(chat *Chat, branch []*Message, memoryLimit int) []*Message {
+	// The next question sees the last question and answer of the branch and the memoryLimit pairs before them in its
+	// window, the same as getRecentRawMessages() skipping the next question and answer
+	offset := 2 * memoryLimit
+	if len(branch) <= offset {
+		return []*Message{}
+	}
+
+	res := []*Message{}
+	for _, message := range branch[:len(branch)-offset] {
+		if chat.SummaryTime == "" || message.CreatedTime > chat.SummaryTime {
+			res = append(res, message)
+		}
+	}
+	return res
+}
+
+func getChatSummaryQuestion(summary string, messages []*Message) string {
+	var sb strings.Builder
+	if summary != "" {
+		sb.WriteString(fmt.Sprintf("Previous summary:\n%s\n\n", summary))
+	}
+
+	sb.WriteString("New messages:\n")
+	for _, message := range messages {
+		if message.Text == "" {
+			continue
+		}
+
+		role := "User"
+		if message.Author == "AI" {
+			role = "Assistant"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", role, message.Text))
+	}
+	return sb.String()
+}
+
+// UpdateChatSummary folds the messages that the memory window of the next question of the chat leaves out into the
+// rolling summary of the chat, it runs after the answer message is saved, so that the answer does not wait for it
+func UpdateChatSummary(store *Store, message *Message) error {
+	if store.MemoryStrategy != MemoryStrategySummary {
+		return nil
+	}
+
+	chat, err := getChat("admin", message.Chat)
+	if err != nil {
+		return err
+	}
+	if chat == nil {
+		return nil
+	}
+
+	branch, err := GetMessageBranch(message)
+	if err != nil {
+		return err
+	}
+
+	messages := getUnsummarizedMessages(chat, branch, store.MemoryLimit)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	memoryProvider := store.MemoryProvider
+	if memoryProvider == "" {
+		memoryProvider = store.ModelProvider
+	}
+
+	for i := 0; i < len(messages); i += chatSummaryBatchSize {
+		batch := messages[i:min(i+chatSummaryBatchSize, len(messages))]
+		// The stored messages are raw, so the PII masked in the questions is masked before reaching the summarizer
+		question := maskInputPii(store.Guardrails, getChatSummaryQuestion(chat.Summary, batch))
+
+		summary, modelResult, err := GetAnswerWithContext(memoryProvider, question, []*model.RawMessage{}, []*model.RawMessage{}, chatSummaryPrompt)
+		if err != nil {
+			return fmt.Errorf("failed to summarize the chat: %s, %s", chat.Name, err.Error())
+		}
+
+		// Only the summary columns are written, so that the chat saved by the next answer meanwhile is kept
+		chat.Summary = strings.TrimSpace(summary)
+		chat.SummaryTime = batch[len(batch)-1].CreatedTime
+		_, err = adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("summary", "summary_time").Update(chat)
+		if err != nil {
+			return err
+		}
+
+		if modelResult != nil {
+			_, err = AddChatCost(chat, modelResult.TotalTokenCount, modelResult.TotalPrice, modelResult.Currency)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"sync"
+	"testing"
+)
+
+// initTestAdapter points the object package to an empty SQLite database
+func initTestAdapter(t *testing.T) {
+	t.Setenv("driverName", "sqlite")
+	t.Setenv("dataSourceName", "file:"+t.TempDir()+"/casibase.db?cache=shared")
+	t.Setenv("dbName", "")
+	t.Setenv("providerDbName", "")
+	InitFlag()
+	InitAdapter()
+	CreateTables()
+}
+
+func TestGetChatSummaryQuestion(t *testing.T) {
+	messages := []*Message{
+		{Author: "alice", Text: "My order number is 42."},
+		{Author: "AI", Text: "Thanks, I found order 42."},
+		{Author: "AI", Text: ""},
+	}
+
+	question := getChatSummaryQuestion("The user asked about a refund.", messages)
+	expected := "Previous summary:\nThe user asked about a refund.\n\nNew messages:\nUser: My order number is 42.\nAssistant: Thanks, I found order 42.\n"
+	if question != expected {
+		t.Errorf("getChatSummaryQuestion() = %q, want %q", question, expected)
+	}
+
+	question = getChatSummaryQuestion("", messages[:1])
+	expected = "New messages:\nUser: My order number is 42.\n"
+	if question != expected {
+		t.Errorf("getChatSummaryQuestion() without summary = %q, want %q", question, expected)
+	}
+}
+
+func TestAddChatCostConcurrently(t *testing.T) {
+	initTestAdapter(t)
+
+	chat := &Chat{Owner: "admin", Name: "chat_summary_test", Users: []string{}, Currency: "USD"}
+	_, err := AddChat(chat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The summary and the memory extraction add their costs to the same chat at the same time
+	var wg sync.WaitGroup
+	for _, tokenCount := range []int{100, 200} {
+		wg.Add(1)
+		go func(tokenCount int) {
+			defer wg.Done()
+			staleChat := &Chat{Owner: chat.Owner, Name: chat.Name, Currency: chat.Currency}
+			_, err := AddChatCost(staleChat, tokenCount, float64(tokenCount)/100, "USD")
+			if err != nil {
+				t.Error(err)
+			}
+		}(tokenCount)
+	}
+	wg.Wait()
+
+	chat, err = GetChat(chat.GetId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.TokenCount != 300 || chat.Price != 3 {
+		t.Fatalf("AddChatCost() chat = %d tokens, %f %s, want 300 tokens, 3 USD", chat.TokenCount, chat.Price, chat.Currency)
+	}
+}
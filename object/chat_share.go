@@ -0,0 +1,109 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// IsShareExpired returns whether the share link of the chat has expired, a link without an expire time never expires
+func (chat *Chat) IsShareExpired() bool {
+	if chat.ShareExpireTime == "" {
+		return false
+	}
+	return util.GetUnixTimeFromString(chat.ShareExpireTime) <= util.GetCurrentUnixTime()
+}
+
+// ShareChat creates the share link of the chat, or keeps the existing one, with the given expire time. An empty
+// expire time makes the link never expire
+func ShareChat(chat *Chat, expireTime string) (bool, error) {
+	if expireTime != "" && util.GetUnixTimeFromString(expireTime) == 0 {
+		return false, fmt.Errorf("The expire time: %s is invalid", expireTime)
+	}
+
+	if chat.ShareId == "" {
+		chat.ShareId = util.GenerateId()
+	}
+	chat.ShareExpireTime = expireTime
+
+	affected, err := adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("share_id", "share_expire_time").Update(chat)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// UnshareChat revokes the share link of the chat
+func UnshareChat(chat *Chat) (bool, error) {
+	chat.ShareId = ""
+	chat.ShareExpireTime = ""
+
+	affected, err := adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("share_id", "share_expire_time").Update(chat)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func getChatByShareId(shareId string) (*Chat, error) {
+	if shareId == "" {
+		return nil, nil
+	}
+
+	chat := Chat{ShareId: shareId}
+	existed, err := adapter.engine.Get(&chat)
+	if err != nil {
+		return nil, err
+	}
+
+	if existed {
+		return &chat, nil
+	} else {
+		return nil, nil
+	}
+}
+
+// GetSharedChatExport returns the read-only conversation of the share link. The user names are replaced, so that
+// the link does not reveal who had the conversation
+func GetSharedChatExport(shareId string) (*ChatExport, error) {
+	chat, err := getChatByShareId(shareId)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil || chat.IsDeleted {
+		return nil, fmt.Errorf("The shared chat: %s is not found", shareId)
+	}
+	if chat.IsShareExpired() {
+		return nil, fmt.Errorf("The share link of the chat has expired")
+	}
+
+	res, err := GetChatExport(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Name = ""
+	for _, message := range res.Messages {
+		if message.Author != "AI" {
+			message.Author = "User"
+		}
+	}
+	return res, nil
+}
@@ -20,10 +20,12 @@ package object
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/util"
+	"xorm.io/core"
 )
 
 func TestUpdateChatCounts(t *testing.T) {
@@ -52,8 +54,9 @@ func TestUpdateChatCounts(t *testing.T) {
 		}
 	}
 
+	// UpdateChat() leaves the counts alone, so the recounted ones are written directly
 	for _, chat := range chats {
-		_, err = UpdateChat(chat.GetId(), chat)
+		_, err = adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("message_count").Update(chat)
 		if err != nil {
 			panic(err)
 		}
@@ -90,7 +93,7 @@ func TestUpdateChatPrices(t *testing.T) {
 	}
 
 	for _, chat := range chats {
-		_, err = UpdateChat(chat.GetId(), chat)
+		_, err = adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("token_count", "price", "currency").Update(chat)
 		if err != nil {
 			panic(err)
 		}
@@ -184,3 +187,45 @@ func TestPrintChatUsers(t *testing.T) {
 	}
 	fmt.Println(concatenatedUsers)
 }
+
+func TestAddChatUsage(t *testing.T) {
+	initTestAdapter(t)
+
+	staleChat := &Chat{Owner: "admin", Name: "chat_api_test", Users: []string{}}
+	_, err := AddChat(staleChat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The usages added at the same time are all counted
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := AddChatUsage("admin/chat_api_test", 2, 100, 0.5, "USD")
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A chat read before the usages doesn't roll the counts back
+	staleChat.DisplayName = "API chat"
+	_, err = UpdateChat(staleChat.GetId(), staleChat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat, err := GetChat("admin/chat_api_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.DisplayName != "API chat" {
+		t.Fatalf("UpdateChat() display name = %s, want API chat", chat.DisplayName)
+	}
+	if chat.MessageCount != 20 || chat.TokenCount != 1000 || chat.Price != 5 || chat.Currency != "USD" {
+		t.Fatalf("AddChatUsage() chat = %d messages, %d tokens, %f %s, want 20 messages, 1000 tokens, 5 USD", chat.MessageCount, chat.TokenCount, chat.Price, chat.Currency)
+	}
+}
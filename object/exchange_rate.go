@@ -0,0 +1,297 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/casibase/casibase/conf"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// ExchangeRate converts a price in the currency to the target currency: target price = price * rate
+type ExchangeRate struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Currency       string  `xorm:"varchar(100)" json:"currency"`
+	TargetCurrency string  `xorm:"varchar(100)" json:"targetCurrency"`
+	Rate           float64 `json:"rate"`
+}
+
+func GetGlobalExchangeRates() ([]*ExchangeRate, error) {
+	exchangeRates := []*ExchangeRate{}
+	err := adapter.engine.Asc("owner").Desc("created_time").Find(&exchangeRates)
+	if err != nil {
+		return exchangeRates, err
+	}
+
+	return exchangeRates, nil
+}
+
+func GetExchangeRates(owner string) ([]*ExchangeRate, error) {
+	exchangeRates := []*ExchangeRate{}
+	err := adapter.engine.Desc("created_time").Find(&exchangeRates, &ExchangeRate{Owner: owner})
+	if err != nil {
+		return exchangeRates, err
+	}
+
+	return exchangeRates, nil
+}
+
+func getExchangeRate(owner string, name string) (*ExchangeRate, error) {
+	exchangeRate := ExchangeRate{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&exchangeRate)
+	if err != nil {
+		return &exchangeRate, err
+	}
+
+	if existed {
+		return &exchangeRate, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetExchangeRate(id string) (*ExchangeRate, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	return getExchangeRate(owner, name)
+}
+
+func UpdateExchangeRate(id string, exchangeRate *ExchangeRate) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	e, err := getExchangeRate(owner, name)
+	if err != nil {
+		return false, err
+	}
+	if e == nil {
+		return false, nil
+	}
+
+	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(exchangeRate)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func AddExchangeRate(exchangeRate *ExchangeRate) (bool, error) {
+	affected, err := adapter.engine.Insert(exchangeRate)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteExchangeRate(exchangeRate *ExchangeRate) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{exchangeRate.Owner, exchangeRate.Name}).Delete(&ExchangeRate{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (exchangeRate *ExchangeRate) GetId() string {
+	return fmt.Sprintf("%s/%s", exchangeRate.Owner, exchangeRate.Name)
+}
+
+func GetExchangeRateCount(owner string, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&ExchangeRate{})
+}
+
+func GetPaginationExchangeRates(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*ExchangeRate, error) {
+	exchangeRates := []*ExchangeRate{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&exchangeRates)
+	if err != nil {
+		return exchangeRates, err
+	}
+
+	return exchangeRates, nil
+}
+
+// CurrencyConverter converts prices with the exchange rates, a rate also converts backwards with its inverse,
+// and two rates sharing a currency convert through that currency
+type CurrencyConverter struct {
+	rates map[string]map[string]float64
+}
+
+func newCurrencyConverter(exchangeRates []*ExchangeRate) *CurrencyConverter {
+	converter := &CurrencyConverter{rates: map[string]map[string]float64{}}
+	addRate := func(currency string, targetCurrency string, rate float64) {
+		if converter.rates[currency] == nil {
+			converter.rates[currency] = map[string]float64{}
+		}
+		if _, ok := converter.rates[currency][targetCurrency]; !ok {
+			converter.rates[currency][targetCurrency] = rate
+		}
+	}
+
+	// The rates set explicitly win over the inverted ones
+	for _, exchangeRate := range exchangeRates {
+		if exchangeRate.Rate > 0 {
+			addRate(exchangeRate.Currency, exchangeRate.TargetCurrency, exchangeRate.Rate)
+		}
+	}
+	for _, exchangeRate := range exchangeRates {
+		if exchangeRate.Rate > 0 {
+			addRate(exchangeRate.TargetCurrency, exchangeRate.Currency, 1/exchangeRate.Rate)
+		}
+	}
+	return converter
+}
+
+func NewCurrencyConverter() (*CurrencyConverter, error) {
+	exchangeRates, err := GetExchangeRates("admin")
+	if err != nil {
+		return nil, err
+	}
+
+	return newCurrencyConverter(exchangeRates), nil
+}
+
+func (converter *CurrencyConverter) getRate(currency string, targetCurrency string) (float64, bool) {
+	if currency == targetCurrency {
+		return 1, true
+	}
+
+	if rate, ok := converter.rates[currency][targetCurrency]; ok {
+		return rate, true
+	}
+
+	for middleCurrency, rate := range converter.rates[currency] {
+		if middleRate, ok := converter.rates[middleCurrency][targetCurrency]; ok {
+			return rate * middleRate, true
+		}
+	}
+	return 0, false
+}
+
+// Convert converts the price to the target currency, it returns false if there is no exchange rate for it
+func (converter *CurrencyConverter) Convert(price float64, currency string, targetCurrency string) (float64, bool) {
+	if price == 0 || currency == "" || targetCurrency == "" {
+		return price, true
+	}
+
+	rate, ok := converter.getRate(currency, targetCurrency)
+	if !ok {
+		return price, false
+	}
+	return math.Round(price*rate*1e8) / 1e8, true
+}
+
+// GetDisplayPrice converts the price to the display currency configured by "displayCurrency",
+// the price is kept in its currency when there is no display currency or no exchange rate for it
+func (converter *CurrencyConverter) GetDisplayPrice(price float64, currency string) (float64, string) {
+	displayCurrency := conf.GetConfigString("displayCurrency")
+	if displayCurrency == "" {
+		return price, currency
+	}
+
+	displayPrice, ok := converter.Convert(price, currency, displayCurrency)
+	if !ok {
+		return price, currency
+	}
+	return displayPrice, displayCurrency
+}
+
+// addDisplayPrice adds the price to the totals per currency. The price is converted to the display currency when
+// there is an exchange rate for it, otherwise it's totaled in its own currency rather than summed with the others
+func (converter *CurrencyConverter) addDisplayPrice(prices map[string]float64, price float64, currency string) {
+	if price == 0 {
+		return
+	}
+
+	price, currency = converter.GetDisplayPrice(price, currency)
+	prices[currency] = model.AddPrices(prices[currency], price)
+}
+
+// getMainPrice returns the total of the main currency of the totals per currency, which is the display currency if
+// it has a total, otherwise the currency with the largest total. The totals of the other currencies are left out
+func getMainPrice(prices map[string]float64) (float64, string) {
+	displayCurrency := conf.GetConfigString("displayCurrency")
+	if price, ok := prices[displayCurrency]; ok && displayCurrency != "" {
+		return price, displayCurrency
+	}
+
+	currencies := []string{}
+	for currency := range prices {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	res := 0.0
+	resCurrency := ""
+	for _, currency := range currencies {
+		if resCurrency == "" || prices[currency] > res {
+			res = prices[currency]
+			resCurrency = currency
+		}
+	}
+	return res, resCurrency
+}
+
+func refinePrices(prices map[string]float64) map[string]float64 {
+	res := map[string]float64{}
+	for currency, price := range prices {
+		res[currency] = model.RefinePrice(price)
+	}
+	return res
+}
+
+func ConvertPrice(price float64, currency string, targetCurrency string) (float64, error) {
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return 0, err
+	}
+
+	res, ok := converter.Convert(price, currency, targetCurrency)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate from %s to %s", currency, targetCurrency)
+	}
+	return res, nil
+}
+
+// AddChatPrice adds the price to the chat in the currency of the chat, it returns false and leaves the chat
+// unchanged if there is no exchange rate for the currency
+func AddChatPrice(chat *Chat, price float64, currency string) (bool, error) {
+	chatCurrency := chat.Currency
+	if chatCurrency == "" {
+		chatCurrency = currency
+	}
+
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return false, err
+	}
+
+	chatPrice, ok := converter.Convert(price, currency, chatCurrency)
+	if !ok {
+		return false, nil
+	}
+
+	chat.Currency = chatCurrency
+	chat.Price = model.AddPrices(chat.Price, chatPrice)
+	return true, nil
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"path/filepath"
 	"strings"
 )
 
@@ -45,6 +46,11 @@ func AddFile(storeId string, userName string, key string, isLeaf bool, filename
 	if isLeaf {
 		objectKey = fmt.Sprintf("%s/%s", key, filename)
 		objectKey = strings.TrimLeft(objectKey, "/")
+		err = checkFileKey(objectKey)
+		if err != nil {
+			return false, nil, err
+		}
+
 		fileBuffer = bytes.NewBuffer(nil)
 		_, err = io.Copy(fileBuffer, file)
 		if err != nil {
@@ -61,6 +67,11 @@ func AddFile(storeId string, userName string, key string, isLeaf bool, filename
 	} else {
 		objectKey = fmt.Sprintf("%s/%s/_hidden.ini", key, filename)
 		objectKey = strings.TrimLeft(objectKey, "/")
+		err = checkFileKey(objectKey)
+		if err != nil {
+			return false, nil, err
+		}
+
 		fileBuffer = bytes.NewBuffer(nil)
 		bs := fileBuffer.Bytes()
 		_, err = storageProviderObj.PutObject(userName, store.Name, objectKey, fileBuffer)
@@ -73,6 +84,12 @@ func AddFile(storeId string, userName string, key string, isLeaf bool, filename
 }
 
 func DeleteFile(storeId string, key string, isLeaf bool) (bool, error) {
+	key = strings.TrimLeft(key, "/")
+	err := checkFileKey(key)
+	if err != nil {
+		return false, err
+	}
+
 	store, err := GetStore(storeId)
 	if err != nil {
 		return false, err
@@ -106,3 +123,91 @@ func DeleteFile(storeId string, key string, isLeaf bool) (bool, error) {
 	}
 	return true, nil
 }
+
+// checkFileKey rejects the keys that could escape the store, like absolute paths or the ones with ".." segments
+func checkFileKey(key string) error {
+	if strings.HasPrefix(key, "/") || strings.HasPrefix(key, "\\") || filepath.IsAbs(key) || filepath.VolumeName(key) != "" {
+		return fmt.Errorf("the file key: %s should not be an absolute path", key)
+	}
+
+	for _, segment := range strings.FieldsFunc(key, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return fmt.Errorf("the file key: %s should not contain \"..\"", key)
+		}
+	}
+	return nil
+}
+
+func MoveFile(storeId string, key string, newKey string, isLeaf bool) (bool, error) {
+	for _, k := range []string{key, newKey} {
+		err := checkFileKey(k)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	store, err := GetStore(storeId)
+	if err != nil {
+		return false, err
+	}
+	if store == nil {
+		return false, nil
+	}
+
+	key = strings.Trim(key, "/")
+	newKey = strings.Trim(newKey, "/")
+	if key == "" || newKey == "" {
+		return false, fmt.Errorf("the source and destination of the file should not be empty")
+	}
+	if key == newKey {
+		return true, nil
+	}
+	if !isLeaf && strings.HasPrefix(newKey+"/", key+"/") {
+		return false, fmt.Errorf("the folder: %s can't be moved into itself: %s", key, newKey)
+	}
+
+	storageProviderObj, err := store.GetStorageProviderObj()
+	if err != nil {
+		return false, err
+	}
+
+	if isLeaf {
+		err = storageProviderObj.MoveObject(key, newKey)
+		if err != nil {
+			return false, err
+		}
+
+		_, err = updateVectorsFile(store.Name, key, newKey)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		objects, err := storageProviderObj.ListObjects(key)
+		if err != nil {
+			return false, err
+		}
+
+		for _, object := range objects {
+			if !strings.HasPrefix(object.Key, key+"/") {
+				continue
+			}
+
+			objectKey := newKey + strings.TrimPrefix(object.Key, key)
+			err = storageProviderObj.MoveObject(object.Key, objectKey)
+			if err != nil {
+				return false, err
+			}
+
+			_, err = updateVectorsFile(store.Name, object.Key, objectKey)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	err = updatePermissionsResource(store.Name, key, newKey)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import "testing"
+
+func TestCheckFileKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		isValid bool
+	}{
+		{"docs/a.md", true},
+		{"docs/..a.md", true},
+		{"/etc/passwd", false},
+		{"\\windows\\system32", false},
+		{"../a.md", false},
+		{"docs/../../a.md", false},
+		{"docs\\..\\a.md", false},
+	}
+
+	for _, test := range tests {
+		err := checkFileKey(test.key)
+		if (err == nil) != test.isValid {
+			t.Errorf("checkFileKey(%q) error = %v, want valid = %v", test.key, err, test.isValid)
+		}
+	}
+}
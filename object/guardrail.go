@@ -0,0 +1,296 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+)
+
+// Guardrail is a check of a store on the questions before they reach the providers ("Input" stage) and on the
+// answers ("Output" stage), the guardrails of a store run in order as a chain
+type Guardrail struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Stage     string   `json:"stage"`
+	Action    string   `json:"action"`
+	PiiTypes  []string `json:"piiTypes"`
+	Keywords  []string `json:"keywords"`
+	Patterns  []string `json:"patterns"`
+	Provider  string   `json:"provider"`
+	IsEnabled bool     `json:"isEnabled"`
+}
+
+// GuardrailEvent is the audit record of a guardrail that masked or blocked a message, it never contains the
+// masked text itself
+type GuardrailEvent struct {
+	Guardrail string `json:"guardrail"`
+	Type      string `json:"type"`
+	Stage     string `json:"stage"`
+	Action    string `json:"action"`
+	Detail    string `json:"detail"`
+	Time      string `json:"time"`
+}
+
+const (
+	GuardrailTypePii        = "PII"
+	GuardrailTypeBlocklist  = "Blocklist"
+	GuardrailTypeModeration = "Moderation"
+
+	GuardrailStageInput  = "Input"
+	GuardrailStageOutput = "Output"
+	GuardrailStageBoth   = "Both"
+
+	GuardrailActionMask  = "Mask"
+	GuardrailActionBlock = "Block"
+)
+
+type piiPattern struct {
+	Type  string
+	Mask  string
+	Regex *regexp.Regexp
+}
+
+// piiPatterns are matched in order, so the ID numbers and API keys are masked before their digits look like phones
+var piiPatterns = []piiPattern{
+	{"API Key", "[API_KEY]", regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{35}|gh[pousr]_[A-Za-z0-9]{36}|xox[abprs]-[A-Za-z0-9-]{10,})`)},
+	{"Email", "[EMAIL]", regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{"ID Number", "[ID_NUMBER]", regexp.MustCompile(`\b(?:\d{17}[\dXx]|\d{3}-\d{2}-\d{4})\b`)},
+	{"Phone", "[PHONE]", regexp.MustCompile(`(?:\+\d{1,3}[\s-]?)?\b(?:1[3-9]\d{9}|\(?\d{3}\)?[\s.-]\d{3}[\s.-]\d{4})\b`)},
+}
+
+const guardrailModerationPrompt = `You are a content moderator. Decide whether the following text is harmful, such as violence, self-harm, sexual content involving minors, hate, harassment or instructions for illegal activities. Answer "SAFE" if it is acceptable, otherwise answer "UNSAFE: " followed by a short reason.`
+
+func (guardrail *Guardrail) appliesTo(stage string) bool {
+	if !guardrail.IsEnabled {
+		return false
+	}
+	return guardrail.Stage == stage || guardrail.Stage == GuardrailStageBoth || guardrail.Stage == ""
+}
+
+func newGuardrailEvent(guardrail *Guardrail, stage string, action string, detail string) GuardrailEvent {
+	return GuardrailEvent{
+		Guardrail: guardrail.Name,
+		Type:      guardrail.Type,
+		Stage:     stage,
+		Action:    action,
+		Detail:    detail,
+		Time:      util.GetCurrentTime(),
+	}
+}
+
+// maskPii masks the PII of the types in the text, all the types are masked if types is empty,
+// it returns the masked text and the count of each masked type in the order of piiPatterns
+func maskPii(text string, types []string) (string, []string) {
+	details := []string{}
+	for _, pattern := range piiPatterns {
+		if len(types) != 0 && !util.InSlice(types, pattern.Type) {
+			continue
+		}
+
+		count := len(pattern.Regex.FindAllStringIndex(text, -1))
+		if count == 0 {
+			continue
+		}
+
+		text = pattern.Regex.ReplaceAllString(text, pattern.Mask)
+		details = append(details, fmt.Sprintf("%s x%d", pattern.Type, count))
+	}
+	return text, details
+}
+
+func getBlocklistRegexes(guardrail *Guardrail) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	for _, keyword := range guardrail.Keywords {
+		if keyword != "" {
+			res = append(res, regexp.MustCompile(`(?i)`+regexp.QuoteMeta(keyword)))
+		}
+	}
+	for _, pattern := range guardrail.Patterns {
+		if pattern == "" {
+			continue
+		}
+
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the pattern: %s of the guardrail: %s is invalid, %s", pattern, guardrail.Name, err.Error())
+		}
+		res = append(res, regex)
+	}
+	return res, nil
+}
+
+// applyBlocklist returns the text with the blocklist matches masked and the matched keywords and patterns
+func applyBlocklist(guardrail *Guardrail, text string) (string, []string, error) {
+	regexes, err := getBlocklistRegexes(guardrail)
+	if err != nil {
+		return "", nil, err
+	}
+
+	matches := []string{}
+	for _, regex := range regexes {
+		if !regex.MatchString(text) {
+			continue
+		}
+
+		matches = append(matches, strings.TrimPrefix(regex.String(), "(?i)"))
+		text = regex.ReplaceAllString(text, "***")
+	}
+	return text, matches, nil
+}
+
+// moderateText asks the moderation model provider about the text, it returns the reason if the text is unsafe
+func moderateText(guardrail *Guardrail, text string) (string, error) {
+	answer, _, err := GetAnswerWithContext(guardrail.Provider, text, []*model.RawMessage{}, []*model.RawMessage{}, guardrailModerationPrompt)
+	if err != nil {
+		return "", fmt.Errorf("the moderation of the guardrail: %s failed, %s", guardrail.Name, err.Error())
+	}
+
+	answer = strings.TrimSpace(answer)
+	if strings.HasPrefix(strings.ToUpper(answer), "SAFE") {
+		return "", nil
+	}
+
+	reason := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(answer, "UNSAFE"), ":"))
+	if reason == "" {
+		reason = answer
+	}
+	return reason, nil
+}
+
+// ApplyGuardrails runs the guardrails of the stage on the text in order, it returns the text with the masks applied,
+// the audit events and whether a guardrail blocked the text, the chain stops at the first block
+func ApplyGuardrails(guardrails []Guardrail, stage string, text string) (string, []GuardrailEvent, bool, error) {
+	events := []GuardrailEvent{}
+	for i := range guardrails {
+		guardrail := &guardrails[i]
+		if !guardrail.appliesTo(stage) {
+			continue
+		}
+
+		var details []string
+		var isBlocked bool
+		var err error
+		switch guardrail.Type {
+		case GuardrailTypePii:
+			// The PII is masked unless the guardrail blocks
+			var maskedText string
+			maskedText, details = maskPii(text, guardrail.PiiTypes)
+			isBlocked = guardrail.Action == GuardrailActionBlock
+			if !isBlocked {
+				text = maskedText
+			}
+		case GuardrailTypeBlocklist:
+			// The blocklist blocks unless the guardrail masks
+			var maskedText string
+			maskedText, details, err = applyBlocklist(guardrail, text)
+			if err != nil {
+				return "", nil, false, err
+			}
+			isBlocked = guardrail.Action != GuardrailActionMask
+			if !isBlocked {
+				text = maskedText
+			}
+		case GuardrailTypeModeration:
+			var reason string
+			reason, err = moderateText(guardrail, text)
+			if err != nil {
+				return "", nil, false, err
+			}
+			if reason != "" {
+				details = []string{reason}
+			}
+			isBlocked = true
+		default:
+			return "", nil, false, fmt.Errorf("the guardrail type: %s is not supported", guardrail.Type)
+		}
+
+		if len(details) == 0 {
+			continue
+		}
+
+		action := "Masked"
+		if isBlocked {
+			action = "Blocked"
+		}
+		events = append(events, newGuardrailEvent(guardrail, stage, action, strings.Join(details, ", ")))
+		if isBlocked {
+			return text, events, true, nil
+		}
+	}
+	return text, events, false, nil
+}
+
+// CheckGuardrails returns the error of a guardrail that would fail the answers of the store, like an unsupported type
+// or an invalid blocklist pattern, so that it is reported when the store is saved. The disabled guardrails are
+// checked too, as they can be enabled later
+func CheckGuardrails(guardrails []Guardrail) error {
+	for i := range guardrails {
+		guardrail := &guardrails[i]
+		switch guardrail.Type {
+		case GuardrailTypePii, GuardrailTypeModeration:
+		case GuardrailTypeBlocklist:
+			_, err := getBlocklistRegexes(guardrail)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("the guardrail type: %s of the guardrail: %s is not supported", guardrail.Type, guardrail.Name)
+		}
+	}
+	return nil
+}
+
+// HasGuardrails returns whether any enabled guardrail of the store runs at the stage
+func HasGuardrails(guardrails []Guardrail, stage string) bool {
+	for i := range guardrails {
+		if guardrails[i].appliesTo(stage) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskInputPii masks the PII in the text with the masking PII guardrails of the input stage
+func maskInputPii(guardrails []Guardrail, text string) string {
+	for i := range guardrails {
+		guardrail := &guardrails[i]
+		if guardrail.Type != GuardrailTypePii || guardrail.Action == GuardrailActionBlock || !guardrail.appliesTo(GuardrailStageInput) {
+			continue
+		}
+
+		text, _ = maskPii(text, guardrail.PiiTypes)
+	}
+	return text
+}
+
+// MaskHistoryPii masks the PII in the history with the PII guardrails of the input stage, so that the earlier
+// messages don't leak what the guardrails mask in the questions
+func MaskHistoryPii(guardrails []Guardrail, history []*model.RawMessage) {
+	for _, message := range history {
+		message.Text = maskInputPii(guardrails, message.Text)
+	}
+}
+
+func GetGuardrailBlockedText(stage string) string {
+	if stage == GuardrailStageOutput {
+		return "Sorry, the answer was withheld by the content policy of this store."
+	}
+	return "Sorry, the question can't be answered because it violates the content policy of this store."
+}
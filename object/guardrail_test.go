@@ -0,0 +1,121 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/casibase/casibase/model"
+)
+
+func TestMaskPii(t *testing.T) {
+	tests := []struct {
+		text    string
+		types   []string
+		want    string
+		details []string
+	}{
+		{"Mail me at alice@example.com", nil, "Mail me at [EMAIL]", []string{"Email x1"}},
+		{"Call 13812345678 or +1 415-555-0100", nil, "Call [PHONE] or [PHONE]", []string{"Phone x2"}},
+		{"My ID is 11010519491231002X and SSN 123-45-6789", nil, "My ID is [ID_NUMBER] and SSN [ID_NUMBER]", []string{"ID Number x2"}},
+		{"key=sk-abcdefghijklmnopqrstuvwxyz123456", nil, "key=[API_KEY]", []string{"API Key x1"}},
+		{"alice@example.com 13812345678", []string{"Phone"}, "alice@example.com [PHONE]", []string{"Phone x1"}},
+		{"Nothing to mask in version 1.22", nil, "Nothing to mask in version 1.22", []string{}},
+	}
+	for _, test := range tests {
+		text, details := maskPii(test.text, test.types)
+		if text != test.want || !reflect.DeepEqual(details, test.details) {
+			t.Errorf("maskPii(%q) = %q, %v, want %q, %v", test.text, text, details, test.want, test.details)
+		}
+	}
+}
+
+func TestApplyGuardrails(t *testing.T) {
+	guardrails := []Guardrail{
+		{Name: "pii", Type: GuardrailTypePii, Stage: GuardrailStageInput, Action: GuardrailActionMask, IsEnabled: true},
+		{Name: "masked-words", Type: GuardrailTypeBlocklist, Stage: GuardrailStageBoth, Action: GuardrailActionMask, Keywords: []string{"Project X"}, IsEnabled: true},
+		{Name: "blocked-words", Type: GuardrailTypeBlocklist, Stage: GuardrailStageBoth, Action: GuardrailActionBlock, Patterns: []string{`(?i)\bpassword dump\b`}, IsEnabled: true},
+		{Name: "disabled", Type: GuardrailTypeBlocklist, Action: GuardrailActionBlock, Keywords: []string{"hello"}},
+	}
+
+	text, events, isBlocked, err := ApplyGuardrails(guardrails, GuardrailStageInput, "Hello, I'm bob@example.com and I work on project x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isBlocked || text != "Hello, I'm [EMAIL] and I work on ***" {
+		t.Errorf("ApplyGuardrails() = %q, %v, want the email and keyword masked", text, isBlocked)
+	}
+	if len(events) != 2 || events[0].Guardrail != "pii" || events[0].Action != "Masked" || events[1].Detail != "Project X" {
+		t.Errorf("ApplyGuardrails() events = %+v", events)
+	}
+
+	_, events, isBlocked, err = ApplyGuardrails(guardrails, GuardrailStageOutput, "Here is the Password Dump for bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isBlocked || len(events) != 1 || events[0].Guardrail != "blocked-words" || events[0].Action != "Blocked" || events[0].Stage != GuardrailStageOutput {
+		t.Errorf("ApplyGuardrails() = %v, %+v, want blocked by blocked-words", isBlocked, events)
+	}
+
+	_, _, _, err = ApplyGuardrails([]Guardrail{{Name: "invalid", Type: GuardrailTypeBlocklist, Patterns: []string{"("}, IsEnabled: true}}, GuardrailStageInput, "text")
+	if err == nil {
+		t.Errorf("ApplyGuardrails() should fail for an invalid pattern")
+	}
+}
+
+func TestCheckGuardrails(t *testing.T) {
+	tests := []struct {
+		name       string
+		guardrails []Guardrail
+		isValid    bool
+	}{
+		{"valid", []Guardrail{{Name: "pii", Type: GuardrailTypePii}, {Name: "words", Type: GuardrailTypeBlocklist, Keywords: []string{"("}, Patterns: []string{`\bsecret\b`}}}, true},
+		{"invalid pattern", []Guardrail{{Name: "words", Type: GuardrailTypeBlocklist, Patterns: []string{"("}}}, false},
+		{"disabled invalid pattern", []Guardrail{{Name: "words", Type: GuardrailTypeBlocklist, Patterns: []string{"[a-"}, IsEnabled: false}}, false},
+		{"unsupported type", []Guardrail{{Name: "unknown", Type: "Unknown"}}, false},
+	}
+
+	for _, test := range tests {
+		err := CheckGuardrails(test.guardrails)
+		if (err == nil) != test.isValid {
+			t.Errorf("%s: CheckGuardrails() error = %v, want valid = %v", test.name, err, test.isValid)
+		}
+	}
+}
+
+func TestMaskHistoryPii(t *testing.T) {
+	guardrails := []Guardrail{{Name: "pii", Type: GuardrailTypePii, Stage: GuardrailStageInput, IsEnabled: true}}
+	history := []*model.RawMessage{{Text: "I'm bob@example.com", Author: "bob"}}
+
+	MaskHistoryPii(guardrails, history)
+	if history[0].Text != "I'm [EMAIL]" {
+		t.Errorf("MaskHistoryPii() = %q, want the email masked", history[0].Text)
+	}
+}
+
+func TestHasGuardrails(t *testing.T) {
+	guardrails := []Guardrail{
+		{Name: "pii", Type: GuardrailTypePii, Stage: GuardrailStageInput, IsEnabled: true},
+		{Name: "disabled", Type: GuardrailTypeBlocklist, Stage: GuardrailStageOutput, Keywords: []string{"hello"}},
+	}
+
+	if !HasGuardrails(guardrails, GuardrailStageInput) {
+		t.Errorf("HasGuardrails() = false for the input stage, want true")
+	}
+	if HasGuardrails(guardrails, GuardrailStageOutput) {
+		t.Errorf("HasGuardrails() = true for the output stage, want false for a disabled guardrail")
+	}
+}
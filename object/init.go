@@ -64,6 +64,7 @@ func initBuiltInStore(modelProviderName string, embeddingProviderName string, tt
 		SpeechToTextProvider: sttProviderName,
 		Frequency:            10000,
 		MemoryLimit:          10,
+		MemoryStrategy:       "Window",
 		LimitMinutes:         15,
 		Welcome:              "Hello",
 		WelcomeTitle:         "Hello, this is the Casibase AI Assistant",
@@ -0,0 +1,383 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// Memory is a fact about a user extracted from the conversations, it is shared by all the chats of the user
+type Memory struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	UpdatedTime string `xorm:"varchar(100)" json:"updatedTime"`
+
+	User     string `xorm:"varchar(100) index" json:"user"`
+	Store    string `xorm:"varchar(100)" json:"store"`
+	Chat     string `xorm:"varchar(100)" json:"chat"`
+	Message  string `xorm:"varchar(100)" json:"message"`
+	Text     string `xorm:"mediumtext" json:"text"`
+	Provider string `xorm:"varchar(100)" json:"provider"`
+
+	Data []float32 `xorm:"mediumtext" json:"-"`
+}
+
+func GetGlobalMemories() ([]*Memory, error) {
+	memories := []*Memory{}
+	err := adapter.engine.Asc("owner").Desc("created_time").Find(&memories)
+	if err != nil {
+		return memories, err
+	}
+
+	return memories, nil
+}
+
+func GetMemories(owner string, user string) ([]*Memory, error) {
+	memories := []*Memory{}
+	if user == "" {
+		return memories, fmt.Errorf("the user of memories should not be empty")
+	}
+
+	// "user" is a reserved word in some databases, so the column is quoted by the dialect
+	err := adapter.engine.Where(fmt.Sprintf("owner = ? and %s = ?", adapter.engine.Quote("user")), owner, user).Desc("created_time").Find(&memories)
+	if err != nil {
+		return memories, err
+	}
+
+	return memories, nil
+}
+
+func getMemory(owner string, name string) (*Memory, error) {
+	memory := Memory{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&memory)
+	if err != nil {
+		return &memory, err
+	}
+
+	if existed {
+		return &memory, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetMemory(id string) (*Memory, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	return getMemory(owner, name)
+}
+
+func UpdateMemory(id string, memory *Memory) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	m, err := getMemory(owner, name)
+	if err != nil {
+		return false, err
+	}
+	if m == nil {
+		return false, nil
+	}
+
+	memory.Data = m.Data
+	if memory.Text != m.Text || len(memory.Data) == 0 {
+		err = memory.updateVector()
+		if err != nil {
+			return false, err
+		}
+	}
+	memory.UpdatedTime = util.GetCurrentTime()
+
+	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(memory)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func AddMemory(memory *Memory) (bool, error) {
+	if len(memory.Data) == 0 {
+		err := memory.updateVector()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	affected, err := adapter.engine.Insert(memory)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteMemory(memory *Memory) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{memory.Owner, memory.Name}).Delete(&Memory{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (memory *Memory) GetId() string {
+	return fmt.Sprintf("%s/%s", memory.Owner, memory.Name)
+}
+
+// updateVector embeds the text of the memory with the embedding provider of its store
+func (memory *Memory) updateVector() error {
+	store, err := getStore("admin", memory.Store)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("the store: %s for the memory: %s is not found", memory.Store, memory.GetId())
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider)
+	if err != nil {
+		return err
+	}
+
+	vector, _, err := queryVectorSafe(embeddingProviderObj, memory.Text)
+	if err != nil {
+		return err
+	}
+
+	memory.Provider = embeddingProvider.Name
+	memory.Data = vector
+	return nil
+}
+
+// memoryCount is the maximum number of memories added to the knowledge of a question
+const memoryCount = 5
+
+// memorySimilarityThreshold is the similarity above which a memory is relevant to a question
+const memorySimilarityThreshold = 0.3
+
+// memoryDuplicateThreshold is the similarity above which a new fact replaces an existing memory
+const memoryDuplicateThreshold = 0.9
+
+const memoryExtractionPrompt = `You extract long-term memory about a user from a conversation turn. Return the durable facts the user states about themselves, their work, their environment or their preferences that would help in future conversations, such as "The user is on the payments team" or "The user uses Go 1.22". Ignore the questions themselves, temporary requests and anything the assistant said that the user did not confirm. Answer with a JSON array of short sentences in the language of the user, and answer [] if there is nothing worth remembering.`
+
+func parseMemoryFacts(answer string) ([]string, error) {
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.TrimPrefix(answer, "```")
+	answer = strings.TrimSuffix(answer, "```")
+	answer = strings.TrimSpace(answer)
+
+	facts := []string{}
+	err := json.Unmarshal([]byte(answer), &facts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the memory facts: %s, %s", answer, err.Error())
+	}
+
+	res := []string{}
+	for _, fact := range facts {
+		fact = strings.TrimSpace(fact)
+		if fact != "" {
+			res = append(res, fact)
+		}
+	}
+	return res, nil
+}
+
+func getNearestMemories(memories []*Memory, vector []float32, n int, threshold float32) []*Memory {
+	vectors := [][]float32{}
+	for _, memory := range memories {
+		vectors = append(vectors, memory.Data)
+	}
+
+	similarities, err := getNearestVectors(vector, vectors, n)
+	if err != nil {
+		return nil
+	}
+
+	res := []*Memory{}
+	for _, similarity := range similarities {
+		if similarity.Similarity >= threshold {
+			res = append(res, memories[similarity.Index])
+		}
+	}
+	return res
+}
+
+func getEmbeddedMemories(user string, embeddingProviderName string) ([]*Memory, error) {
+	memories, err := GetMemories("admin", user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Memory{}
+	for _, memory := range memories {
+		if memory.Provider == embeddingProviderName && len(memory.Data) != 0 {
+			res = append(res, memory)
+		}
+	}
+	return res, nil
+}
+
+// GetRelevantMemories returns the memories of the user most similar to the question as knowledge messages,
+// their "Memory" author makes getSystemMessages() present them as facts about the user
+func GetRelevantMemories(store *Store, user string, question string) ([]*model.RawMessage, error) {
+	res := []*model.RawMessage{}
+	if !store.EnableMemory || user == "" {
+		return res, nil
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	memories, err := getEmbeddedMemories(user, embeddingProvider.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(memories) == 0 {
+		return res, nil
+	}
+
+	vector, _, err := queryVectorSafe(embeddingProviderObj, question)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, memory := range getNearestMemories(memories, vector, memoryCount, memorySimilarityThreshold) {
+		tokenCount, err := getMessageTextTokenCount("", memory.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, &model.RawMessage{
+			Text:           memory.Text,
+			Author:         "Memory",
+			TextTokenCount: tokenCount,
+		})
+	}
+	return res, nil
+}
+
+// addChatCost adds the cost of a call made in the background to the chat, the counts are added by the database, so
+// that the chat saved by the answer meanwhile is kept
+func addChatCost(chatName string, tokenCount int, price float64, currency string) error {
+	chat, err := getChat("admin", chatName)
+	if err != nil {
+		return err
+	}
+	if chat == nil {
+		return nil
+	}
+
+	_, err = AddChatCost(chat, tokenCount, price, currency)
+	return err
+}
+
+// ExtractMemories asks the memory provider of the store for the facts about the user in the question and answer,
+// and saves them as memories, a fact similar to an existing memory replaces the text of that memory
+func ExtractMemories(store *Store, message *Message, question string, answer string) error {
+	if !store.EnableMemory || message.User == "" {
+		return nil
+	}
+
+	memoryProvider := store.MemoryProvider
+	if memoryProvider == "" {
+		memoryProvider = store.ModelProvider
+	}
+
+	text := fmt.Sprintf("User: %s\nAssistant: %s", question, answer)
+	factsAnswer, modelResult, err := GetAnswerWithContext(memoryProvider, text, []*model.RawMessage{}, []*model.RawMessage{}, memoryExtractionPrompt)
+	if err != nil {
+		return err
+	}
+	if modelResult != nil {
+		err = addChatCost(message.Chat, modelResult.TotalTokenCount, modelResult.TotalPrice, modelResult.Currency)
+		if err != nil {
+			return err
+		}
+	}
+
+	facts, err := parseMemoryFacts(factsAnswer)
+	if err != nil {
+		return err
+	}
+	if len(facts) == 0 {
+		return nil
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider)
+	if err != nil {
+		return err
+	}
+
+	memories, err := getEmbeddedMemories(message.User, embeddingProvider.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, fact := range facts {
+		vector, embeddingResult, err := queryVectorSafe(embeddingProviderObj, fact)
+		if err != nil {
+			return err
+		}
+		if embeddingResult != nil {
+			err = addChatCost(message.Chat, embeddingResult.TokenCount, embeddingResult.Price, embeddingResult.Currency)
+			if err != nil {
+				return err
+			}
+		}
+
+		duplicates := getNearestMemories(memories, vector, 1, memoryDuplicateThreshold)
+		if len(duplicates) != 0 {
+			memory := duplicates[0]
+			memory.Text = fact
+			memory.Data = vector
+			memory.Message = message.Name
+			memory.UpdatedTime = util.GetCurrentTime()
+			_, err = adapter.engine.ID(core.PK{memory.Owner, memory.Name}).AllCols().Update(memory)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		memory := &Memory{
+			Owner:       "admin",
+			Name:        fmt.Sprintf("memory_%s", util.GetRandomName()),
+			CreatedTime: util.GetCurrentTime(),
+			UpdatedTime: util.GetCurrentTime(),
+			User:        message.User,
+			Store:       store.Name,
+			Chat:        message.Chat,
+			Message:     message.Name,
+			Text:        fact,
+			Provider:    embeddingProvider.Name,
+			Data:        vector,
+		}
+		_, err = AddMemory(memory)
+		if err != nil {
+			return err
+		}
+		memories = append(memories, memory)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMemoryFacts(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   []string
+	}{
+		{`["The user is on the payments team", "The user uses Go 1.22"]`, []string{"The user is on the payments team", "The user uses Go 1.22"}},
+		{"```json\n[\"The user prefers short answers\", \" \"]\n```", []string{"The user prefers short answers"}},
+		{"[]", []string{}},
+	}
+	for _, test := range tests {
+		facts, err := parseMemoryFacts(test.answer)
+		if err != nil {
+			t.Fatalf("parseMemoryFacts(%q) error: %s", test.answer, err.Error())
+		}
+		if !reflect.DeepEqual(facts, test.want) {
+			t.Errorf("parseMemoryFacts(%q) = %v, want %v", test.answer, facts, test.want)
+		}
+	}
+
+	_, err := parseMemoryFacts("The user is on the payments team")
+	if err == nil {
+		t.Errorf("parseMemoryFacts() should fail for an answer that is not a JSON array")
+	}
+}
+
+func TestGetNearestMemories(t *testing.T) {
+	memories := []*Memory{
+		{Name: "team", Data: []float32{1, 0}},
+		{Name: "language", Data: []float32{0, 1}},
+		{Name: "both", Data: []float32{1, 1}},
+	}
+
+	res := getNearestMemories(memories, []float32{1, 0.1}, 2, 0.5)
+	if len(res) != 2 || res[0].Name != "team" || res[1].Name != "both" {
+		t.Errorf("getNearestMemories() = %v, want [team both]", res)
+	}
+
+	res = getNearestMemories(memories, []float32{1, 0}, 1, 0.99)
+	if len(res) != 1 || res[0].Name != "team" {
+		t.Errorf("getNearestMemories() with a duplicate threshold = %v, want [team]", res)
+	}
+}
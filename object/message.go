@@ -16,6 +16,7 @@ package object
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -41,32 +42,42 @@ type Message struct {
 	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
 	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
 
-	Organization      string        `xorm:"varchar(100)" json:"organization"`
-	Store             string        `xorm:"varchar(100)" json:"store"`
-	User              string        `xorm:"varchar(100) index" json:"user"`
-	Chat              string        `xorm:"varchar(100) index" json:"chat"`
-	ReplyTo           string        `xorm:"varchar(100) index" json:"replyTo"`
-	Author            string        `xorm:"varchar(100)" json:"author"`
-	Text              string        `xorm:"mediumtext" json:"text"`
-	ReasonText        string        `xorm:"mediumtext" json:"reasonText"`
-	ErrorText         string        `xorm:"mediumtext" json:"errorText"`
-	FileName          string        `xorm:"varchar(100)" json:"fileName"`
-	Comment           string        `xorm:"mediumtext" json:"comment"`
-	TokenCount        int           `json:"tokenCount"`
-	TextTokenCount    int           `json:"textTokenCount"`
-	Price             float64       `json:"price"`
-	Currency          string        `xorm:"varchar(100)" json:"currency"`
-	IsHidden          bool          `json:"isHidden"`
-	IsDeleted         bool          `json:"isDeleted"`
-	NeedNotify        bool          `json:"needNotify"`
-	IsAlerted         bool          `json:"isAlerted"`
-	IsRegenerated     bool          `json:"isRegenerated"`
-	ModelProvider     string        `xorm:"varchar(100)" json:"modelProvider"`
-	EmbeddingProvider string        `xorm:"varchar(100)" json:"embeddingProvider"`
-	VectorScores      []VectorScore `xorm:"mediumtext" json:"vectorScores"`
-	LikeUsers         []string      `json:"likeUsers"`
-	DisLikeUsers      []string      `json:"dislikeUsers"`
-	Suggestions       []Suggestion  `json:"suggestions"`
+	Organization      string                `xorm:"varchar(100)" json:"organization"`
+	Store             string                `xorm:"varchar(100)" json:"store"`
+	User              string                `xorm:"varchar(100) index" json:"user"`
+	Chat              string                `xorm:"varchar(100) index" json:"chat"`
+	ReplyTo           string                `xorm:"varchar(100) index" json:"replyTo"`
+	ParentMessage     string                `xorm:"varchar(100)" json:"parentMessage"`
+	Author            string                `xorm:"varchar(100)" json:"author"`
+	Text              string                `xorm:"mediumtext" json:"text"`
+	ReasonText        string                `xorm:"mediumtext" json:"reasonText"`
+	ErrorText         string                `xorm:"mediumtext" json:"errorText"`
+	State             string                `xorm:"varchar(100)" json:"state"`
+	FileName          string                `xorm:"varchar(100)" json:"fileName"`
+	Comment           string                `xorm:"mediumtext" json:"comment"`
+	TokenCount        int                   `json:"tokenCount"`
+	TextTokenCount    int                   `json:"textTokenCount"`
+	Price             float64               `json:"price"`
+	Currency          string                `xorm:"varchar(100)" json:"currency"`
+	IsHidden          bool                  `json:"isHidden"`
+	IsDeleted         bool                  `json:"isDeleted"`
+	NeedNotify        bool                  `json:"needNotify"`
+	IsAlerted         bool                  `json:"isAlerted"`
+	IsRegenerated     bool                  `json:"isRegenerated"`
+	NeedStop          bool                  `json:"needStop"`
+	ModelProvider     string                `xorm:"varchar(100)" json:"modelProvider"`
+	UsedModelProvider string                `xorm:"varchar(100)" json:"usedModelProvider"`
+	EmbeddingProvider string                `xorm:"varchar(100)" json:"embeddingProvider"`
+	VectorScores      []VectorScore         `xorm:"mediumtext" json:"vectorScores"`
+	LikeUsers         []string              `json:"likeUsers"`
+	DisLikeUsers      []string              `json:"dislikeUsers"`
+	Suggestions       []Suggestion          `json:"suggestions"`
+	GuardrailEvents   []GuardrailEvent      `xorm:"mediumtext" json:"guardrailEvents"`
+	AgentSteps        []*model.AgentStep    `xorm:"mediumtext" json:"agentSteps"`
+	ToolApprovals     []*model.ToolApproval `xorm:"mediumtext" json:"toolApprovals"`
+	CarrierValues     map[string]string     `xorm:"mediumtext" json:"carrierValues"`
+
+	Siblings []string `xorm:"-" json:"siblings"`
 }
 
 func GetGlobalMessages() ([]*Message, error) {
@@ -223,25 +234,37 @@ func AddMessage(message *Message) (bool, error) {
 		return false, err
 	}
 	message.TextTokenCount = size
+
+	var chat *Chat
+	if message.Chat != "" {
+		chat, err = getChat(message.Owner, message.Chat)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if chat != nil {
+		err = setMessageParent(message, chat)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	affected, err := adapter.engine.Insert(message)
 	if err != nil {
 		return false, err
 	}
 
-	if affected != 0 {
-		var chat *Chat
-		chat, err = getChat(message.Owner, message.Chat)
+	if affected != 0 && chat != nil {
+		_, err = AddChatUsage(chat.GetId(), 1, 0, 0, "")
 		if err != nil {
 			return false, err
 		}
 
-		if chat != nil {
-			chat.UpdatedTime = util.GetCurrentTime()
-			chat.MessageCount += 1
-			_, err = UpdateChat(chat.GetId(), chat)
-			if err != nil {
-				return false, err
-			}
+		// The new message is the leaf of the active branch
+		err = setChatActiveMessage(chat.Owner, chat.Name, message.Name)
+		if err != nil {
+			return false, err
 		}
 	}
 
@@ -257,29 +280,6 @@ func DeleteMessage(message *Message) (bool, error) {
 	return affected != 0, nil
 }
 
-func DeleteAllLaterMessages(messageId string) error {
-	originMessage, err := GetMessage(messageId)
-	if err != nil {
-		return err
-	}
-	// Get all messages for this chat
-	allMessages, err := GetChatMessages(originMessage.Chat)
-	if err != nil {
-		return err
-	}
-
-	// Find and delete messages created after the original message
-	for _, msg := range allMessages {
-		if msg.CreatedTime >= originMessage.CreatedTime {
-			_, err := DeleteMessage(msg)
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
 func DeleteMessagesByChat(message *Message) (bool, error) {
 	affected, err := adapter.engine.Delete(&Message{Owner: message.Owner, Chat: message.Chat})
 	if err != nil {
@@ -293,18 +293,29 @@ func (message *Message) GetId() string {
 	return fmt.Sprintf("%s/%s", message.Owner, message.Name)
 }
 
-func GetRecentRawMessages(chat string, createdTime string, memoryLimit int) ([]*model.RawMessage, error) {
+// GetRecentRawMessages returns the history for the answer message, newest first. The history follows the branch
+// of the answer, without the answer and its question
+func GetRecentRawMessages(message *Message, memoryLimit int) ([]*model.RawMessage, error) {
+	branch, err := GetMessageBranch(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRecentRawMessages(branch, memoryLimit)
+}
+
+func getRecentRawMessages(branch []*Message, memoryLimit int) ([]*model.RawMessage, error) {
 	res := []*model.RawMessage{}
 	if memoryLimit == 0 {
 		return res, nil
 	}
 
 	messages := []*Message{}
-	err := adapter.engine.Where("created_time <= ?", createdTime).Desc("created_time").Limit(2*memoryLimit, 2).Find(&messages, &Message{Chat: chat})
-	if err != nil {
-		return nil, err
+	for i := len(branch) - 3; i >= 0 && len(messages) < 2*memoryLimit; i-- {
+		messages = append(messages, branch[i])
 	}
 
+	var err error
 	for _, message := range messages {
 		rawTextTokenCount := message.TextTokenCount
 		if rawTextTokenCount == 0 {
@@ -318,6 +329,18 @@ func GetRecentRawMessages(chat string, createdTime string, memoryLimit int) ([]*
 			Author:         message.Author,
 			TextTokenCount: message.TextTokenCount,
 		}
+
+		// The tool calls of the answer are kept in the history, so that they are not repeated for nothing
+		agentStepsText := model.GetAgentStepsText(message.AgentSteps)
+		if agentStepsText != "" {
+			agentStepsTokenCount, err := getMessageTextTokenCount(message.ModelProvider, agentStepsText)
+			if err != nil {
+				return nil, err
+			}
+
+			rawMessage.Text = agentStepsText + "\n\n" + rawMessage.Text
+			rawMessage.TextTokenCount += agentStepsTokenCount
+		}
 		res = append(res, rawMessage)
 	}
 	return res, nil
@@ -356,7 +379,7 @@ func GetAnswerWithContext(provider string, question string, history []*model.Raw
 		prompt = "You are an expert in your field and you specialize in using your knowledge to answer or solve people's problems."
 	}
 	var writer MyWriter
-	modelResult, err := modelProviderObj.QueryText(question, &writer, history, prompt, knowledge, nil)
+	modelResult, err := modelProviderObj.QueryText(context.Background(), question, &writer, history, prompt, knowledge, nil, nil)
 	if err != nil {
 		return "", nil, err
 	}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// The tool approvals are stored on the message, so that the decision of the user reaches the answer whichever
+// instance serves it, and the decided calls are kept with the answer
+
+func (message *Message) getToolApproval(toolCallId string) *model.ToolApproval {
+	for _, approval := range message.ToolApprovals {
+		if approval.ToolCallId == toolCallId {
+			return approval
+		}
+	}
+	return nil
+}
+
+// decideToolApproval sets the state of the pending approval of the tool call, it returns false if there is none
+func (message *Message) decideToolApproval(toolCallId string, approved bool) bool {
+	approval := message.getToolApproval(toolCallId)
+	if approval == nil || approval.State != model.ToolApprovalStatePending {
+		return false
+	}
+
+	approval.State = model.ToolApprovalStateRejected
+	if approved {
+		approval.State = model.ToolApprovalStateApproved
+	}
+	return true
+}
+
+func updateMessageToolApprovals(message *Message) error {
+	_, err := adapter.engine.ID(core.PK{message.Owner, message.Name}).Cols("tool_approvals").Update(message)
+	return err
+}
+
+// AddMessageToolApproval stores the approval on the message as pending
+func AddMessageToolApproval(message *Message, approval *model.ToolApproval) error {
+	approval.State = model.ToolApprovalStatePending
+	message.ToolApprovals = append(message.ToolApprovals, approval)
+	return updateMessageToolApprovals(message)
+}
+
+// GetMessageToolApprovalState returns the stored state of the approval of the tool call, or "" if there is none
+func GetMessageToolApprovalState(id string, toolCallId string) (string, error) {
+	message, err := GetMessage(id)
+	if err != nil {
+		return "", err
+	}
+	if message == nil {
+		return "", nil
+	}
+
+	approval := message.getToolApproval(toolCallId)
+	if approval == nil {
+		return "", nil
+	}
+	return approval.State, nil
+}
+
+// DecideMessageToolApproval stores the decision of the user on the pending approval of the tool call, it returns
+// false if the message has no such approval
+func DecideMessageToolApproval(id string, toolCallId string, approved bool) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	message, err := getMessage(owner, name)
+	if err != nil {
+		return false, err
+	}
+	if message == nil || !message.decideToolApproval(toolCallId, approved) {
+		return false, nil
+	}
+
+	err = updateMessageToolApprovals(message)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExpireMessageToolApproval rejects the approval of the tool call if it is still pending and returns its final
+// state. The message is read again, so that a decision stored just before on any instance is kept instead of being
+// overwritten by the approvals in memory
+func ExpireMessageToolApproval(id string, toolCallId string) (string, error) {
+	decided, err := DecideMessageToolApproval(id, toolCallId, false)
+	if err != nil {
+		return "", err
+	}
+	if decided {
+		return model.ToolApprovalStateRejected, nil
+	}
+
+	state, err := GetMessageToolApprovalState(id, toolCallId)
+	if err != nil {
+		return "", err
+	}
+	if state == "" || state == model.ToolApprovalStatePending {
+		return model.ToolApprovalStateRejected, nil
+	}
+	return state, nil
+}
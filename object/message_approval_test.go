@@ -0,0 +1,47 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+
+	"github.com/casibase/casibase/model"
+)
+
+func TestDecideToolApproval(t *testing.T) {
+	message := &Message{
+		ToolApprovals: []*model.ToolApproval{
+			{ToolCallId: "call_1", State: model.ToolApprovalStatePending},
+			{ToolCallId: "call_2", State: model.ToolApprovalStatePending},
+		},
+	}
+
+	if !message.decideToolApproval("call_1", true) {
+		t.Fatal("decideToolApproval(call_1) should decide the pending approval")
+	}
+	if message.decideToolApproval("call_1", false) {
+		t.Fatal("decideToolApproval(call_1) should not decide an approval twice")
+	}
+	if message.decideToolApproval("call_3", true) {
+		t.Fatal("decideToolApproval(call_3) should not decide a missing approval")
+	}
+	if !message.decideToolApproval("call_2", false) {
+		t.Fatal("decideToolApproval(call_2) should decide the pending approval")
+	}
+
+	if message.ToolApprovals[0].State != model.ToolApprovalStateApproved || message.ToolApprovals[1].State != model.ToolApprovalStateRejected {
+		t.Fatalf("the states are %s and %s, want Approved and Rejected", message.ToolApprovals[0].State, message.ToolApprovals[1].State)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import "xorm.io/core"
+
+// MessageParentRoot is the parent of the first messages of a chat, like "Welcome" is the question of the welcome message
+const MessageParentRoot = "Root"
+
+// messageTree is the tree of the messages of a chat: an edited question is a sibling of the original question, and a
+// regenerated answer is a sibling of the original answer. The messages without a parent were added before the
+// branches, so they follow the previous message like a linear chat
+type messageTree struct {
+	messages   []*Message
+	messageMap map[string]*Message
+	parents    map[string]string
+	children   map[string][]*Message
+}
+
+func newMessageTree(messages []*Message) *messageTree {
+	tree := &messageTree{
+		messages:   messages,
+		messageMap: map[string]*Message{},
+		parents:    map[string]string{},
+		children:   map[string][]*Message{},
+	}
+
+	var previous *Message
+	for _, message := range messages {
+		parent := message.ParentMessage
+		if parent != MessageParentRoot && tree.messageMap[parent] == nil {
+			// The parent is not set or has been deleted
+			parent = MessageParentRoot
+			if previous != nil {
+				parent = previous.Name
+			}
+		}
+
+		tree.messageMap[message.Name] = message
+		tree.parents[message.Name] = parent
+		tree.children[parent] = append(tree.children[parent], message)
+		previous = message
+	}
+	return tree
+}
+
+// getLatestLeaf follows the latest child from the message down to a leaf
+func (tree *messageTree) getLatestLeaf(message *Message) *Message {
+	for {
+		children := tree.children[message.Name]
+		if len(children) == 0 {
+			return message
+		}
+		message = children[len(children)-1]
+	}
+}
+
+// getActiveLeaf returns the leaf of the active branch, which is the last message if the chat has no active message
+func (tree *messageTree) getActiveLeaf(activeMessage string) *Message {
+	if message, ok := tree.messageMap[activeMessage]; ok {
+		return tree.getLatestLeaf(message)
+	}
+
+	if len(tree.messages) == 0 {
+		return nil
+	}
+	return tree.messages[len(tree.messages)-1]
+}
+
+// getBranch returns the messages from the root down to the message, oldest first
+func (tree *messageTree) getBranch(message *Message) []*Message {
+	res := []*Message{}
+	for message != nil {
+		res = append([]*Message{message}, res...)
+		message = tree.messageMap[tree.parents[message.Name]]
+	}
+	return res
+}
+
+func (tree *messageTree) getSiblings(message *Message) []*Message {
+	return tree.children[tree.parents[message.Name]]
+}
+
+func getChatMessageTree(chat string) (*messageTree, error) {
+	messages := []*Message{}
+	if chat != "" {
+		var err error
+		messages, err = GetChatMessages(chat)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newMessageTree(messages), nil
+}
+
+// GetChatBranchMessages returns the messages of the active branch of the chat, oldest first. The messages with
+// siblings have the names of the siblings, so that the branches can be switched to
+func GetChatBranchMessages(chatName string) ([]*Message, error) {
+	tree, err := getChatMessageTree(chatName)
+	if err != nil {
+		return nil, err
+	}
+	if len(tree.messages) == 0 {
+		return tree.messages, nil
+	}
+
+	chat, err := getChat(tree.messages[0].Owner, chatName)
+	if err != nil {
+		return nil, err
+	}
+
+	activeMessage := ""
+	if chat != nil {
+		activeMessage = chat.ActiveMessage
+	}
+
+	messages := tree.getBranch(tree.getActiveLeaf(activeMessage))
+	for _, message := range messages {
+		siblings := tree.getSiblings(message)
+		if len(siblings) <= 1 {
+			continue
+		}
+
+		message.Siblings = []string{}
+		for _, sibling := range siblings {
+			message.Siblings = append(message.Siblings, sibling.Name)
+		}
+	}
+	return messages, nil
+}
+
+// GetMessageBranch returns the messages from the root of the chat down to the message, oldest first
+func GetMessageBranch(message *Message) ([]*Message, error) {
+	tree, err := getChatMessageTree(message.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	treeMessage, ok := tree.messageMap[message.Name]
+	if !ok {
+		return []*Message{}, nil
+	}
+	return tree.getBranch(treeMessage), nil
+}
+
+// GetMessageParent returns the parent of the message in the tree of its chat, the new branch of an edited question
+// forks from it
+func GetMessageParent(message *Message) (string, error) {
+	tree, err := getChatMessageTree(message.Chat)
+	if err != nil {
+		return "", err
+	}
+
+	parent, ok := tree.parents[message.Name]
+	if !ok {
+		return MessageParentRoot, nil
+	}
+	return parent, nil
+}
+
+// setMessageParent sets the parent of the new message to the leaf of the active branch if it is not given, a
+// given parent elsewhere forks a new branch
+func setMessageParent(message *Message, chat *Chat) error {
+	tree, err := getChatMessageTree(message.Chat)
+	if err != nil {
+		return err
+	}
+
+	leafName := MessageParentRoot
+	if leaf := tree.getActiveLeaf(chat.ActiveMessage); leaf != nil {
+		leafName = leaf.Name
+	}
+
+	if message.ParentMessage == "" {
+		message.ParentMessage = leafName
+		return nil
+	}
+	if message.ParentMessage != leafName {
+		return resetChatSummaryForBranch(chat, tree.messageMap[message.ParentMessage])
+	}
+	return nil
+}
+
+func setChatActiveMessage(owner string, chatName string, messageName string) error {
+	_, err := adapter.engine.ID(core.PK{owner, chatName}).Cols("active_message").Update(&Chat{ActiveMessage: messageName})
+	return err
+}
+
+// SwitchMessageBranch makes the branch of the message the active branch of its chat, down to the latest leaf
+// under the message
+func SwitchMessageBranch(message *Message) (bool, error) {
+	tree, err := getChatMessageTree(message.Chat)
+	if err != nil {
+		return false, err
+	}
+
+	treeMessage, ok := tree.messageMap[message.Name]
+	if !ok {
+		return false, nil
+	}
+
+	chat, err := getChat(treeMessage.Owner, treeMessage.Chat)
+	if err != nil {
+		return false, err
+	}
+	if chat == nil {
+		return false, nil
+	}
+
+	err = resetChatSummaryForBranch(chat, tree.messageMap[tree.parents[treeMessage.Name]])
+	if err != nil {
+		return false, err
+	}
+
+	err = setChatActiveMessage(chat.Owner, chat.Name, tree.getLatestLeaf(treeMessage).Name)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resetChatSummaryForBranch clears the rolling summary of the chat if the branches fork before the summarized
+// messages, because the summary may contain the messages of the other branch. It is rebuilt from the active branch
+// by the next answer
+func resetChatSummaryForBranch(chat *Chat, forkMessage *Message) error {
+	if chat.SummaryTime == "" {
+		return nil
+	}
+	if forkMessage != nil && forkMessage.CreatedTime >= chat.SummaryTime {
+		return nil
+	}
+
+	// UpdateChat() leaves the summary alone, so the summary columns are written directly
+	chat.Summary = ""
+	chat.SummaryTime = ""
+	_, err := adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("summary", "summary_time").Update(chat)
+	return err
+}
+
+// DeleteFailedReplies deletes the failed and empty answers of the question, they are not kept as branches when
+// the answer is regenerated
+func DeleteFailedReplies(question *Message) error {
+	replies := []*Message{}
+	err := adapter.engine.Find(&replies, &Message{Owner: question.Owner, Chat: question.Chat, ReplyTo: question.Name, Author: "AI"})
+	if err != nil {
+		return err
+	}
+
+	for _, reply := range replies {
+		if reply.ErrorText == "" && reply.Text != "" {
+			continue
+		}
+
+		_, err = DeleteMessage(reply)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
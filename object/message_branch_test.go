@@ -0,0 +1,98 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+func getMessageNames(messages []*Message) []string {
+	names := []string{}
+	for _, message := range messages {
+		names = append(names, message.Name)
+	}
+	return names
+}
+
+func TestMessageTree(t *testing.T) {
+	// q1 and a1 were added before the branches, q2 is edited as q2b, and the answer of q2b is regenerated as a2c
+	messages := []*Message{
+		{Name: "q1", Author: "alice", CreatedTime: "2025-01-01T00:00:01Z"},
+		{Name: "a1", Author: "AI", ReplyTo: "q1", CreatedTime: "2025-01-01T00:00:02Z"},
+		{Name: "q2", Author: "alice", ParentMessage: "a1", CreatedTime: "2025-01-01T00:00:03Z"},
+		{Name: "a2", Author: "AI", ReplyTo: "q2", ParentMessage: "q2", CreatedTime: "2025-01-01T00:00:04Z"},
+		{Name: "q2b", Author: "alice", ParentMessage: "a1", CreatedTime: "2025-01-01T00:00:05Z"},
+		{Name: "a2b", Author: "AI", ReplyTo: "q2b", ParentMessage: "q2b", CreatedTime: "2025-01-01T00:00:06Z"},
+		{Name: "a2c", Author: "AI", ReplyTo: "q2b", ParentMessage: "q2b", CreatedTime: "2025-01-01T00:00:07Z"},
+		{Name: "q3", Author: "alice", ParentMessage: "a2b", CreatedTime: "2025-01-01T00:00:08Z"},
+	}
+	tree := newMessageTree(messages)
+
+	tests := []struct {
+		activeMessage string
+		want          []string
+	}{
+		{"", []string{"q1", "a1", "q2b", "a2b", "q3"}},
+		{"a2", []string{"q1", "a1", "q2", "a2"}},
+		{"q2b", []string{"q1", "a1", "q2b", "a2c"}},
+		{"deleted", []string{"q1", "a1", "q2b", "a2b", "q3"}},
+	}
+	for _, test := range tests {
+		branch := tree.getBranch(tree.getActiveLeaf(test.activeMessage))
+		if names := getMessageNames(branch); !reflect.DeepEqual(names, test.want) {
+			t.Errorf("getBranch() with the active message: %q = %v, want %v", test.activeMessage, names, test.want)
+		}
+	}
+
+	if names := getMessageNames(tree.getSiblings(tree.messageMap["q2"])); !reflect.DeepEqual(names, []string{"q2", "q2b"}) {
+		t.Errorf("getSiblings(q2) = %v", names)
+	}
+	if names := getMessageNames(tree.getSiblings(tree.messageMap["a2c"])); !reflect.DeepEqual(names, []string{"a2b", "a2c"}) {
+		t.Errorf("getSiblings(a2c) = %v", names)
+	}
+	if siblings := tree.getSiblings(tree.messageMap["a1"]); len(siblings) != 1 {
+		t.Errorf("getSiblings(a1) = %v", getMessageNames(siblings))
+	}
+}
+
+func TestGetRecentRawMessagesOfBranch(t *testing.T) {
+	branch := []*Message{
+		{Name: "q1", Author: "alice", Text: "q1", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:01Z"},
+		{Name: "a1", Author: "AI", Text: "a1", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:02Z"},
+		{Name: "q2b", Author: "alice", Text: "q2b", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:05Z"},
+		{Name: "a2b", Author: "AI", Text: "a2b", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:06Z"},
+		{Name: "q3", Author: "alice", Text: "q3", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:08Z"},
+		{Name: "a3", Author: "AI", Text: "", TextTokenCount: 1, CreatedTime: "2025-01-01T00:00:09Z"},
+	}
+
+	history, err := getRecentRawMessages(branch, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := []string{}
+	for _, message := range history {
+		texts = append(texts, message.Text)
+	}
+	if !reflect.DeepEqual(texts, []string{"a2b", "q2b"}) {
+		t.Errorf("getRecentRawMessages() = %v", texts)
+	}
+
+	chat := &Chat{SummaryTime: "2025-01-01T00:00:01Z"}
+	if names := getMessageNames(getUnsummarizedMessages(chat, branch, 1)); !reflect.DeepEqual(names, []string{"a1", "q2b", "a2b"}) {
+		t.Errorf("getUnsummarizedMessages() = %v", names)
+	}
+}
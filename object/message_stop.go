@@ -0,0 +1,53 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// The stop request of an answer is stored on the message, so that it reaches the answer whichever instance serves it
+
+func setMessageNeedStop(id string, needStop bool) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	affected, err := adapter.engine.ID(core.PK{owner, name}).Cols("need_stop").Update(&Message{NeedStop: needStop})
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
+// RequestMessageStop stores the stop request of the answer of the message, it returns false if there is no such message
+func RequestMessageStop(id string) (bool, error) {
+	return setMessageNeedStop(id, true)
+}
+
+// ResetMessageStop clears an old stop request before the answer of the message starts
+func ResetMessageStop(id string) error {
+	_, err := setMessageNeedStop(id, false)
+	return err
+}
+
+// GetMessageNeedStop returns whether the answer of the message is requested to stop
+func GetMessageNeedStop(id string) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	message := Message{}
+	existed, err := adapter.engine.ID(core.PK{owner, name}).Cols("need_stop").Get(&message)
+	if err != nil {
+		return false, err
+	}
+	return existed && message.NeedStop, nil
+}
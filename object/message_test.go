@@ -72,7 +72,7 @@ func TestUpdateMessagePrices(t *testing.T) {
 				question = questionMessage.Text
 			}
 
-			history, err := GetRecentRawMessages(message.Chat, message.CreatedTime, store.MemoryLimit)
+			history, err := GetRecentRawMessages(message, store.MemoryLimit)
 			if err != nil {
 				panic(err)
 			}
@@ -0,0 +1,190 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// Pricing is an entry of the pricing catalog, it sets the price of the providers of a type and sub type
+// from its effective time on, replacing the built-in prices of the providers
+type Pricing struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	DisplayName string `xorm:"varchar(100)" json:"displayName"`
+	Category    string `xorm:"varchar(100)" json:"category"`
+	Type        string `xorm:"varchar(100)" json:"type"`
+	SubType     string `xorm:"varchar(100)" json:"subType"`
+
+	InputPricePerThousandTokens       float64 `xorm:"DECIMAL(10, 6)" json:"inputPricePerThousandTokens"`
+	OutputPricePerThousandTokens      float64 `xorm:"DECIMAL(10, 6)" json:"outputPricePerThousandTokens"`
+	CachedInputPricePerThousandTokens float64 `xorm:"DECIMAL(10, 6)" json:"cachedInputPricePerThousandTokens"`
+	PricePerImage                     float64 `xorm:"DECIMAL(10, 6)" json:"pricePerImage"`
+	AudioPricePerThousandTokens       float64 `xorm:"DECIMAL(10, 6)" json:"audioPricePerThousandTokens"`
+	Currency                          string  `xorm:"varchar(100)" json:"currency"`
+	EffectiveTime                     string  `xorm:"varchar(100)" json:"effectiveTime"`
+}
+
+func GetGlobalPricings() ([]*Pricing, error) {
+	pricings := []*Pricing{}
+	err := adapter.engine.Asc("owner").Desc("created_time").Find(&pricings)
+	if err != nil {
+		return pricings, err
+	}
+
+	return pricings, nil
+}
+
+func GetPricings(owner string) ([]*Pricing, error) {
+	pricings := []*Pricing{}
+	err := adapter.engine.Desc("created_time").Find(&pricings, &Pricing{Owner: owner})
+	if err != nil {
+		return pricings, err
+	}
+
+	return pricings, nil
+}
+
+func getPricing(owner string, name string) (*Pricing, error) {
+	pricing := Pricing{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&pricing)
+	if err != nil {
+		return &pricing, err
+	}
+
+	if existed {
+		return &pricing, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetPricing(id string) (*Pricing, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	return getPricing(owner, name)
+}
+
+func UpdatePricing(id string, pricing *Pricing) (bool, error) {
+	owner, name := util.GetOwnerAndNameFromId(id)
+	p, err := getPricing(owner, name)
+	if err != nil {
+		return false, err
+	}
+	if p == nil {
+		return false, nil
+	}
+
+	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(pricing)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func AddPricing(pricing *Pricing) (bool, error) {
+	affected, err := adapter.engine.Insert(pricing)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeletePricing(pricing *Pricing) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{pricing.Owner, pricing.Name}).Delete(&Pricing{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (pricing *Pricing) GetId() string {
+	return fmt.Sprintf("%s/%s", pricing.Owner, pricing.Name)
+}
+
+func GetPricingCount(owner string, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&Pricing{})
+}
+
+func GetPaginationPricings(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*Pricing, error) {
+	pricings := []*Pricing{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&pricings)
+	if err != nil {
+		return pricings, err
+	}
+
+	return pricings, nil
+}
+
+// getEffectivePricing returns the catalog entry of the latest effective time that is not in the future, an entry for
+// the sub type wins over an entry with an empty sub type, which applies to all the sub types of the type
+func getEffectivePricing(pricings []*Pricing, category string, typ string, subType string, now time.Time) *Pricing {
+	var res *Pricing
+	var resTime time.Time
+	for _, pricing := range pricings {
+		if pricing.Category != category || pricing.Type != typ || (pricing.SubType != "" && pricing.SubType != subType) {
+			continue
+		}
+
+		effectiveTime := time.Time{}
+		if pricing.EffectiveTime != "" {
+			var err error
+			effectiveTime, err = time.Parse(time.RFC3339, pricing.EffectiveTime)
+			if err != nil {
+				continue
+			}
+		}
+		if effectiveTime.After(now) {
+			continue
+		}
+
+		if res == nil || (res.SubType == "" && pricing.SubType != "") || (res.SubType == pricing.SubType && effectiveTime.After(resTime)) {
+			res = pricing
+			resTime = effectiveTime
+		}
+	}
+	return res
+}
+
+func GetEffectivePricing(category string, typ string, subType string) (*Pricing, error) {
+	pricings := []*Pricing{}
+	err := adapter.engine.Find(&pricings, &Pricing{Owner: "admin", Category: category, Type: typ})
+	if err != nil {
+		return nil, err
+	}
+
+	return getEffectivePricing(pricings, category, typ, subType, time.Now()), nil
+}
+
+func (pricing *Pricing) GetModelPrice() *model.ModelPrice {
+	return &model.ModelPrice{
+		InputPricePerThousandTokens:       pricing.InputPricePerThousandTokens,
+		OutputPricePerThousandTokens:      pricing.OutputPricePerThousandTokens,
+		CachedInputPricePerThousandTokens: pricing.CachedInputPricePerThousandTokens,
+		PricePerImage:                     pricing.PricePerImage,
+		Currency:                          pricing.Currency,
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/tts"
+)
+
+func getCatalogPrice(tokenCount int, pricePerThousandTokens float64) float64 {
+	res := (float64(tokenCount) / 1000.0) * pricePerThousandTokens
+	return math.Round(res*1e8) / 1e8
+}
+
+// pricedEmbeddingProvider charges the input price of the pricing catalog for the embedded tokens
+type pricedEmbeddingProvider struct {
+	embedding.EmbeddingProvider
+	pricing *Pricing
+}
+
+func (p *pricedEmbeddingProvider) QueryVector(text string, ctx context.Context) ([]float32, *embedding.EmbeddingResult, error) {
+	vector, embeddingResult, err := p.EmbeddingProvider.QueryVector(text, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if embeddingResult != nil {
+		embeddingResult.Price = getCatalogPrice(embeddingResult.TokenCount, p.pricing.InputPricePerThousandTokens)
+		embeddingResult.Currency = p.pricing.Currency
+	}
+	return vector, embeddingResult, nil
+}
+
+// pricedTextToSpeechProvider charges the audio price of the pricing catalog for the synthesized tokens
+type pricedTextToSpeechProvider struct {
+	tts.TextToSpeechProvider
+	pricing *Pricing
+}
+
+func (p *pricedTextToSpeechProvider) updateResult(ttsResult *tts.TextToSpeechResult) {
+	if ttsResult != nil {
+		ttsResult.Price = getCatalogPrice(ttsResult.TokenCount, p.pricing.AudioPricePerThousandTokens)
+		ttsResult.Currency = p.pricing.Currency
+	}
+}
+
+func (p *pricedTextToSpeechProvider) QueryAudio(text string, ctx context.Context) ([]byte, *tts.TextToSpeechResult, error) {
+	audio, ttsResult, err := p.TextToSpeechProvider.QueryAudio(text, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.updateResult(ttsResult)
+	return audio, ttsResult, nil
+}
+
+func (p *pricedTextToSpeechProvider) QueryAudioStream(text string, ctx context.Context, writer io.Writer) (*tts.TextToSpeechResult, error) {
+	ttsResult, err := p.TextToSpeechProvider.QueryAudioStream(text, ctx, writer)
+	if err != nil {
+		return nil, err
+	}
+
+	p.updateResult(ttsResult)
+	return ttsResult, nil
+}
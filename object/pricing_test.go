@@ -0,0 +1,108 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetEffectivePricing(t *testing.T) {
+	pricings := []*Pricing{
+		{Name: "openai", Category: "Model", Type: "OpenAI", InputPricePerThousandTokens: 0.01},
+		{Name: "gpt-4o-old", Category: "Model", Type: "OpenAI", SubType: "gpt-4o", InputPricePerThousandTokens: 0.005, EffectiveTime: "2024-05-13T00:00:00Z"},
+		{Name: "gpt-4o", Category: "Model", Type: "OpenAI", SubType: "gpt-4o", InputPricePerThousandTokens: 0.0025, EffectiveTime: "2024-08-06T00:00:00Z"},
+		{Name: "gpt-4o-future", Category: "Model", Type: "OpenAI", SubType: "gpt-4o", InputPricePerThousandTokens: 0.001, EffectiveTime: "2099-01-01T00:00:00Z"},
+		{Name: "embedding", Category: "Embedding", Type: "OpenAI", InputPricePerThousandTokens: 0.0001},
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		category string
+		subType  string
+		want     string
+	}{
+		{"Model", "gpt-4o", "gpt-4o"},
+		{"Model", "gpt-4o-mini", "openai"},
+		{"Embedding", "text-embedding-3-small", "embedding"},
+		{"Agent", "gpt-4o", ""},
+	}
+	for _, test := range tests {
+		pricing := getEffectivePricing(pricings, test.category, "OpenAI", test.subType, now)
+		name := ""
+		if pricing != nil {
+			name = pricing.Name
+		}
+		if name != test.want {
+			t.Errorf("getEffectivePricing(%s, %s) = %q, want %q", test.category, test.subType, name, test.want)
+		}
+	}
+
+	pricing := getEffectivePricing(pricings, "Model", "OpenAI", "gpt-4o", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if pricing == nil || pricing.Name != "gpt-4o-old" {
+		t.Errorf("getEffectivePricing() before the new price = %v, want gpt-4o-old", pricing)
+	}
+}
+
+func TestCurrencyConverter(t *testing.T) {
+	converter := newCurrencyConverter([]*ExchangeRate{
+		{Currency: "USD", TargetCurrency: "CNY", Rate: 7.2},
+		{Currency: "EUR", TargetCurrency: "USD", Rate: 1.1},
+	})
+
+	tests := []struct {
+		price          float64
+		currency       string
+		targetCurrency string
+		want           float64
+		ok             bool
+	}{
+		{1, "USD", "CNY", 7.2, true},
+		{7.2, "CNY", "USD", 1, true},
+		{1, "EUR", "CNY", 7.92, true},
+		{1, "USD", "USD", 1, true},
+		{1, "USD", "JPY", 1, false},
+	}
+	for _, test := range tests {
+		res, ok := converter.Convert(test.price, test.currency, test.targetCurrency)
+		if ok != test.ok || res != test.want {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v, want %v, %v", test.price, test.currency, test.targetCurrency, res, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestAddDisplayPrice(t *testing.T) {
+	converter := newCurrencyConverter([]*ExchangeRate{})
+
+	// Without an exchange rate, the prices are totaled per currency rather than summed together
+	prices := map[string]float64{}
+	converter.addDisplayPrice(prices, 1.5, "USD")
+	converter.addDisplayPrice(prices, 2, "USD")
+	converter.addDisplayPrice(prices, 10, "CNY")
+	converter.addDisplayPrice(prices, 0, "")
+	if len(prices) != 2 || prices["USD"] != 3.5 || prices["CNY"] != 10 {
+		t.Errorf("addDisplayPrice() = %v, want 3.5 USD and 10 CNY", prices)
+	}
+
+	price, currency := getMainPrice(prices)
+	if price != 10 || currency != "CNY" {
+		t.Errorf("getMainPrice() = %v %s, want 10 CNY", price, currency)
+	}
+
+	price, currency = getMainPrice(map[string]float64{})
+	if price != 0 || currency != "" {
+		t.Errorf("getMainPrice() = %v %s for no prices, want 0", price, currency)
+	}
+}
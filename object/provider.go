@@ -47,6 +47,7 @@ type Provider struct {
 	ApiVersion         string            `xorm:"varchar(100)" json:"apiVersion"`
 	CompatibleProvider string            `xorm:"varchar(100)" json:"compatibleProvider"`
 	McpTools           []*agent.McpTools `xorm:"text" json:"mcpTools"`
+	ModelRoutes        []*ModelRoute     `xorm:"mediumtext" json:"modelRoutes"`
 	Text               string            `xorm:"mediumtext" json:"text"`
 	ConfigText         string            `xorm:"mediumtext" json:"configText"`
 
@@ -223,7 +224,7 @@ func UpdateProvider(id string, provider *Provider) (bool, error) {
 }
 
 func AddProvider(provider *Provider) (bool, error) {
-	if provider.ProviderKey == "" && provider.Category == "Model" {
+	if provider.ProviderKey == "" && (provider.Category == "Model" || provider.Category == "Embedding") {
 		provider.ProviderKey = generateProviderKey()
 	}
 
@@ -281,7 +282,7 @@ func GetDefaultKubernetesProvider() (*Provider, error) {
 }
 
 func (p *Provider) GetStorageProviderObj(vectorStoreId string) (storage.StorageProvider, error) {
-	pProvider, err := storage.GetStorageProvider(p.Type, p.ClientId, p.ClientSecret, p.Name, vectorStoreId)
+	pProvider, err := storage.GetStorageProvider(p.Type, p.ClientId, p.ClientSecret, p.ProviderUrl, p.Name, vectorStoreId)
 	if err != nil {
 		return nil, err
 	}
@@ -294,6 +295,10 @@ func (p *Provider) GetStorageProviderObj(vectorStoreId string) (storage.StorageP
 }
 
 func (p *Provider) GetModelProvider() (model.ModelProvider, error) {
+	if p.Type == "Router" {
+		return p.getRouterModelProvider()
+	}
+
 	pProvider, err := model.GetModelProvider(p.Type, p.SubType, p.ClientId, p.ClientSecret, p.UserKey, p.Temperature, p.TopP, p.TopK, p.FrequencyPenalty, p.PresencePenalty, p.ProviderUrl, p.ApiVersion, p.CompatibleProvider, p.InputPricePerThousandTokens, p.OutputPricePerThousandTokens, p.Currency, p.EnableThinking)
 	if err != nil {
 		return nil, err
@@ -303,6 +308,14 @@ func (p *Provider) GetModelProvider() (model.ModelProvider, error) {
 		return nil, fmt.Errorf("the model provider type: %s is not supported", p.Type)
 	}
 
+	pricing, err := GetEffectivePricing(p.Category, p.Type, p.SubType)
+	if err != nil {
+		return nil, err
+	}
+	if pricing != nil {
+		return model.NewPricedModelProvider(pProvider, pricing.GetModelPrice()), nil
+	}
+
 	return pProvider, nil
 }
 
@@ -316,11 +329,20 @@ func (p *Provider) GetEmbeddingProvider() (embedding.EmbeddingProvider, error) {
 		return nil, fmt.Errorf("the embedding provider type: %s is not supported", p.Type)
 	}
 
+	pricing, err := GetEffectivePricing(p.Category, p.Type, p.SubType)
+	if err != nil {
+		return nil, err
+	}
+	if pricing != nil {
+		return &pricedEmbeddingProvider{EmbeddingProvider: pProvider, pricing: pricing}, nil
+	}
+
 	return pProvider, nil
 }
 
-func (p *Provider) GetAgentProvider() (agent.AgentProvider, error) {
-	pProvider, err := agent.GetAgentProvider(p.Type, p.SubType, p.Text, p.McpTools)
+// GetAgentProvider returns the agent provider, its knowledge searcher only returns the vectors permitted to the user
+func (p *Provider) GetAgentProvider(user *casdoorsdk.User) (agent.AgentProvider, error) {
+	pProvider, err := agent.GetAgentProvider(p.Type, p.SubType, p.Text, p.McpTools, newStoreKnowledgeSearcher(user))
 	if err != nil {
 		return nil, err
 	}
@@ -342,6 +364,14 @@ func (p *Provider) GetTextToSpeechProvider() (tts.TextToSpeechProvider, error) {
 		return nil, fmt.Errorf("the TTS provider type: %s is not supported", p.Type)
 	}
 
+	pricing, err := GetEffectivePricing(p.Category, p.Type, p.SubType)
+	if err != nil {
+		return nil, err
+	}
+	if pricing != nil {
+		return &pricedTextToSpeechProvider{TextToSpeechProvider: pProvider, pricing: pricing}, nil
+	}
+
 	return pProvider, nil
 }
 
@@ -394,7 +424,7 @@ func GetEmbeddingProviderFromContext(owner string, name string) (*Provider, embe
 	return getEmbeddingProviderFromName(owner, providerName)
 }
 
-func GetAgentProviderFromContext(owner string, name string) (*Provider, agent.AgentProvider, error) {
+func GetAgentProviderFromContext(owner string, name string, user *casdoorsdk.User) (*Provider, agent.AgentProvider, error) {
 	var providerName string
 	if name != "" {
 		providerName = name
@@ -409,7 +439,7 @@ func GetAgentProviderFromContext(owner string, name string) (*Provider, agent.Ag
 		}
 	}
 
-	return getAgentProviderFromName(owner, providerName)
+	return getAgentProviderFromName(owner, providerName, user)
 }
 
 func GetAgentClients(agentProviderObj agent.AgentProvider) (*agent.AgentClients, error) {
@@ -494,11 +524,37 @@ func GetPaginationProviders(owner, storeName string, offset, limit int, field, v
 }
 
 func RefreshMcpTools(provider *Provider) error {
-	tools, err := agent.GetToolsList(provider.Text)
+	var tools []*agent.McpTools
+	var err error
+	if provider.Type == "Builtin" {
+		tools, err = agent.GetBuiltinToolsList(provider.Text)
+	} else if provider.Type == "OpenAPI" {
+		tools, err = agent.GetOpenApiToolsList(provider.Text)
+	} else {
+		tools, err = agent.GetToolsList(provider.Text)
+	}
 	if err != nil {
 		return err
 	}
 
+	// The servers keep their switches and tool policies, and the unhealthy ones keep their last known tools
+	previousToolsMap := map[string]*agent.McpTools{}
+	for _, previousTools := range provider.McpTools {
+		previousToolsMap[previousTools.ServerName] = previousTools
+	}
+	for _, mcpTools := range tools {
+		previousTools, ok := previousToolsMap[mcpTools.ServerName]
+		if !ok {
+			continue
+		}
+
+		mcpTools.IsEnabled = previousTools.IsEnabled
+		mcpTools.ToolPolicies = previousTools.ToolPolicies
+		if mcpTools.Status == agent.McpServerStatusUnhealthy {
+			mcpTools.Tools = previousTools.Tools
+		}
+	}
+
 	provider.McpTools = tools
 	return nil
 }
@@ -513,7 +569,7 @@ func (p *Provider) processProviderParams(providerDb *Provider) {
 	if p.SignKey == "***" {
 		p.SignKey = providerDb.SignKey
 	}
-	if p.ProviderKey == "" && p.Category == "Model" {
+	if p.ProviderKey == "" && (p.Category == "Model" || p.Category == "Embedding") {
 		p.ProviderKey = generateProviderKey()
 	}
 
@@ -16,8 +16,7 @@ package object
 
 import (
 	"fmt"
-
-	"github.com/casibase/casibase/model"
+	"strings"
 )
 
 // GetProviderByProviderKey retrieves a provider using the Provider key
@@ -49,8 +48,8 @@ func GetProviderByProviderKey(providerKey string) (*Provider, error) {
 	return nil, nil
 }
 
-// GetModelProviderByProviderKey retrieves both the provider and its model provider by API key
-func GetModelProviderByProviderKey(providerKey string) (model.ModelProvider, error) {
+// GetProviderByProviderKeyAndCategory retrieves a provider of the category using the Provider key
+func GetProviderByProviderKeyAndCategory(providerKey string, category string) (*Provider, error) {
 	provider, err := GetProviderByProviderKey(providerKey)
 	if err != nil {
 		return nil, err
@@ -60,20 +59,11 @@ func GetModelProviderByProviderKey(providerKey string) (model.ModelProvider, err
 		return nil, fmt.Errorf("The provider is not found")
 	}
 
-	// Ensure it's a model provider
-	if provider.Category != "Model" {
-		return nil, fmt.Errorf("The model provider: %s is not found", provider.Name)
-	}
-
-	modelProvider, err := provider.GetModelProvider()
-	if err != nil {
-		return nil, err
-	}
-	if modelProvider == nil {
-		return nil, fmt.Errorf("The model provider: %s is not found", provider.Name)
+	if provider.Category != category {
+		return nil, fmt.Errorf("The provider: %s is not a %s provider", provider.Name, strings.ToLower(category))
 	}
 
-	return modelProvider, nil
+	return provider, nil
 }
 
 func getFilteredProviders(providers []*Provider, needStorage bool) []*Provider {
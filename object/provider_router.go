@@ -0,0 +1,64 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+)
+
+type ModelRoute struct {
+	Provider string `json:"provider"`
+	Weight   int    `json:"weight"`
+}
+
+// getRouterModelProvider builds a "Router" model provider whose targets are the model providers listed in its routes
+func (p *Provider) getRouterModelProvider() (model.ModelProvider, error) {
+	targets := []*model.RouterTarget{}
+	for _, route := range p.ModelRoutes {
+		if route.Provider == "" {
+			continue
+		}
+
+		provider, err := GetProvider(util.GetIdFromOwnerAndName(p.Owner, route.Provider))
+		if err != nil {
+			return nil, err
+		}
+		if provider == nil {
+			return nil, fmt.Errorf("The model provider: %s of router: %s is not found", route.Provider, p.Name)
+		}
+		if provider.Category != "Model" {
+			return nil, fmt.Errorf("The provider: %s of router: %s is expected to be \"Model\" category, got: \"%s\"", route.Provider, p.Name, provider.Category)
+		}
+		if provider.Type == "Router" {
+			return nil, fmt.Errorf("The provider: %s of router: %s should not be a router itself", route.Provider, p.Name)
+		}
+
+		providerObj, err := provider.GetModelProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, &model.RouterTarget{
+			Name:     provider.Name,
+			Weight:   route.Weight,
+			Provider: providerObj,
+		})
+	}
+
+	return model.NewRouterModelProvider(p.Name, p.SubType, targets)
+}
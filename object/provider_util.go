@@ -17,6 +17,7 @@ package object
 import (
 	"fmt"
 
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/casibase/casibase/agent"
 	"github.com/casibase/casibase/embedding"
 	"github.com/casibase/casibase/model"
@@ -47,7 +48,7 @@ func getModelProviderFromName(owner string, providerName string) (*Provider, mod
 	if provider.Category != "Model" {
 		return nil, nil, fmt.Errorf("The model provider: %s is expected to be \"Model\" category, got: \"%s\"", provider.GetId(), provider.Category)
 	}
-	if provider.ClientSecret == "" && provider.Type != "Dummy" && provider.Type != "Ollama" {
+	if provider.ClientSecret == "" && provider.Type != "Dummy" && provider.Type != "Ollama" && provider.Type != "Router" {
 		return nil, nil, fmt.Errorf("The model provider: %s's client secret should not be empty", provider.GetId())
 	}
 
@@ -94,7 +95,7 @@ func getEmbeddingProviderFromName(owner string, providerName string) (*Provider,
 	return provider, providerObj, err
 }
 
-func getAgentProviderFromName(owner string, providerName string) (*Provider, agent.AgentProvider, error) {
+func getAgentProviderFromName(owner string, providerName string, user *casdoorsdk.User) (*Provider, agent.AgentProvider, error) {
 	var provider *Provider
 	var err error
 	if providerName != "" {
@@ -114,7 +115,7 @@ func getAgentProviderFromName(owner string, providerName string) (*Provider, age
 		return nil, nil, fmt.Errorf("The agent provider: %s is expected to be \"Agent\" category, got: \"%s\"", provider.GetId(), provider.Category)
 	}
 
-	providerObj, err := provider.GetAgentProvider()
+	providerObj, err := provider.GetAgentProvider(user)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -15,11 +15,12 @@
 package object
 
 import (
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/casibase/casibase/embedding"
 )
 
 type SearchProvider interface {
-	Search(storeName string, embeddingProviderName string, embeddingProviderObj embedding.EmbeddingProvider, modelProviderName string, text string, knowledgeCount int) ([]Vector, *embedding.EmbeddingResult, error)
+	Search(storeName string, embeddingProviderName string, embeddingProviderObj embedding.EmbeddingProvider, modelProviderName string, text string, knowledgeCount int, user *casdoorsdk.User) ([]Vector, *embedding.EmbeddingResult, error)
 }
 
 func GetSearchProvider(typ string, owner string) (SearchProvider, error) {
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/casibase/casibase/embedding"
 	"github.com/casibase/casibase/model"
 )
@@ -30,8 +31,8 @@ func NewHierarchySearchProvider(owner string) (*HierarchySearchProvider, error)
 	return &HierarchySearchProvider{owner: owner}, nil
 }
 
-func (p *HierarchySearchProvider) Search(storeName string, embeddingProviderName string, embeddingProviderObj embedding.EmbeddingProvider, modelProviderName string, text string, knowledgeCount int) ([]Vector, *embedding.EmbeddingResult, error) {
-	vectors, err := getRelatedVectors(storeName, embeddingProviderName)
+func (p *HierarchySearchProvider) Search(storeName string, embeddingProviderName string, embeddingProviderObj embedding.EmbeddingProvider, modelProviderName string, text string, knowledgeCount int, user *casdoorsdk.User) ([]Vector, *embedding.EmbeddingResult, error) {
+	vectors, err := getRelatedVectors(storeName, embeddingProviderName, user)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/embedding"
 	"github.com/casibase/casibase/storage"
 	"github.com/casibase/casibase/util"
 	"xorm.io/core"
@@ -52,6 +54,16 @@ type Prompt struct {
 	Image string `json:"image"`
 }
 
+// StoreCarrier is an admin-defined carrier: its instruction is appended to the questions of the store, and the
+// value extracted from the answer by its parse type and pattern is stored in the carrier values of the message
+type StoreCarrier struct {
+	Name        string `json:"name"`
+	Instruction string `json:"instruction"`
+	ParseType   string `json:"parseType"`
+	Pattern     string `json:"pattern"`
+	IsEnabled   bool   `json:"isEnabled"`
+}
+
 type Store struct {
 	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
 	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
@@ -71,29 +83,40 @@ type Store struct {
 	AgentProvider        string `xorm:"varchar(100)" json:"agentProvider"`
 	VectorStoreId        string `xorm:"varchar(100)" json:"vectorStoreId"`
 
-	MemoryLimit         int      `json:"memoryLimit"`
-	Frequency           int      `json:"frequency"`
-	LimitMinutes        int      `json:"limitMinutes"`
-	KnowledgeCount      int      `json:"knowledgeCount"`
-	SuggestionCount     int      `json:"suggestionCount"`
-	Welcome             string   `xorm:"varchar(100)" json:"welcome"`
-	WelcomeTitle        string   `xorm:"varchar(100)" json:"welcomeTitle"`
-	WelcomeText         string   `xorm:"varchar(100)" json:"welcomeText"`
-	Prompt              string   `xorm:"mediumtext" json:"prompt"`
-	Prompts             []Prompt `xorm:"mediumtext" json:"prompts"`
-	ThemeColor          string   `xorm:"varchar(100)" json:"themeColor"`
-	Avatar              string   `xorm:"varchar(200)" json:"avatar"`
-	Title               string   `xorm:"varchar(100)" json:"title"`
-	HtmlTitle           string   `xorm:"varchar(100)" json:"htmlTitle"`
-	FaviconUrl          string   `xorm:"varchar(200)" json:"faviconUrl"`
-	LogoUrl             string   `xorm:"varchar(200)" json:"logoUrl"`
-	FooterHtml          string   `xorm:"mediumtext" json:"footerHtml"`
-	ChildStores         []string `xorm:"varchar(500)" json:"childStores"`
-	ChildModelProviders []string `xorm:"varchar(500)" json:"childModelProviders"`
-	ShowAutoRead        bool     `json:"showAutoRead"`
-	DisableFileUpload   bool     `json:"disableFileUpload"`
-	IsDefault           bool     `json:"isDefault"`
-	State               string   `xorm:"varchar(100)" json:"state"`
+	MemoryLimit         int            `json:"memoryLimit"`
+	MemoryStrategy      string         `xorm:"varchar(100)" json:"memoryStrategy"`
+	MemoryProvider      string         `xorm:"varchar(100)" json:"memoryProvider"`
+	EnableMemory        bool           `json:"enableMemory"`
+	Guardrails          []Guardrail    `xorm:"mediumtext" json:"guardrails"`
+	Frequency           int            `json:"frequency"`
+	LimitMinutes        int            `json:"limitMinutes"`
+	KnowledgeCount      int            `json:"knowledgeCount"`
+	SuggestionCount     int            `json:"suggestionCount"`
+	Welcome             string         `xorm:"varchar(100)" json:"welcome"`
+	WelcomeTitle        string         `xorm:"varchar(100)" json:"welcomeTitle"`
+	WelcomeText         string         `xorm:"varchar(100)" json:"welcomeText"`
+	Prompt              string         `xorm:"mediumtext" json:"prompt"`
+	Prompts             []Prompt       `xorm:"mediumtext" json:"prompts"`
+	Carriers            []StoreCarrier `xorm:"mediumtext" json:"carriers"`
+	ThemeColor          string         `xorm:"varchar(100)" json:"themeColor"`
+	Avatar              string         `xorm:"varchar(200)" json:"avatar"`
+	Title               string         `xorm:"varchar(100)" json:"title"`
+	HtmlTitle           string         `xorm:"varchar(100)" json:"htmlTitle"`
+	FaviconUrl          string         `xorm:"varchar(200)" json:"faviconUrl"`
+	LogoUrl             string         `xorm:"varchar(200)" json:"logoUrl"`
+	FooterHtml          string         `xorm:"mediumtext" json:"footerHtml"`
+	ChildStores         []string       `xorm:"varchar(500)" json:"childStores"`
+	ChildModelProviders []string       `xorm:"varchar(500)" json:"childModelProviders"`
+	ShowAutoRead        bool           `json:"showAutoRead"`
+	DisableFileUpload   bool           `json:"disableFileUpload"`
+	IsDefault           bool           `json:"isDefault"`
+	State               string         `xorm:"varchar(100)" json:"state"`
+	ApiKey              string         `xorm:"varchar(100)" json:"apiKey"`
+
+	EnableWatch  bool     `json:"enableWatch"`
+	SyncTime     string   `xorm:"varchar(100)" json:"syncTime"`
+	SyncError    string   `xorm:"mediumtext" json:"syncError"`
+	PendingFiles []string `xorm:"-" json:"pendingFiles"`
 
 	ChatCount    int `xorm:"-" json:"chatCount"`
 	MessageCount int `xorm:"-" json:"messageCount"`
@@ -168,7 +191,7 @@ func GetStore(id string) (*Store, error) {
 
 func UpdateStore(id string, store *Store) (bool, error) {
 	owner, name := util.GetOwnerAndNameFromId(id)
-	_, err := getStore(owner, name)
+	storeDb, err := getStore(owner, name)
 	if err != nil {
 		return false, err
 	}
@@ -176,11 +199,21 @@ func UpdateStore(id string, store *Store) (bool, error) {
 		return false, nil
 	}
 
-	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(store)
+	if store.ApiKey == "***" && storeDb != nil {
+		store.ApiKey = storeDb.ApiKey
+	}
+
+	// The sync status is written by the watcher of the store, not by the edit page
+	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Omit("sync_time", "sync_error").Update(store)
 	if err != nil {
 		return false, err
 	}
 
+	if store.GetId() != id {
+		stopStoreWatcher(id)
+	}
+	refreshStoreWatcher(store)
+
 	// return affected != 0
 	return true, nil
 }
@@ -191,15 +224,58 @@ func AddStore(store *Store) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		refreshStoreWatcher(store)
+	}
+
 	return affected != 0, nil
 }
 
+// GetStoreByApiKey retrieves the store that the API key of the OpenAI-compatible API belongs to. A store without an
+// API key is not open to the API, its key is only set when the admin generates one on the store page
+func GetStoreByApiKey(apiKey string) (*Store, error) {
+	if apiKey == "" || apiKey == "***" {
+		return nil, nil
+	}
+
+	store := Store{}
+	existed, err := adapter.engine.Where("api_key = ? and api_key <> ?", apiKey, "").Get(&store)
+	if err != nil {
+		return nil, err
+	}
+
+	if existed {
+		return &store, nil
+	}
+	return nil, nil
+}
+
+func GetMaskedStore(store *Store, user *casdoorsdk.User) *Store {
+	if store == nil {
+		return nil
+	}
+
+	if !isAdmin(user) && store.ApiKey != "" {
+		store.ApiKey = "***"
+	}
+	return store
+}
+
+func GetMaskedStores(stores []*Store, user *casdoorsdk.User) []*Store {
+	for _, store := range stores {
+		GetMaskedStore(store, user)
+	}
+	return stores
+}
+
 func DeleteStore(store *Store) (bool, error) {
 	affected, err := adapter.engine.ID(core.PK{store.Owner, store.Name}).Delete(&Store{})
 	if err != nil {
 		return false, err
 	}
 
+	stopStoreWatcher(store.GetId())
+
 	return affected != 0, nil
 }
 
@@ -280,29 +356,38 @@ func (store *Store) GetEmbeddingProvider() (*Provider, error) {
 	return GetProvider(providerId)
 }
 
-func RefreshStoreVectors(store *Store) (bool, error) {
-	storageProviderObj, err := store.GetStorageProviderObj()
-	if err != nil {
-		return false, err
-	}
-
+func (store *Store) getEmbeddingProviders() (*Provider, *Provider, embedding.EmbeddingProvider, error) {
 	modelProvider, err := store.GetModelProvider()
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
 	if modelProvider == nil {
-		return false, fmt.Errorf("The model provider for store: %s is not found", store.GetId())
+		return nil, nil, nil, fmt.Errorf("The model provider for store: %s is not found", store.GetId())
 	}
 
 	embeddingProvider, err := store.GetEmbeddingProvider()
 	if err != nil {
-		return false, err
+		return nil, nil, nil, err
 	}
 	if embeddingProvider == nil {
-		return false, fmt.Errorf("The embedding provider for store: %s is not found", store.GetId())
+		return nil, nil, nil, fmt.Errorf("The embedding provider for store: %s is not found", store.GetId())
 	}
 
 	embeddingProviderObj, err := embeddingProvider.GetEmbeddingProvider()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return modelProvider, embeddingProvider, embeddingProviderObj, nil
+}
+
+func RefreshStoreVectors(store *Store) (bool, error) {
+	storageProviderObj, err := store.GetStorageProviderObj()
+	if err != nil {
+		return false, err
+	}
+
+	modelProvider, embeddingProvider, embeddingProviderObj, err := store.getEmbeddingProviders()
 	if err != nil {
 		return false, err
 	}
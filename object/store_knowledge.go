@@ -0,0 +1,104 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/model"
+)
+
+// SearchStoreKnowledge returns the knowledge of the store that is nearest to the query and readable by the user,
+// with the store's own embedding and model providers, nothing is returned if the store has no vectors yet
+func SearchStoreKnowledge(store *Store, query string, user *casdoorsdk.User) ([]*model.RawMessage, []VectorScore, error) {
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modelProvider, _, err := GetModelProviderFromContext("admin", store.ModelProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	knowledgeCount := store.KnowledgeCount
+	if knowledgeCount <= 0 {
+		knowledgeCount = 10
+	}
+
+	knowledge, vectorScores, _, err := GetNearestKnowledge(store.Name, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", query, knowledgeCount, user)
+	if err != nil {
+		if err.Error() == "no knowledge vectors found" {
+			return []*model.RawMessage{}, []VectorScore{}, nil
+		}
+		return nil, nil, err
+	}
+	return knowledge, vectorScores, nil
+}
+
+// GetStoreFiles returns the files of the store that have been vectorized and are readable by the user
+func GetStoreFiles(store *Store, user *casdoorsdk.User) ([]string, error) {
+	vectors := []*Vector{}
+	err := adapter.engine.Cols("file").Distinct("file").Where("owner = ? and store = ?", "admin", store.Name).Find(&vectors)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors, err = getPermittedVectors(store.Name, vectors, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []string{}
+	for _, vector := range vectors {
+		if vector.File != "" {
+			res = append(res, vector.File)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// GetStoreFileText returns the text of a file of the store, joined from its vectors in order, if the user can read it
+func GetStoreFileText(store *Store, file string, user *casdoorsdk.User) (string, error) {
+	vectors := []*Vector{}
+	err := adapter.engine.Where("owner = ? and store = ? and file = ?", "admin", store.Name, file).Find(&vectors)
+	if err != nil {
+		return "", err
+	}
+	sort.SliceStable(vectors, func(i, j int) bool {
+		return vectors[i].Index < vectors[j].Index
+	})
+	if len(vectors) == 0 {
+		return "", fmt.Errorf("the file: %s is not found in the store: %s", file, store.Name)
+	}
+
+	vectors, err = getPermittedVectors(store.Name, vectors, user)
+	if err != nil {
+		return "", err
+	}
+	if len(vectors) == 0 {
+		return "", fmt.Errorf("the file: %s of the store: %s is not permitted", file, store.Name)
+	}
+
+	texts := []string{}
+	for _, vector := range vectors {
+		texts = append(texts, vector.Text)
+	}
+	return strings.Join(texts, "\n"), nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/util"
+)
+
+// permissionsCacheTtl is how long the Casdoor permissions are cached, so that the knowledge searches don't query
+// Casdoor each time, while a changed permission still applies soon
+const permissionsCacheTtl = 30 * time.Second
+
+var (
+	cachedPermissions      []*casdoorsdk.Permission
+	cachedPermissionsTime  time.Time
+	cachedPermissionsMutex sync.Mutex
+)
+
+var (
+	getCasdoorPermissions   = casdoorsdk.GetPermissions
+	updateCasdoorPermission = casdoorsdk.UpdatePermission
+)
+
+// getCachedPermissions returns all the Casdoor permissions, they are fetched again once the cache is older than the TTL
+func getCachedPermissions() ([]*casdoorsdk.Permission, error) {
+	cachedPermissionsMutex.Lock()
+	defer cachedPermissionsMutex.Unlock()
+
+	if cachedPermissions != nil && time.Since(cachedPermissionsTime) < permissionsCacheTtl {
+		return cachedPermissions, nil
+	}
+
+	permissions, err := getCasdoorPermissions()
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []*casdoorsdk.Permission{}
+	}
+
+	cachedPermissions = permissions
+	cachedPermissionsTime = time.Now()
+	return permissions, nil
+}
+
+// isStorePermission checks whether the Casdoor permission is an ACL on the files of the store
+func isStorePermission(permission *casdoorsdk.Permission, storeName string) bool {
+	if permission.ResourceType != "" && permission.ResourceType != "TreeNode" {
+		return false
+	}
+	return util.InSlice(permission.Domains, storeName)
+}
+
+// getStorePermissions returns the approved and enabled Casdoor permissions whose domain is the store,
+// each of them is an ACL on the store files or folders listed in its resources
+func getStorePermissions(storeName string) ([]*casdoorsdk.Permission, error) {
+	permissions, err := getCachedPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*casdoorsdk.Permission{}
+	for _, permission := range permissions {
+		if !permission.IsEnabled || permission.State != "Approved" {
+			continue
+		}
+		if !isStorePermission(permission, storeName) {
+			continue
+		}
+
+		res = append(res, permission)
+	}
+	return res, nil
+}
+
+// isPermissionResourceMatched checks whether the resource is the file itself or one of its parent folders
+func isPermissionResourceMatched(resource string, key string) bool {
+	resource = strings.Trim(resource, "/")
+	key = strings.Trim(key, "/")
+	if resource == "" {
+		return true
+	}
+
+	return key == resource || strings.HasPrefix(key, resource+"/")
+}
+
+// updatePermissionsResource moves the resources of the store permissions along with the moved file or folder, so that
+// its ACLs still apply at the new key. The disabled and pending permissions are moved too, as they can be turned on later
+func updatePermissionsResource(storeName string, key string, newKey string) error {
+	permissions, err := getCasdoorPermissions()
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !isStorePermission(permission, storeName) {
+			continue
+		}
+
+		isChanged := false
+		for i, resource := range permission.Resources {
+			trimmedResource := strings.Trim(resource, "/")
+			if trimmedResource == "" || !isPermissionResourceMatched(key, trimmedResource) {
+				continue
+			}
+
+			permission.Resources[i] = newKey + strings.TrimPrefix(trimmedResource, key)
+			isChanged = true
+		}
+		if !isChanged {
+			continue
+		}
+
+		_, err = updateCasdoorPermission(permission)
+		if err != nil {
+			return fmt.Errorf("failed to move the resources of permission: %s, %s", util.GetIdFromOwnerAndName(permission.Owner, permission.Name), err.Error())
+		}
+	}
+
+	cachedPermissionsMutex.Lock()
+	cachedPermissions = nil
+	cachedPermissionsMutex.Unlock()
+	return nil
+}
+
+// isPermissionReadAction checks whether the permission is about reading files. Writing and administering imply reading
+// when they are allowed, but denying them leaves reading alone, so only a denied "read" counts for a "Deny" permission
+func isPermissionReadAction(permission *casdoorsdk.Permission) bool {
+	for _, action := range permission.Actions {
+		switch strings.ToLower(action) {
+		case "read":
+			return true
+		case "write", "admin":
+			if permission.Effect != "Deny" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPermissionUserMatched(permission *casdoorsdk.Permission, user *casdoorsdk.User) bool {
+	if user == nil {
+		return false
+	}
+
+	userId := util.GetIdFromOwnerAndName(user.Owner, user.Name)
+	for _, permissionUser := range permission.Users {
+		if permissionUser == "*" || permissionUser == userId || permissionUser == fmt.Sprintf("%s/*", user.Owner) {
+			return true
+		}
+	}
+
+	for _, group := range user.Groups {
+		if !strings.Contains(group, "/") {
+			group = util.GetIdFromOwnerAndName(user.Owner, group)
+		}
+		if util.InSlice(permission.Groups, group) {
+			return true
+		}
+	}
+
+	for _, role := range user.Roles {
+		if role == nil {
+			continue
+		}
+		if util.InSlice(permission.Roles, util.GetIdFromOwnerAndName(role.Owner, role.Name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isFileReadableByPermissions returns whether the user can read the file. A file is open to everyone unless
+// an "Allow" permission covers it, then only the users, groups and roles of those permissions can read it.
+// A matched "Deny" permission of reading always wins.
+func isFileReadableByPermissions(permissions []*casdoorsdk.Permission, user *casdoorsdk.User, key string) bool {
+	isRestricted := false
+	isAllowed := false
+	for _, permission := range permissions {
+		if !isPermissionReadAction(permission) {
+			continue
+		}
+
+		isCovered := false
+		for _, resource := range permission.Resources {
+			if isPermissionResourceMatched(resource, key) {
+				isCovered = true
+				break
+			}
+		}
+		if !isCovered {
+			continue
+		}
+
+		isMatched := isPermissionUserMatched(permission, user)
+		if permission.Effect == "Deny" {
+			if isMatched {
+				return false
+			}
+			continue
+		}
+
+		isRestricted = true
+		if isMatched {
+			isAllowed = true
+		}
+	}
+
+	return !isRestricted || isAllowed
+}
+
+func filterVectorsByPermissions(vectors []*Vector, permissions []*casdoorsdk.Permission, user *casdoorsdk.User) []*Vector {
+	if isAdmin(user) || len(permissions) == 0 {
+		return vectors
+	}
+
+	fileMap := map[string]bool{}
+	res := []*Vector{}
+	for _, vector := range vectors {
+		ok, exists := fileMap[vector.File]
+		if !exists {
+			ok = isFileReadableByPermissions(permissions, user, vector.File)
+			fileMap[vector.File] = ok
+		}
+
+		if ok {
+			res = append(res, vector)
+		}
+	}
+	return res
+}
+
+func getPermittedVectors(storeName string, vectors []*Vector, user *casdoorsdk.User) ([]*Vector, error) {
+	if isAdmin(user) {
+		return vectors, nil
+	}
+
+	permissions, err := getStorePermissions(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the permissions of store: %s, %s", storeName, err.Error())
+	}
+
+	return filterVectorsByPermissions(vectors, permissions, user), nil
+}
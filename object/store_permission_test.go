@@ -0,0 +1,148 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+)
+
+func TestFilterVectorsByPermissions(t *testing.T) {
+	permissions := []*casdoorsdk.Permission{
+		{Users: []string{"built-in/alice"}, Resources: []string{"hr"}, Actions: []string{"Read"}, Effect: "Allow"},
+		{Roles: []string{"built-in/finance"}, Resources: []string{"finance/report.pdf"}, Actions: []string{"Write"}, Effect: "Allow"},
+		{Groups: []string{"built-in/interns"}, Resources: []string{"general/secret.md"}, Actions: []string{"Read"}, Effect: "Deny"},
+		{Groups: []string{"built-in/interns"}, Resources: []string{"general/intro.md"}, Actions: []string{"Write", "Admin"}, Effect: "Deny"},
+	}
+
+	vectors := []*Vector{
+		{Name: "1", File: "general/intro.md"},
+		{Name: "2", File: "general/secret.md"},
+		{Name: "3", File: "hr/salary.xlsx"},
+		{Name: "4", File: "hr/2025/review.docx"},
+		{Name: "5", File: "finance/report.pdf"},
+		{Name: "6", File: "hrm/notes.txt"},
+	}
+
+	tests := []struct {
+		name     string
+		user     *casdoorsdk.User
+		expected []string
+	}{
+		{"anonymous", nil, []string{"1", "2", "6"}},
+		{"member of folder ACL", &casdoorsdk.User{Owner: "built-in", Name: "alice"}, []string{"1", "2", "3", "4", "6"}},
+		{"role on file ACL", &casdoorsdk.User{Owner: "built-in", Name: "bob", Roles: []*casdoorsdk.Role{{Owner: "built-in", Name: "finance"}}}, []string{"1", "2", "5", "6"}},
+		{"denied group", &casdoorsdk.User{Owner: "built-in", Name: "carol", Groups: []string{"interns"}}, []string{"1", "6"}},
+		{"admin", &casdoorsdk.User{Owner: "built-in", Name: "admin", IsAdmin: true}, []string{"1", "2", "3", "4", "5", "6"}},
+	}
+
+	for _, test := range tests {
+		res := filterVectorsByPermissions(vectors, permissions, test.user)
+
+		names := []string{}
+		for _, vector := range res {
+			names = append(names, vector.Name)
+		}
+		if len(names) != len(test.expected) {
+			t.Fatalf("%s: filterVectorsByPermissions() = %v, want %v", test.name, names, test.expected)
+		}
+		for i := range names {
+			if names[i] != test.expected[i] {
+				t.Fatalf("%s: filterVectorsByPermissions() = %v, want %v", test.name, names, test.expected)
+			}
+		}
+	}
+}
+
+func TestGetStorePermissionsCache(t *testing.T) {
+	count := 0
+	getCasdoorPermissions = func() ([]*casdoorsdk.Permission, error) {
+		count += 1
+		return []*casdoorsdk.Permission{
+			{IsEnabled: true, State: "Approved", Domains: []string{"store"}, Resources: []string{"hr"}},
+			{IsEnabled: true, State: "Approved", Domains: []string{"other-store"}, Resources: []string{"hr"}},
+		}, nil
+	}
+	defer func() {
+		getCasdoorPermissions = casdoorsdk.GetPermissions
+		cachedPermissions = nil
+	}()
+	cachedPermissions = nil
+
+	for i := 0; i < 3; i++ {
+		permissions, err := getStorePermissions("store")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(permissions) != 1 {
+			t.Fatalf("getStorePermissions() = %d permissions, want 1", len(permissions))
+		}
+	}
+	if count != 1 {
+		t.Fatalf("the permissions are fetched %d times, want 1", count)
+	}
+
+	cachedPermissionsTime = time.Now().Add(-permissionsCacheTtl)
+	_, err := getStorePermissions("store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("the expired permissions are fetched %d times, want 2", count)
+	}
+}
+
+func TestUpdatePermissionsResource(t *testing.T) {
+	permissions := []*casdoorsdk.Permission{
+		{Name: "p1", Domains: []string{"store"}, Resources: []string{"docs", "docs/a.md", "/docs/sub/b.md", "docsx/c.md"}},
+		{Name: "p2", Domains: []string{"store"}, Resources: []string{"other/d.md", "/"}},
+		{Name: "p3", Domains: []string{"other-store"}, Resources: []string{"docs/a.md"}},
+	}
+	getCasdoorPermissions = func() ([]*casdoorsdk.Permission, error) {
+		return permissions, nil
+	}
+	updated := []string{}
+	updateCasdoorPermission = func(permission *casdoorsdk.Permission) (bool, error) {
+		updated = append(updated, permission.Name)
+		return true, nil
+	}
+	defer func() {
+		getCasdoorPermissions = casdoorsdk.GetPermissions
+		updateCasdoorPermission = casdoorsdk.UpdatePermission
+		cachedPermissions = nil
+	}()
+	cachedPermissions = []*casdoorsdk.Permission{}
+
+	err := updatePermissionsResource("store", "docs", "archive/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(updated) != "[p1]" {
+		t.Fatalf("updated permissions = %v, want [p1]", updated)
+	}
+	if fmt.Sprint(permissions[0].Resources) != "[archive/docs archive/docs/a.md archive/docs/sub/b.md docsx/c.md]" {
+		t.Fatalf("resources of p1 = %v, want the docs folder moved to archive/docs", permissions[0].Resources)
+	}
+	if fmt.Sprint(permissions[2].Resources) != "[docs/a.md]" {
+		t.Fatalf("resources of p3 = %v, want the permission of another store unchanged", permissions[2].Resources)
+	}
+	if cachedPermissions != nil {
+		t.Fatal("the cached permissions should be reset after moving the resources")
+	}
+}
@@ -16,6 +16,7 @@ package object
 
 import (
 	"bytes"
+	"io"
 	"strings"
 
 	"github.com/casibase/casibase/storage"
@@ -66,6 +67,32 @@ func (w *SubpathStorageProvider) DeleteObject(key string) error {
 	return w.provider.DeleteObject(fullKey)
 }
 
+func (w *SubpathStorageProvider) GetObject(key string) (io.ReadCloser, error) {
+	fullKey := w.buildFullPath(key)
+	return w.provider.GetObject(fullKey)
+}
+
+func (w *SubpathStorageProvider) StatObject(key string) (*storage.Object, error) {
+	fullKey := w.buildFullPath(key)
+	obj, err := w.provider.StatObject(fullKey)
+	if err != nil || obj == nil {
+		return obj, err
+	}
+
+	if w.subpath != "" && strings.HasPrefix(obj.Key, w.subpath+"/") {
+		obj.Key = strings.TrimPrefix(obj.Key, w.subpath+"/")
+	}
+	return obj, nil
+}
+
+func (w *SubpathStorageProvider) CopyObject(srcKey string, dstKey string) error {
+	return w.provider.CopyObject(w.buildFullPath(srcKey), w.buildFullPath(dstKey))
+}
+
+func (w *SubpathStorageProvider) MoveObject(srcKey string, dstKey string) error {
+	return w.provider.MoveObject(w.buildFullPath(srcKey), w.buildFullPath(dstKey))
+}
+
 // Constructs the full path by combining subpath and path
 func (w *SubpathStorageProvider) buildFullPath(path string) string {
 	if w.subpath == "" {
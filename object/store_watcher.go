@@ -0,0 +1,435 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/storage"
+	"github.com/casibase/casibase/util"
+	"github.com/fsnotify/fsnotify"
+	"xorm.io/core"
+)
+
+const (
+	storeWatcherDebounce      = 3 * time.Second
+	storeWatcherRetryInterval = time.Minute
+)
+
+type storeWatcher struct {
+	storeId string
+	path    string
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+
+	debounce         time.Duration
+	retryInterval    time.Duration
+	syncFiles        func(storeId string, keys []string) ([]string, error)
+	updateSyncStatus func(storeId string, syncTime string, syncError string)
+
+	mutex   sync.Mutex
+	pending map[string]bool
+	timer   *time.Timer
+	syncing bool
+	stopped bool
+}
+
+var (
+	storeWatchers      = map[string]*storeWatcher{}
+	storeWatchersMutex sync.Mutex
+)
+
+func InitStoreWatchers() {
+	stores, err := GetGlobalStores()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, store := range stores {
+		refreshStoreWatcher(store)
+	}
+}
+
+// getLocalStoragePath returns the folder of the store if it is backed by the "Local File System" storage provider
+func (store *Store) getLocalStoragePath() (string, error) {
+	var provider *Provider
+	var err error
+	if store.StorageProvider == "" {
+		provider, err = GetDefaultStorageProvider()
+	} else {
+		provider, err = GetProvider(util.GetIdFromOwnerAndName(store.Owner, store.StorageProvider))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if provider == nil || provider.Type != "Local File System" {
+		return "", nil
+	}
+
+	path := filepath.Join(provider.ClientId, strings.Trim(store.StorageSubpath, "/"))
+	return filepath.Clean(path), nil
+}
+
+func refreshStoreWatcher(store *Store) {
+	storeId := store.GetId()
+	if !store.EnableWatch || store.State == "Inactive" {
+		stopStoreWatcher(storeId)
+		return
+	}
+
+	path, err := store.getLocalStoragePath()
+	if err != nil || path == "" {
+		stopStoreWatcher(storeId)
+		if err != nil {
+			fmt.Printf("refreshStoreWatcher() error: %s\n", err.Error())
+		}
+		return
+	}
+
+	storeWatchersMutex.Lock()
+	w, ok := storeWatchers[storeId]
+	storeWatchersMutex.Unlock()
+	if ok && w.path == path {
+		return
+	}
+
+	stopStoreWatcher(storeId)
+
+	w, err = newStoreWatcher(storeId, path)
+	if err != nil {
+		updateStoreSyncStatus(storeId, "", err.Error())
+		fmt.Printf("refreshStoreWatcher() error: %s\n", err.Error())
+		return
+	}
+
+	storeWatchersMutex.Lock()
+	storeWatchers[storeId] = w
+	storeWatchersMutex.Unlock()
+}
+
+func stopStoreWatcher(storeId string) {
+	storeWatchersMutex.Lock()
+	w, ok := storeWatchers[storeId]
+	delete(storeWatchers, storeId)
+	storeWatchersMutex.Unlock()
+
+	if ok {
+		w.stop()
+	}
+}
+
+func newStoreWatcher(storeId string, path string) (*storeWatcher, error) {
+	w := &storeWatcher{
+		storeId:          storeId,
+		path:             path,
+		done:             make(chan struct{}),
+		debounce:         storeWatcherDebounce,
+		retryInterval:    storeWatcherRetryInterval,
+		syncFiles:        syncStoreFiles,
+		updateSyncStatus: updateStoreSyncStatus,
+		pending:          map[string]bool{},
+	}
+
+	err := w.start()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *storeWatcher) start() error {
+	util.EnsureFolderExists(w.path)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	w.watcher = watcher
+	err = w.addFolder(w.path)
+	if err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go w.run()
+	return nil
+}
+
+func isIgnoredWatchFolder(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "node_modules"
+}
+
+// addFolder watches the folder and all its subfolders, fsnotify is not recursive by itself
+func (w *storeWatcher) addFolder(folder string) error {
+	return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != folder && isIgnoredWatchFolder(info.Name()) {
+			return filepath.SkipDir
+		}
+
+		return w.watcher.Add(path)
+	})
+}
+
+func (w *storeWatcher) stop() {
+	close(w.done)
+	w.watcher.Close()
+
+	w.mutex.Lock()
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mutex.Unlock()
+}
+
+func (w *storeWatcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.updateSyncStatus(w.storeId, "", err.Error())
+		}
+	}
+}
+
+func (w *storeWatcher) getKey(path string) string {
+	key, err := filepath.Rel(w.path, path)
+	if err != nil {
+		return ""
+	}
+
+	key = filepath.ToSlash(key)
+	if key == "." || strings.HasPrefix(key, "../") {
+		return ""
+	}
+	return key
+}
+
+func (w *storeWatcher) handleEvent(event fsnotify.Event) {
+	key := w.getKey(event.Name)
+	if key == "" {
+		return
+	}
+
+	for _, token := range strings.Split(key, "/") {
+		if isIgnoredWatchFolder(token) {
+			return
+		}
+	}
+
+	if event.Has(fsnotify.Create) {
+		info, err := os.Stat(event.Name)
+		if err == nil && info.IsDir() {
+			err = w.addFolder(event.Name)
+			if err != nil {
+				w.updateSyncStatus(w.storeId, "", err.Error())
+			}
+		}
+	}
+
+	if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending[key] = true
+	w.schedule(w.debounce)
+}
+
+// schedule runs the sync after the delay, replacing the one scheduled before, the caller holds the mutex
+func (w *storeWatcher) schedule(delay time.Duration) {
+	if w.stopped {
+		return
+	}
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(delay, w.sync)
+}
+
+func (w *storeWatcher) getPendingFiles() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	res := []string{}
+	for key := range w.pending {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (w *storeWatcher) sync() {
+	w.mutex.Lock()
+	if w.syncing {
+		// Another sync is running, try again after it finishes
+		w.schedule(w.debounce)
+		w.mutex.Unlock()
+		return
+	}
+	w.syncing = true
+	keys := []string{}
+	for key := range w.pending {
+		keys = append(keys, key)
+	}
+	w.pending = map[string]bool{}
+	w.mutex.Unlock()
+
+	sort.Strings(keys)
+	failedKeys, err := w.syncFiles(w.storeId, keys)
+
+	w.mutex.Lock()
+	w.syncing = false
+	if len(failedKeys) > 0 {
+		// Keep the failed files pending and retry them later, the changes made during the sync have scheduled an
+		// earlier one already
+		hasChanges := len(w.pending) > 0
+		for _, key := range failedKeys {
+			w.pending[key] = true
+		}
+		if !hasChanges {
+			w.schedule(w.retryInterval)
+		}
+	}
+	w.mutex.Unlock()
+
+	syncError := ""
+	if err != nil {
+		syncError = err.Error()
+		fmt.Printf("syncStoreFiles() error: %s\n", syncError)
+	}
+	w.updateSyncStatus(w.storeId, util.GetCurrentTime(), syncError)
+}
+
+// syncStoreFiles embeds the new or changed files and drops the vectors of the deleted files, it returns the keys that
+// failed to sync along with the first error
+func syncStoreFiles(storeId string, keys []string) ([]string, error) {
+	store, err := GetStore(storeId)
+	if err != nil {
+		return keys, err
+	}
+	if store == nil {
+		return keys, fmt.Errorf("The store: %s is not found", storeId)
+	}
+
+	storageProviderObj, err := store.GetStorageProviderObj()
+	if err != nil {
+		return keys, err
+	}
+
+	modelProvider, embeddingProvider, embeddingProviderObj, err := store.getEmbeddingProviders()
+	if err != nil {
+		return keys, err
+	}
+
+	failedKeys := []string{}
+	var firstErr error
+	for _, key := range keys {
+		err = syncStoreFile(store, storageProviderObj, embeddingProviderObj, embeddingProvider.Name, modelProvider.SubType, key)
+		if err != nil {
+			failedKeys = append(failedKeys, key)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return failedKeys, firstErr
+}
+
+func syncStoreFile(store *Store, storageProviderObj storage.StorageProvider, embeddingProviderObj embedding.EmbeddingProvider, embeddingProviderName string, modelSubType string, key string) error {
+	object, err := storageProviderObj.StatObject(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = deleteVectorsByFile(store.Name, key)
+	if err != nil {
+		return err
+	}
+
+	if object == nil {
+		_, err = deleteVectorsByFolder(store.Name, key)
+		return err
+	}
+
+	files := filterTextFiles([]*storage.Object{object})
+	if len(files) == 0 {
+		// A folder moved into the store only fires a single event, so embed everything under it
+		_, err = addVectorsForStore(storageProviderObj, embeddingProviderObj, key, store.Name, store.SplitProvider, embeddingProviderName, modelSubType)
+	} else {
+		var simHashIndex *vectorSimHashIndex
+		simHashIndex, err = getVectorSimHashIndex(store.Name, embeddingProviderName)
+		if err != nil {
+			return err
+		}
+
+		_, err = addVectorsForFile(storageProviderObj, embeddingProviderObj, object, store.Name, store.SplitProvider, embeddingProviderName, modelSubType, simHashIndex)
+	}
+	return err
+}
+
+func updateStoreSyncStatus(storeId string, syncTime string, syncError string) {
+	owner, name := util.GetOwnerAndNameFromId(storeId)
+	store := &Store{SyncError: syncError}
+	cols := []string{"sync_error"}
+	if syncTime != "" {
+		store.SyncTime = syncTime
+		cols = append(cols, "sync_time")
+	}
+
+	_, err := adapter.engine.ID(core.PK{owner, name}).Cols(cols...).Update(store)
+	if err != nil {
+		fmt.Printf("updateStoreSyncStatus() error: %s\n", err.Error())
+	}
+}
+
+func PopulateStoreWatchStatus(store *Store) {
+	storeWatchersMutex.Lock()
+	w, ok := storeWatchers[store.GetId()]
+	storeWatchersMutex.Unlock()
+
+	store.PendingFiles = []string{}
+	if ok {
+		store.PendingFiles = w.getPendingFiles()
+	}
+}
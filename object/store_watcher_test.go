@@ -0,0 +1,157 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testStoreSync struct {
+	mutex      sync.Mutex
+	calls      chan []string
+	failedKeys map[string]bool
+	syncErrors []string
+}
+
+func newTestStoreWatcher(t *testing.T, s *testStoreSync) *storeWatcher {
+	w := &storeWatcher{
+		storeId:       "admin/store",
+		path:          t.TempDir(),
+		done:          make(chan struct{}),
+		debounce:      50 * time.Millisecond,
+		retryInterval: 100 * time.Millisecond,
+		syncFiles: func(storeId string, keys []string) ([]string, error) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			failedKeys := []string{}
+			for _, key := range keys {
+				if s.failedKeys[key] {
+					failedKeys = append(failedKeys, key)
+				}
+			}
+			s.calls <- keys
+			if len(failedKeys) > 0 {
+				return failedKeys, fmt.Errorf("failed to sync: %v", failedKeys)
+			}
+			return nil, nil
+		},
+		updateSyncStatus: func(storeId string, syncTime string, syncError string) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			s.syncErrors = append(s.syncErrors, syncError)
+		},
+		pending: map[string]bool{},
+	}
+
+	err := w.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(w.stop)
+	return w
+}
+
+func waitStoreSync(t *testing.T, s *testStoreSync) []string {
+	select {
+	case keys := <-s.calls:
+		return keys
+	case <-time.After(5 * time.Second):
+		t.Fatal("the store is not synced")
+		return nil
+	}
+}
+
+func TestStoreWatcherDebounce(t *testing.T) {
+	s := &testStoreSync{calls: make(chan []string, 10)}
+	w := newTestStoreWatcher(t, s)
+
+	// The changes in a row are synced together once they settle
+	for _, name := range []string{"a.txt", "b.txt", "a.txt"} {
+		err := os.WriteFile(filepath.Join(w.path, name), []byte(name), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.Mkdir(filepath.Join(w.path, ".git"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := waitStoreSync(t, s)
+	if want := []string{"a.txt", "b.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("synced keys = %v, want %v", keys, want)
+	}
+	if pendingFiles := w.getPendingFiles(); len(pendingFiles) != 0 {
+		t.Fatalf("pending files = %v, want none after the sync", pendingFiles)
+	}
+
+	select {
+	case keys = <-s.calls:
+		t.Fatalf("the store is synced again with %v", keys)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestStoreWatcherRetry(t *testing.T) {
+	s := &testStoreSync{calls: make(chan []string, 10), failedKeys: map[string]bool{"b.txt": true}}
+	w := newTestStoreWatcher(t, s)
+
+	for _, name := range []string{"a.txt", "b.txt"} {
+		err := os.WriteFile(filepath.Join(w.path, name), []byte(name), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys := waitStoreSync(t, s)
+	if want := []string{"a.txt", "b.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("synced keys = %v, want %v", keys, want)
+	}
+
+	// Only the failed file is retried, without any new change
+	keys = waitStoreSync(t, s)
+	if want := []string{"b.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("retried keys = %v, want %v", keys, want)
+	}
+
+	s.mutex.Lock()
+	s.failedKeys = map[string]bool{}
+	s.mutex.Unlock()
+
+	keys = waitStoreSync(t, s)
+	if want := []string{"b.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("retried keys = %v, want %v", keys, want)
+	}
+
+	select {
+	case keys = <-s.calls:
+		t.Fatalf("the store is synced again with %v", keys)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.syncErrors) != 3 || s.syncErrors[0] == "" || s.syncErrors[2] != "" {
+		t.Fatalf("sync errors = %q, want an error cleared by the last sync", s.syncErrors)
+	}
+}
@@ -140,13 +140,6 @@ func PrepareTextToSpeech(storeId, providerId, messageId, text string) (*Message,
 }
 
 func UpdateChatStats(chat *Chat, ttsResult *tts.TextToSpeechResult) error {
-	chat.TokenCount += ttsResult.TokenCount
-	chat.Price += ttsResult.Price
-	if chat.Currency == "" {
-		chat.Currency = ttsResult.Currency
-	}
-
-	chat.UpdatedTime = util.GetCurrentTime()
-	_, err := UpdateChat(chat.GetId(), chat)
+	_, err := AddChatCost(chat, ttsResult.TokenCount, ttsResult.Price, ttsResult.Currency)
 	return err
 }
@@ -23,14 +23,17 @@ import (
 	"github.com/casibase/casibase/model"
 )
 
+// Usage is the usage of a period, the price is the total of its currency, and the prices are the totals of all the
+// currencies, which differ from the price only for the prices without an exchange rate to the display currency
 type Usage struct {
-	Date         string  `json:"date"`
-	UserCount    int     `json:"userCount"`
-	ChatCount    int     `json:"chatCount"`
-	MessageCount int     `json:"messageCount"`
-	TokenCount   int     `json:"tokenCount"`
-	Price        float64 `json:"price"`
-	Currency     string  `json:"currency"`
+	Date         string             `json:"date"`
+	UserCount    int                `json:"userCount"`
+	ChatCount    int                `json:"chatCount"`
+	MessageCount int                `json:"messageCount"`
+	TokenCount   int                `json:"tokenCount"`
+	Price        float64            `json:"price"`
+	Currency     string             `json:"currency"`
+	Prices       map[string]float64 `json:"prices"`
 }
 
 type UsageMetadata struct {
@@ -39,11 +42,27 @@ type UsageMetadata struct {
 }
 
 type UserUsage struct {
-	User         string  `json:"user"`
-	Chats        int     `json:"chats"`
-	MessageCount int     `json:"messageCount"`
-	TokenCount   int     `json:"tokenCount"`
-	Price        float64 `json:"price"`
+	User         string             `json:"user"`
+	Chats        int                `json:"chats"`
+	MessageCount int                `json:"messageCount"`
+	TokenCount   int                `json:"tokenCount"`
+	Price        float64            `json:"price"`
+	Currency     string             `json:"currency"`
+	Prices       map[string]float64 `json:"prices"`
+}
+
+func (usage *Usage) refinePrices() {
+	usage.Price, usage.Currency = getMainPrice(usage.Prices)
+	usage.Price = model.RefinePrice(usage.Price)
+	usage.Prices = refinePrices(usage.Prices)
+}
+
+func copyPrices(prices map[string]float64) map[string]float64 {
+	res := map[string]float64{}
+	for currency, price := range prices {
+		res[currency] = price
+	}
+	return res
 }
 
 func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
@@ -52,6 +71,11 @@ func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
 		return nil, err
 	}
 
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now().UTC()
 	// Adjusted to include today in the count by subtracting days-1
 	startDateTime := now.AddDate(0, 0, -(days - 1)).Truncate(24 * time.Hour)
@@ -63,7 +87,8 @@ func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
 
 	for i := 0; i < days; i++ {
 		usages[i] = &Usage{
-			Date: startDateTime.AddDate(0, 0, i).Format("2006-01-02"),
+			Date:   startDateTime.AddDate(0, 0, i).Format("2006-01-02"),
+			Prices: map[string]float64{},
 		}
 	}
 
@@ -90,8 +115,7 @@ func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
 					currentUsage.ChatCount = previousUsage.ChatCount
 					currentUsage.MessageCount = previousUsage.MessageCount
 					currentUsage.TokenCount = previousUsage.TokenCount
-					currentUsage.Price = previousUsage.Price
-					currentUsage.Currency = previousUsage.Currency
+					currentUsage.Prices = copyPrices(previousUsage.Prices)
 				}
 			}
 		}
@@ -104,8 +128,7 @@ func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
 			currentUsage.ChatCount = len(chatSet)
 			currentUsage.MessageCount++
 			currentUsage.TokenCount += message.TokenCount
-			currentUsage.Price += message.Price
-			currentUsage.Currency = message.Currency
+			converter.addDisplayPrice(currentUsage.Prices, message.Price, message.Currency)
 		}
 	}
 
@@ -118,12 +141,11 @@ func GetUsages(days int, user string, storeName string) ([]*Usage, error) {
 		currentUsage.ChatCount = previousUsage.ChatCount
 		currentUsage.MessageCount = previousUsage.MessageCount
 		currentUsage.TokenCount = previousUsage.TokenCount
-		currentUsage.Price = previousUsage.Price
-		currentUsage.Currency = previousUsage.Currency
+		currentUsage.Prices = copyPrices(previousUsage.Prices)
 	}
 
 	for _, usage := range usages {
-		usage.Price = model.RefinePrice(usage.Price)
+		usage.refinePrices()
 	}
 
 	return usages, nil
@@ -135,10 +157,15 @@ func GetUsage(date string) (*Usage, error) {
 		return nil, err
 	}
 
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return nil, err
+	}
+
 	userSet := make(map[string]int)
 	chatSet := make(map[string]int)
 	var messageCount, tokenCount int
-	price := 0.0
+	prices := map[string]float64{}
 
 	var dateTime time.Time
 	if date != "" {
@@ -166,25 +193,21 @@ func GetUsage(date string) (*Usage, error) {
 			}
 			messageCount++
 			tokenCount += message.TokenCount
-			price += message.Price
+			converter.addDisplayPrice(prices, message.Price, message.Currency)
 		}
 	}
 
-	currency := "USD"
-	if len(messages) > 0 && messages[0].Currency != "" {
-		currency = messages[0].Currency
-	}
-
-	price = model.RefinePrice(price)
-
 	usage := &Usage{
 		Date:         date,
 		UserCount:    len(userSet),
 		ChatCount:    len(chatSet),
 		MessageCount: messageCount,
 		TokenCount:   tokenCount,
-		Price:        price,
-		Currency:     currency,
+		Prices:       prices,
+	}
+	usage.refinePrices()
+	if usage.Currency == "" {
+		usage.Currency = "USD"
 	}
 	return usage, nil
 }
@@ -239,6 +262,11 @@ func GetUserTableInfos(storeName, user string) ([]*UserUsage, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return nil, err
+	}
 	userUsage := make(map[string]*UserUsage)
 	userChats := make(map[string]map[string]bool)
 
@@ -252,18 +280,20 @@ func GetUserTableInfos(storeName, user string) ([]*UserUsage, error) {
 				User:         message.User,
 				MessageCount: 0,
 				TokenCount:   0,
-				Price:        0,
+				Prices:       map[string]float64{},
 			}
 		}
 		userUsage[message.User].MessageCount++
 		userUsage[message.User].TokenCount += message.TokenCount
-		userUsage[message.User].Price += message.Price
+		converter.addDisplayPrice(userUsage[message.User].Prices, message.Price, message.Currency)
 	}
 
 	userUsageSlice := make([]*UserUsage, len(userUsage))
 	i := 0
 	for _, user := range userUsage {
+		user.Price, user.Currency = getMainPrice(user.Prices)
 		user.Price = model.RefinePrice(user.Price)
+		user.Prices = refinePrices(user.Prices)
 		user.Chats = len(userChats[user.User])
 		userUsageSlice[i] = user
 		i++
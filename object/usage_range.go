@@ -17,8 +17,6 @@ package object
 import (
 	"fmt"
 	"time"
-
-	"github.com/casibase/casibase/model"
 )
 
 func GetRangeUsages(rangeType string, count int, user string, storeName string) ([]*Usage, error) {
@@ -27,6 +25,11 @@ func GetRangeUsages(rangeType string, count int, user string, storeName string)
 		return nil, err
 	}
 
+	converter, err := NewCurrencyConverter()
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	var startDateTime time.Time
@@ -51,7 +54,7 @@ func GetRangeUsages(rangeType string, count int, user string, storeName string)
 
 	usages := make([]*Usage, count)
 	for i := range usages {
-		usages[i] = &Usage{}
+		usages[i] = &Usage{Prices: map[string]float64{}}
 	}
 
 	// Separate sets for each bucket
@@ -93,10 +96,7 @@ func GetRangeUsages(rangeType string, count int, user string, storeName string)
 			}
 			currentUsage.MessageCount++
 			currentUsage.TokenCount += message.TokenCount
-			currentUsage.Price += message.Price
-			if currentUsage.Currency == "" {
-				currentUsage.Currency = message.Currency
-			}
+			converter.addDisplayPrice(currentUsage.Prices, message.Price, message.Currency)
 		}
 	}
 
@@ -114,7 +114,7 @@ func GetRangeUsages(rangeType string, count int, user string, storeName string)
 			dateLabel = startDateTime.AddDate(0, i, 0).Format("2006-01")
 		}
 		usage.Date = dateLabel
-		usage.Price = model.RefinePrice(usage.Price)
+		usage.refinePrices()
 	}
 
 	return usages, nil
@@ -16,6 +16,7 @@ package object
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/casibase/casibase/util"
 	"xorm.io/core"
@@ -37,6 +38,10 @@ type Vector struct {
 	Currency    string  `xorm:"varchar(100)" json:"currency"`
 	Score       float32 `json:"score"`
 
+	SimHash     string   `xorm:"varchar(100)" json:"simHash"`
+	DuplicateOf string   `xorm:"varchar(100) index" json:"duplicateOf"`
+	Files       []string `xorm:"-" json:"files"`
+
 	Data      []float32 `xorm:"mediumtext" json:"data"`
 	Dimension int       `json:"dimension"`
 }
@@ -115,6 +120,14 @@ func UpdateVector(id string, vector *Vector) (bool, error) {
 	}
 
 	if oldVector.Text != vector.Text {
+		// The vector no longer shares its text with the duplicates, so it gets an embedding of its own
+		err = promoteDuplicateVector(oldVector, nil)
+		if err != nil {
+			return false, err
+		}
+
+		vector.SimHash = getSimHash(vector.Text)
+		vector.DuplicateOf = ""
 		if vector.Text == "" {
 			vector.Data = []float32{}
 		} else {
@@ -149,6 +162,11 @@ func AddVector(vector *Vector) (bool, error) {
 }
 
 func DeleteVector(vector *Vector) (bool, error) {
+	err := promoteDuplicateVector(vector, nil)
+	if err != nil {
+		return false, err
+	}
+
 	affected, err := adapter.engine.ID(core.PK{vector.Owner, vector.Name}).Delete(&Vector{})
 	if err != nil {
 		return false, err
@@ -157,6 +175,65 @@ func DeleteVector(vector *Vector) (bool, error) {
 	return affected != 0, nil
 }
 
+func updateVectorsFile(storeName string, file string, newFile string) (int64, error) {
+	affected, err := adapter.engine.Where("store = ? and file = ?", storeName, file).Cols("file").Update(&Vector{File: newFile})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+func deleteVectorsByFile(storeName string, file string) (int64, error) {
+	vectors := []*Vector{}
+	err := adapter.engine.Find(&vectors, &Vector{Store: storeName, File: file})
+	if err != nil {
+		return 0, err
+	}
+
+	err = promoteDuplicateVectors(vectors, func(duplicate *Vector) bool {
+		return duplicate.File == file
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := adapter.engine.Delete(&Vector{Store: storeName, File: file})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// getFolderLikePattern matches the files under the folder, with the "%" and "_" of the folder name taken literally
+func getFolderLikePattern(folder string) string {
+	replacer := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return replacer.Replace(folder) + "/%"
+}
+
+func deleteVectorsByFolder(storeName string, folder string) (int64, error) {
+	vectors := []*Vector{}
+	err := adapter.engine.Where("store = ? and file like ? escape '!'", storeName, getFolderLikePattern(folder)).Find(&vectors)
+	if err != nil {
+		return 0, err
+	}
+
+	err = promoteDuplicateVectors(vectors, func(duplicate *Vector) bool {
+		return strings.HasPrefix(duplicate.File, folder+"/")
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := adapter.engine.Where("store = ? and file like ? escape '!'", storeName, getFolderLikePattern(folder)).Delete(&Vector{})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
 func (vector *Vector) GetId() string {
 	return fmt.Sprintf("%s/%s", vector.Owner, vector.Name)
 }
@@ -0,0 +1,299 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+const (
+	simHashShingleSize = 4
+	// Two chunks whose 64-bit SimHashes differ in no more than this many bits are near-duplicates, which are embedded
+	// once and collapsed in the search results like the exact duplicates
+	simHashThreshold = 3
+)
+
+func normalizeSimHashText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// getSimHash returns the SimHash of the text over its character shingles, which works for both
+// space-separated languages and CJK text. It returns "" when the text has nothing to hash.
+func getSimHash(text string) string {
+	runes := []rune(normalizeSimHashText(text))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	size := simHashShingleSize
+	if len(runes) < size {
+		size = len(runes)
+	}
+
+	weights := [64]int{}
+	for i := 0; i+size <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+size])))
+		value := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if value&(1<<uint(b)) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var res uint64
+	for b := 0; b < 64; b++ {
+		if weights[b] > 0 {
+			res |= 1 << uint(b)
+		}
+	}
+	return fmt.Sprintf("%016x", res)
+}
+
+func parseSimHash(simHash string) (uint64, bool) {
+	value, err := strconv.ParseUint(simHash, 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func getSimHashBand(value uint64, band int) uint16 {
+	return uint16(value >> (uint(band) * 16))
+}
+
+// vectorSimHashIndex holds the SimHashes and normalized texts of the embedded (non-duplicate) vectors of a store. The
+// SimHashes are also indexed by their 4 16-bit bands: two SimHashes within the threshold of 3 bits differ in at most
+// 3 bands, so they share at least one, and only the vectors sharing a band with the text are compared
+type vectorSimHashIndex struct {
+	names     []string
+	simHashes []uint64
+	texts     []string
+	bands     [4]map[uint16][]int
+}
+
+func newVectorSimHashIndex() *vectorSimHashIndex {
+	index := &vectorSimHashIndex{}
+	for band := range index.bands {
+		index.bands[band] = map[uint16][]int{}
+	}
+	return index
+}
+
+// getVectorSimHashIndex loads the index of the store, it is built once for a refresh of the store and then kept up
+// to date by add(), instead of being loaded again for each file
+func getVectorSimHashIndex(storeName string, provider string) (*vectorSimHashIndex, error) {
+	vectors := []*Vector{}
+	err := adapter.engine.Cols("name", "sim_hash", "text").Where("store = ? and provider = ? and sim_hash != '' and (duplicate_of = '' or duplicate_of is null)", storeName, provider).Find(&vectors)
+	if err != nil {
+		return nil, err
+	}
+
+	index := newVectorSimHashIndex()
+	for _, vector := range vectors {
+		index.add(vector.Name, vector.SimHash, vector.Text)
+	}
+	return index, nil
+}
+
+func (index *vectorSimHashIndex) add(name string, simHash string, text string) {
+	value, ok := parseSimHash(simHash)
+	if !ok {
+		return
+	}
+
+	i := len(index.names)
+	index.names = append(index.names, name)
+	index.simHashes = append(index.simHashes, value)
+	index.texts = append(index.texts, normalizeSimHashText(text))
+	for band := range index.bands {
+		key := getSimHashBand(value, band)
+		index.bands[band][key] = append(index.bands[band][key], i)
+	}
+}
+
+// find returns the name of the embedded vector that the text duplicates: the one with the same normalized text,
+// or else the closest one within the threshold, the earlier added one wins a tie. It returns "" if the text is not
+// a duplicate
+func (index *vectorSimHashIndex) find(simHash string, text string) string {
+	value, ok := parseSimHash(simHash)
+	if !ok {
+		return ""
+	}
+
+	normalizedText := normalizeSimHashText(text)
+	exact := -1
+	nearest := -1
+	minDistance := simHashThreshold + 1
+	isChecked := map[int]bool{}
+	for band := range index.bands {
+		for _, i := range index.bands[band][getSimHashBand(value, band)] {
+			if isChecked[i] {
+				continue
+			}
+			isChecked[i] = true
+
+			distance := bits.OnesCount64(value ^ index.simHashes[i])
+			if distance == 0 && index.texts[i] == normalizedText && (exact == -1 || i < exact) {
+				exact = i
+			}
+			if distance < minDistance || distance == minDistance && i < nearest {
+				nearest = i
+				minDistance = distance
+			}
+		}
+	}
+
+	if exact != -1 {
+		return index.names[exact]
+	}
+	if nearest != -1 {
+		return index.names[nearest]
+	}
+	return ""
+}
+
+func addDuplicateVector(text string, storeName string, fileName string, index int, embeddingProviderName string, simHash string, duplicateOf string) (bool, error) {
+	displayName := text
+	if len(text) > 25 {
+		displayName = string([]rune(text)[:25])
+	}
+
+	vector := &Vector{
+		Owner:       "admin",
+		Name:        fmt.Sprintf("vector_%s", util.GetRandomName()),
+		CreatedTime: util.GetCurrentTime(),
+		DisplayName: displayName,
+		Store:       storeName,
+		Provider:    embeddingProviderName,
+		File:        fileName,
+		Index:       index,
+		Text:        text,
+		SimHash:     simHash,
+		DuplicateOf: duplicateOf,
+	}
+	return AddVector(vector)
+}
+
+// promoteDuplicateVector hands the embedding of a vector that is about to be deleted or changed over to one of
+// its duplicates, so that the other source files of the same text stay searchable
+func promoteDuplicateVector(vector *Vector, isRemoved func(duplicate *Vector) bool) error {
+	if vector.DuplicateOf != "" {
+		return nil
+	}
+
+	duplicates := []*Vector{}
+	err := adapter.engine.Asc("created_time").Find(&duplicates, &Vector{Owner: vector.Owner, Store: vector.Store, DuplicateOf: vector.Name})
+	if err != nil {
+		return err
+	}
+
+	var promoted *Vector
+	for _, duplicate := range duplicates {
+		if isRemoved == nil || !isRemoved(duplicate) {
+			promoted = duplicate
+			break
+		}
+	}
+	if promoted == nil {
+		return nil
+	}
+
+	promoted.DuplicateOf = ""
+	promoted.Data = vector.Data
+	promoted.Dimension = vector.Dimension
+	_, err = adapter.engine.ID(core.PK{promoted.Owner, promoted.Name}).Cols("duplicate_of", "data", "dimension").Update(promoted)
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.engine.Where("owner = ? and store = ? and duplicate_of = ?", vector.Owner, vector.Store, vector.Name).Cols("duplicate_of").Update(&Vector{DuplicateOf: promoted.Name})
+	return err
+}
+
+func promoteDuplicateVectors(vectors []*Vector, isRemoved func(duplicate *Vector) bool) error {
+	for _, vector := range vectors {
+		err := promoteDuplicateVector(vector, isRemoved)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collapseDuplicateVectors folds the permitted vectors into one vector per duplicate group, which lists all the
+// permitted source files sharing its text. The text and file of a group always come from a permitted member, a
+// restricted embedded vector only lends its embedding, and groups without any permitted member are dropped
+func collapseDuplicateVectors(vectors []*Vector, permittedVectors []*Vector) []*Vector {
+	vectorMap := map[string]*Vector{}
+	for _, vector := range vectors {
+		vectorMap[vector.Name] = vector
+	}
+
+	permittedMap := map[string]bool{}
+	for _, vector := range permittedVectors {
+		permittedMap[vector.Name] = true
+	}
+
+	res := []*Vector{}
+	groupMap := map[string]*Vector{}
+	for _, vector := range permittedVectors {
+		canonicalName := vector.Name
+		if vector.DuplicateOf != "" {
+			canonicalName = vector.DuplicateOf
+		}
+		canonical := vectorMap[canonicalName]
+		if canonical == nil {
+			continue
+		}
+
+		target, ok := groupMap[canonicalName]
+		if !ok {
+			representative := canonical
+			if !permittedMap[canonical.Name] {
+				representative = vector
+			}
+
+			collapsed := *representative
+			collapsed.Data = canonical.Data
+			collapsed.Dimension = canonical.Dimension
+			collapsed.Files = []string{}
+			target = &collapsed
+			groupMap[canonicalName] = target
+			res = append(res, target)
+		}
+		if !util.InSlice(target.Files, vector.File) {
+			target.Files = append(target.Files, vector.File)
+		}
+	}
+	return res
+}
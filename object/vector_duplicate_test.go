@@ -0,0 +1,127 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/casibase/casibase/util"
+)
+
+func TestSimHashNearDuplicates(t *testing.T) {
+	disclaimer := "This document is confidential and intended solely for the use of the individual or entity to whom it is addressed. " +
+		"If you have received this document in error, please notify the sender immediately and delete it from your system. " +
+		"Any unauthorized review, use, disclosure or distribution is prohibited."
+	pricing := "The Pro plan costs 30 USD per user per month, billed annually, and includes priority support and single sign-on."
+	refunds := "Refunds are accepted"
+
+	index := newVectorSimHashIndex()
+	index.add("vector_1", getSimHash(disclaimer), disclaimer)
+	index.add("vector_2", getSimHash(pricing), pricing)
+	index.add("vector_3", getSimHash(refunds), refunds)
+
+	tests := []struct {
+		name        string
+		text        string
+		duplicateOf string
+	}{
+		{"exact copy", disclaimer, "vector_1"},
+		{"whitespace and case", strings.ToUpper(strings.ReplaceAll(disclaimer, " ", "  \n")), "vector_1"},
+		{"punctuation", strings.ReplaceAll(disclaimer, ".", ";"), "vector_1"},
+		{"near-duplicate", strings.ReplaceAll(disclaimer, "immediately", "at once"), "vector_1"},
+		{"changed number", strings.ReplaceAll(pricing, "30 USD", "90 USD"), "vector_2"},
+		{"different text", "The quarterly revenue grew by twelve percent, driven mainly by the new subscription plans in Europe and Asia.", ""},
+		{"empty text", "   ", ""},
+	}
+
+	for _, test := range tests {
+		duplicateOf := index.find(getSimHash(test.text), test.text)
+		if duplicateOf != test.duplicateOf {
+			t.Errorf("%s: find() = %q, want %q", test.name, duplicateOf, test.duplicateOf)
+		}
+	}
+}
+
+func TestSimHashIndexBands(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	index := newVectorSimHashIndex()
+	values := []uint64{}
+	for i := 0; i < 2000; i++ {
+		value := random.Uint64()
+		values = append(values, value)
+		index.add(fmt.Sprintf("vector_%d", i), fmt.Sprintf("%016x", value), "")
+	}
+
+	// The lookup by bands finds the same vector as comparing the text with every vector
+	for i := 0; i < 1000; i++ {
+		value := values[random.Intn(len(values))]
+		for flips := random.Intn(6); flips > 0; flips-- {
+			value ^= 1 << uint(random.Intn(64))
+		}
+
+		want := ""
+		minDistance := simHashThreshold + 1
+		for j, candidate := range values {
+			distance := bits.OnesCount64(value ^ candidate)
+			if distance < minDistance {
+				want = fmt.Sprintf("vector_%d", j)
+				minDistance = distance
+			}
+		}
+
+		res := index.find(fmt.Sprintf("%016x", value), "text")
+		if res != want {
+			t.Fatalf("find(%016x) = %q, want %q", value, res, want)
+		}
+	}
+}
+
+func TestCollapseDuplicateVectors(t *testing.T) {
+	vectors := []*Vector{
+		{Name: "1", File: "a.md", Text: "restricted text", Data: []float32{1, 0}},
+		{Name: "2", File: "b.md", Text: "permitted text", DuplicateOf: "1"},
+		{Name: "3", File: "c.md", Text: "permitted text", DuplicateOf: "1"},
+		{Name: "4", File: "d.md", Text: "other text", Data: []float32{0, 1}},
+		{Name: "5", File: "a.md", Text: "restricted only", Data: []float32{1, 1}},
+		{Name: "6", File: "a.md", Text: "restricted only", DuplicateOf: "5"},
+	}
+
+	// a.md is not permitted, so the group of vector 1 is represented by b.md and the group of vector 5 is dropped
+	res := collapseDuplicateVectors(vectors, []*Vector{vectors[1], vectors[2], vectors[3]})
+	if len(res) != 2 || res[0].Name != "2" || res[1].Name != "4" {
+		t.Fatalf("collapseDuplicateVectors() returned %d vectors, want vectors 2 and 4", len(res))
+	}
+	for _, vector := range res {
+		if vector.Text == "restricted text" || vector.Text == "restricted only" || vector.File == "a.md" || util.InSlice(vector.Files, "a.md") {
+			t.Errorf("vector %s leaks the restricted content of a.md", vector.Name)
+		}
+	}
+	if strings.Join(res[0].Files, ",") != "b.md,c.md" {
+		t.Errorf("files of vector 2 = %v, want [b.md c.md]", res[0].Files)
+	}
+	if len(res[0].Data) != 2 || res[0].Data[0] != 1 {
+		t.Errorf("vector 2 should carry the embedding of vector 1, got %v", res[0].Data)
+	}
+
+	// a permitted embedded vector keeps representing its group
+	res = collapseDuplicateVectors(vectors, []*Vector{vectors[1], vectors[0]})
+	if len(res) != 1 || res[0].Name != "1" || strings.Join(res[0].Files, ",") != "b.md,a.md" {
+		t.Errorf("collapseDuplicateVectors() = %v, want vector 1 with files [b.md a.md]", res)
+	}
+}
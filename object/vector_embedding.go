@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/agent"
 	"github.com/casibase/casibase/embedding"
 	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/split"
@@ -47,10 +49,20 @@ func filterTextFiles(files []*storage.Object) []*storage.Object {
 	return res
 }
 
-func addEmbeddedVector(embeddingProviderObj embedding.EmbeddingProvider, text string, storeName string, fileName string, index int, embeddingProviderName string, modelSubType string) (bool, error) {
+func getParsedTextFromObject(storageProviderObj storage.StorageProvider, key string, fileExt string) (string, error) {
+	reader, err := storageProviderObj.GetObject(key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	return txt.GetParsedTextFromReader(reader, fileExt)
+}
+
+func addEmbeddedVector(embeddingProviderObj embedding.EmbeddingProvider, text string, storeName string, fileName string, index int, embeddingProviderName string, modelSubType string, simHash string) (*Vector, error) {
 	data, embeddingResult, err := queryVectorSafe(embeddingProviderObj, text)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	displayName := text
@@ -69,7 +81,7 @@ func addEmbeddedVector(embeddingProviderObj embedding.EmbeddingProvider, text st
 
 	defaultEmbeddingResult, err := embedding.GetDefaultEmbeddingResult(modelSubType, text)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	if tokenCount == 0 {
@@ -95,94 +107,138 @@ func addEmbeddedVector(embeddingProviderObj embedding.EmbeddingProvider, text st
 		TokenCount:  tokenCount,
 		Price:       price,
 		Currency:    currency,
+		SimHash:     simHash,
 		Data:        data,
 		Dimension:   len(data),
 	}
-	return AddVector(vector)
+	_, err = AddVector(vector)
+	if err != nil {
+		return nil, err
+	}
+
+	return vector, nil
 }
 
-func addVectorsForStore(storageProviderObj storage.StorageProvider, embeddingProviderObj embedding.EmbeddingProvider, prefix string, storeName string, splitProviderName string, embeddingProviderName string, modelSubType string) (bool, error) {
+func addVectorsForFile(storageProviderObj storage.StorageProvider, embeddingProviderObj embedding.EmbeddingProvider, file *storage.Object, storeName string, splitProviderName string, embeddingProviderName string, modelSubType string, simHashIndex *vectorSimHashIndex) (bool, error) {
 	var affected bool
 
-	files, err := storageProviderObj.ListObjects(prefix)
+	fileExt := filepath.Ext(file.Key)
+	text, err := getParsedTextFromObject(storageProviderObj, file.Key, fileExt)
 	if err != nil {
 		return false, err
 	}
 
-	files = filterTextFiles(files)
+	splitProviderType := splitProviderName
+	if splitProviderType == "" {
+		splitProviderType = "Default"
+	}
 
-	for _, file := range files {
-		var text string
-		fileExt := filepath.Ext(file.Key)
-		text, err = txt.GetParsedTextFromUrl(file.Url, fileExt)
+	if strings.HasPrefix(file.Key, "QA") && fileExt == ".docx" {
+		splitProviderType = "QA"
+	}
+
+	if fileExt == ".md" {
+		splitProviderType = "Markdown"
+	}
+	splitProvider, err := split.GetSplitProvider(splitProviderType)
+	if err != nil {
+		return false, err
+	}
+
+	textSections, err := splitProvider.SplitText(text)
+	if err != nil {
+		return false, err
+	}
+
+	for i, textSection := range textSections {
+		var vector *Vector
+		vector, err = getVectorByIndex("admin", storeName, file.Key, i)
 		if err != nil {
 			return false, err
 		}
 
-		splitProviderType := splitProviderName
-		if splitProviderType == "" {
-			splitProviderType = "Default"
+		if vector != nil {
+			fmt.Printf("[%d/%d] Generating embedding for store: [%s], file: [%s], index: [%d]: %s\n", i+1, len(textSections), storeName, file.Key, i, "Skipped due to already exists")
+			continue
 		}
 
-		if strings.HasPrefix(file.Key, "QA") && fileExt == ".docx" {
-			splitProviderType = "QA"
+		simHash := getSimHash(textSection)
+		duplicateOf := simHashIndex.find(simHash, textSection)
+		if duplicateOf != "" {
+			fmt.Printf("[%d/%d] Generating embedding for store: [%s], file: [%s], index: [%d]: %s\n", i+1, len(textSections), storeName, file.Key, i, "Skipped due to duplicate of vector: "+duplicateOf)
+			affected, err = addDuplicateVector(textSection, storeName, file.Key, i, embeddingProviderName, simHash, duplicateOf)
+			if err != nil {
+				return false, err
+			}
+			continue
 		}
 
-		if fileExt == ".md" {
-			splitProviderType = "Markdown"
+		fmt.Printf("[%d/%d] Generating embedding for store: [%s], file: [%s], index: [%d]: %s\n", i+1, len(textSections), storeName, file.Key, i, textSection)
+
+		operation := func() error {
+			vector, err = addEmbeddedVector(embeddingProviderObj, textSection, storeName, file.Key, i, embeddingProviderName, modelSubType, simHash)
+			if err != nil {
+				if isRetryableError(err) {
+					return err
+				}
+				return backoff.Permanent(err)
+			}
+			return nil
 		}
-		var splitProvider split.SplitProvider
-		splitProvider, err = split.GetSplitProvider(splitProviderType)
+		err = backoff.Retry(operation, backoff.NewExponentialBackOff())
 		if err != nil {
+			fmt.Printf("Failed to generate embedding after retries: %v\n", err)
 			return false, err
 		}
 
-		var textSections []string
-		textSections, err = splitProvider.SplitText(text)
-		if err != nil {
-			return false, err
-		}
+		affected = true
+		simHashIndex.add(vector.Name, simHash, textSection)
+	}
 
-		for i, textSection := range textSections {
-			var vector *Vector
-			vector, err = getVectorByIndex("admin", storeName, file.Key, i)
-			if err != nil {
-				return false, err
-			}
+	return affected, err
+}
 
-			if vector != nil {
-				fmt.Printf("[%d/%d] Generating embedding for store: [%s], file: [%s], index: [%d]: %s\n", i+1, len(textSections), storeName, file.Key, i, "Skipped due to already exists")
-				continue
-			}
+func addVectorsForStore(storageProviderObj storage.StorageProvider, embeddingProviderObj embedding.EmbeddingProvider, prefix string, storeName string, splitProviderName string, embeddingProviderName string, modelSubType string) (bool, error) {
+	var affected bool
 
-			fmt.Printf("[%d/%d] Generating embedding for store: [%s], file: [%s], index: [%d]: %s\n", i+1, len(textSections), storeName, file.Key, i, textSection)
+	files, err := storageProviderObj.ListObjects(prefix)
+	if err != nil {
+		return false, err
+	}
 
-			operation := func() error {
-				affected, err = addEmbeddedVector(embeddingProviderObj, textSection, storeName, file.Key, i, embeddingProviderName, modelSubType)
-				if err != nil {
-					if isRetryableError(err) {
-						return err
-					}
-					return backoff.Permanent(err)
-				}
-				return nil
-			}
-			err = backoff.Retry(operation, backoff.NewExponentialBackOff())
-			if err != nil {
-				fmt.Printf("Failed to generate embedding after retries: %v\n", err)
-				return false, err
-			}
+	files = filterTextFiles(files)
+
+	simHashIndex, err := getVectorSimHashIndex(storeName, embeddingProviderName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, file := range files {
+		var ok bool
+		ok, err = addVectorsForFile(storageProviderObj, embeddingProviderObj, file, storeName, splitProviderName, embeddingProviderName, modelSubType, simHashIndex)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			affected = true
 		}
 	}
 
 	return affected, err
 }
 
-func getRelatedVectors(storeName string, provider string) ([]*Vector, error) {
+func getRelatedVectors(storeName string, provider string, user *casdoorsdk.User) ([]*Vector, error) {
 	vectors, err := getVectorsByProvider(storeName, provider)
 	if err != nil {
 		return nil, err
 	}
+
+	permittedVectors, err := getPermittedVectors(storeName, vectors, user)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors = collapseDuplicateVectors(vectors, permittedVectors)
 	if len(vectors) == 0 {
 		return nil, fmt.Errorf("no knowledge vectors found")
 	}
@@ -220,13 +276,13 @@ func queryVectorSafe(embeddingProvider embedding.EmbeddingProvider, text string)
 	}
 }
 
-func GetNearestKnowledge(storeName string, searchProviderType string, embeddingProvider *Provider, embeddingProviderObj embedding.EmbeddingProvider, modelProvider *Provider, owner string, text string, knowledgeCount int) ([]*model.RawMessage, []VectorScore, *embedding.EmbeddingResult, error) {
+func GetNearestKnowledge(storeName string, searchProviderType string, embeddingProvider *Provider, embeddingProviderObj embedding.EmbeddingProvider, modelProvider *Provider, owner string, text string, knowledgeCount int, user *casdoorsdk.User) ([]*model.RawMessage, []VectorScore, *embedding.EmbeddingResult, error) {
 	searchProvider, err := GetSearchProvider(searchProviderType, owner)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	vectors, embeddingResult, err := searchProvider.Search(storeName, embeddingProvider.Name, embeddingProviderObj, modelProvider.Name, text, knowledgeCount)
+	vectors, embeddingResult, err := searchProvider.Search(storeName, embeddingProvider.Name, embeddingProviderObj, modelProvider.Name, text, knowledgeCount, user)
 	if err != nil {
 		if err.Error() == "no knowledge vectors found" {
 			return nil, nil, embeddingResult, err
@@ -255,3 +311,31 @@ func GetNearestKnowledge(storeName string, searchProviderType string, embeddingP
 
 	return knowledge, vectorScores, embeddingResult, nil
 }
+
+// newStoreKnowledgeSearcher returns the knowledge searcher of the "search_knowledge" tool of the builtin agent
+// providers, it searches on behalf of the user, so the vectors are filtered by the file permissions of the user
+func newStoreKnowledgeSearcher(user *casdoorsdk.User) agent.KnowledgeSearcher {
+	return func(ctx context.Context, storeName string, query string) (string, error) {
+		store, err := getStore("admin", storeName)
+		if err != nil {
+			return "", err
+		}
+		if store == nil {
+			return "", fmt.Errorf("the store: %s is not found", storeName)
+		}
+
+		knowledge, _, err := SearchStoreKnowledge(store, query, user)
+		if err != nil {
+			return "", err
+		}
+		if len(knowledge) == 0 {
+			return "No relevant knowledge is found.", nil
+		}
+
+		texts := []string{}
+		for i, item := range knowledge {
+			texts = append(texts, fmt.Sprintf("[%d] %s", i+1, item.Text))
+		}
+		return strings.Join(texts, "\n\n"), nil
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routers
+
+import "testing"
+
+func TestIsAllowedInDemoMode(t *testing.T) {
+	tests := []struct {
+		method  string
+		urlPath string
+		want    bool
+	}{
+		{"GET", "/api/get-stores", true},
+		{"POST", "/api/add-message", true},
+		{"POST", "/api/stop-message-answer", true},
+		{"POST", "/api/approve-tool-call", true},
+		{"POST", "/api/update-store", false},
+		{"POST", "/api/delete-memory", false},
+	}
+	for _, test := range tests {
+		if got := isAllowedInDemoMode(test.method, test.urlPath); got != test.want {
+			t.Errorf("isAllowedInDemoMode(%s, %s) = %v, want %v", test.method, test.urlPath, got, test.want)
+		}
+	}
+}
@@ -18,13 +18,23 @@ import (
 	"github.com/beego/beego/context"
 )
 
+// The bearer token of these paths can be the API key of a store or the Provider key of a provider, which is checked
+// by the API itself
+var apiKeyPaths = map[string]bool{
+	"/api/chat/completions": true,
+	"/api/models":           true,
+	"/api/embeddings":       true,
+	"/api/mcp":              true,
+	"/v1/messages":          true,
+}
+
 func AutoSigninFilter(ctx *context.Context) {
 	// HTTP Bearer token like "Authorization: Bearer 123"
 	accessToken := ctx.Input.Query("accessToken")
 	if accessToken == "" {
 		accessToken = ctx.Input.Query("access_token")
 	}
-	if accessToken == "" {
+	if accessToken == "" && !apiKeyPaths[ctx.Request.URL.Path] {
 		accessToken = parseBearerToken(ctx)
 	}
 	if accessToken != "" {
@@ -0,0 +1,198 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beego/beego"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/controllers"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// initTestDatabase points the object package to an empty SQLite database, which is enough for the API key lookups
+// and the usage records of the API
+func initTestDatabase(t *testing.T) {
+	t.Setenv("driverName", "sqlite")
+	t.Setenv("dataSourceName", "file:"+t.TempDir()+"/casibase.db?cache=shared")
+	t.Setenv("dbName", "")
+	t.Setenv("providerDbName", "")
+
+	object.InitFlag()
+	object.InitAdapter()
+	object.CreateTables()
+	util.InitParser()
+}
+
+// newTestApiHandler routes the OpenAI-compatible API behind the AutoSigninFilter like main.go does
+func newTestApiHandler(t *testing.T) http.Handler {
+	oldCopyRequestBody := beego.BConfig.CopyRequestBody
+	beego.BConfig.CopyRequestBody = true
+	t.Cleanup(func() {
+		beego.BConfig.CopyRequestBody = oldCopyRequestBody
+	})
+
+	handler := beego.NewControllerRegister()
+	err := handler.InsertFilter("*", beego.BeforeRouter, AutoSigninFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler.Add("/api/chat/completions", &controllers.ApiController{}, "POST:ChatCompletions")
+	handler.Add("/api/models", &controllers.ApiController{}, "GET:ListModels")
+	handler.Add("/api/embeddings", &controllers.ApiController{}, "POST:Embeddings")
+	handler.Add("/v1/messages", &controllers.ApiController{}, "POST:AnthropicMessages")
+	return handler
+}
+
+func serveTestApiRequest(t *testing.T, handler http.Handler, method string, urlPath string, apiKey string, body string) map[string]interface{} {
+	return serveTestApiRequestWithHeader(t, handler, method, urlPath, "Authorization", "Bearer "+apiKey, body)
+}
+
+func serveTestApiRequestWithHeader(t *testing.T, handler http.Handler, method string, urlPath string, header string, value string, body string) map[string]interface{} {
+	request := httptest.NewRequest(method, urlPath, strings.NewReader(body))
+	request.Header.Set(header, value)
+	request.Header.Set("Content-Type", "application/json")
+	request.RemoteAddr = "127.0.0.1:40000"
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("%s %s: failed to parse the response %q, %s", method, urlPath, recorder.Body.String(), err.Error())
+	}
+	if status, ok := response["status"]; ok && status == "error" {
+		t.Fatalf("%s %s: rejected by the filter, %v", method, urlPath, response["msg"])
+	}
+	return response
+}
+
+func addTestProvider(t *testing.T, provider *object.Provider) {
+	_, err := object.AddProvider(provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoSigninFilterProviderKey(t *testing.T) {
+	initTestDatabase(t)
+	addTestProvider(t, &object.Provider{Owner: "admin", Name: "provider-model", Category: "Model", Type: "Dummy", ProviderKey: "model-key"})
+	addTestProvider(t, &object.Provider{Owner: "admin", Name: "provider-embedding", Category: "Embedding", Type: "Dummy", ProviderKey: "embedding-key"})
+	handler := newTestApiHandler(t)
+
+	// The Provider key reaches the controller instead of being taken as a Casdoor access token
+	response := serveTestApiRequest(t, handler, "GET", "/api/models", "model-key", "")
+	data, _ := response["data"].([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["id"] != "provider-model" {
+		t.Errorf("GET /api/models = %v, want the model provider", response)
+	}
+
+	response = serveTestApiRequest(t, handler, "POST", "/api/embeddings", "embedding-key", `{"input": ["hello", "world"]}`)
+	data, _ = response["data"].([]interface{})
+	if len(data) != 2 {
+		t.Errorf("POST /api/embeddings = %v, want 2 embeddings", response)
+	}
+
+	response = serveTestApiRequest(t, handler, "POST", "/api/chat/completions", "model-key", `{"model": "provider-model", "messages": [{"role": "user", "content": "hello"}]}`)
+	choices, _ := response["choices"].([]interface{})
+	if len(choices) != 1 || !strings.Contains(choices[0].(map[string]interface{})["message"].(map[string]interface{})["content"].(string), "hello") {
+		t.Errorf("POST /api/chat/completions = %v, want the answer of the model provider", response)
+	}
+
+	// The Anthropic-compatible API takes the Provider key from "x-api-key" or from the bearer token
+	body := `{"model": "provider-model", "max_tokens": 100, "messages": [{"role": "user", "content": "hello"}]}`
+	for _, header := range []string{"x-api-key", "Authorization"} {
+		value := "model-key"
+		if header == "Authorization" {
+			value = "Bearer model-key"
+		}
+
+		response = serveTestApiRequestWithHeader(t, handler, "POST", "/v1/messages", header, value, body)
+		content, _ := response["content"].([]interface{})
+		if len(content) != 1 || !strings.Contains(content[0].(map[string]interface{})["text"].(string), "hello") {
+			t.Errorf("POST /v1/messages with %s = %v, want the answer of the model provider", header, response)
+		}
+	}
+
+	// A wrong key is refused by the API itself, in the format of the OpenAI API
+	response = serveTestApiRequest(t, handler, "GET", "/api/models", "wrong-key", "")
+	if _, ok := response["error"]; !ok {
+		t.Errorf("GET /api/models with a wrong key = %v, want an OpenAI error", response)
+	}
+}
+
+// initTestCasdoor points the Casdoor SDK to a server without any permissions, so that the store files are open
+func initTestCasdoor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "ok", "msg": "", "data": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	casdoorsdk.InitConfig(server.URL, "client-id", "client-secret", "", "casbin", "app-casibase")
+}
+
+func TestAutoSigninFilterStoreKey(t *testing.T) {
+	initTestDatabase(t)
+	initTestCasdoor(t)
+	addTestProvider(t, &object.Provider{Owner: "admin", Name: "provider-model", Category: "Model", Type: "Dummy"})
+	addTestProvider(t, &object.Provider{Owner: "admin", Name: "provider-embedding", Category: "Embedding", Type: "Dummy"})
+	_, err := object.AddStore(&object.Store{Owner: "admin", Name: "store-api", ModelProvider: "provider-model", EmbeddingProvider: "provider-embedding", ApiKey: "store-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newTestApiHandler(t)
+
+	// The API key of a store reaches the store model instead of being taken as a Casdoor access token
+	response := serveTestApiRequest(t, handler, "GET", "/api/models", "store-key", "")
+	data, _ := response["data"].([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["id"] != "store:store-api" {
+		t.Errorf("GET /api/models = %v, want the store model", response)
+	}
+
+	response = serveTestApiRequest(t, handler, "POST", "/api/chat/completions", "store-key", `{"model": "store:store-api", "messages": [{"role": "user", "content": "hello"}]}`)
+	choices, _ := response["choices"].([]interface{})
+	if len(choices) != 1 || !strings.Contains(choices[0].(map[string]interface{})["message"].(map[string]interface{})["content"].(string), "hello") {
+		t.Errorf("POST /api/chat/completions = %v, want the answer of the store", response)
+	}
+
+	// The key of a store doesn't open another store
+	response = serveTestApiRequest(t, handler, "POST", "/api/chat/completions", "store-key", `{"model": "store:store-other", "messages": [{"role": "user", "content": "hello"}]}`)
+	if _, ok := response["error"]; !ok {
+		t.Errorf("POST /api/chat/completions for another store = %v, want an OpenAI error", response)
+	}
+
+	// A store is not given an API key unless the admin generates one, so it stays closed to the API
+	closedStore := &object.Store{Owner: "admin", Name: "store-closed", ModelProvider: "provider-model", EmbeddingProvider: "provider-embedding"}
+	_, err = object.AddStore(closedStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closedStore.ApiKey != "" {
+		t.Errorf("AddStore() API key = %q, want none", closedStore.ApiKey)
+	}
+
+	response = serveTestApiRequest(t, handler, "POST", "/api/chat/completions", "***", `{"model": "store:store-closed", "messages": [{"role": "user", "content": "hello"}]}`)
+	if _, ok := response["error"]; !ok {
+		t.Errorf("POST /api/chat/completions for a store without API key = %v, want an OpenAI error", response)
+	}
+}
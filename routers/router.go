@@ -89,15 +89,23 @@ func initAPI() {
 	beego.Router("/api/update-chat", &controllers.ApiController{}, "POST:UpdateChat")
 	beego.Router("/api/add-chat", &controllers.ApiController{}, "POST:AddChat")
 	beego.Router("/api/delete-chat", &controllers.ApiController{}, "POST:DeleteChat")
+	beego.Router("/api/export-chat", &controllers.ApiController{}, "GET:ExportChat")
+	beego.Router("/api/import-chat", &controllers.ApiController{}, "POST:ImportChat")
+	beego.Router("/api/share-chat", &controllers.ApiController{}, "POST:ShareChat")
+	beego.Router("/api/unshare-chat", &controllers.ApiController{}, "POST:UnshareChat")
+	beego.Router("/api/get-shared-chat", &controllers.ApiController{}, "GET:GetSharedChat")
 
 	beego.Router("/api/get-global-messages", &controllers.ApiController{}, "GET:GetGlobalMessages")
 	beego.Router("/api/get-messages", &controllers.ApiController{}, "GET:GetMessages")
 	beego.Router("/api/get-message", &controllers.ApiController{}, "GET:GetMessage")
 	beego.Router("/api/get-message-answer", &controllers.ApiController{}, "GET:GetMessageAnswer")
+	beego.Router("/api/stop-message-answer", &controllers.ApiController{}, "POST:StopMessageAnswer")
+	beego.Router("/api/approve-tool-call", &controllers.ApiController{}, "POST:ApproveToolCall")
 	beego.Router("/api/get-answer", &controllers.ApiController{}, "GET:GetAnswer")
 	beego.Router("/api/update-message", &controllers.ApiController{}, "POST:UpdateMessage")
 	beego.Router("/api/add-message", &controllers.ApiController{}, "POST:AddMessage")
 	beego.Router("/api/delete-message", &controllers.ApiController{}, "POST:DeleteMessage")
+	beego.Router("/api/switch-message-branch", &controllers.ApiController{}, "POST:SwitchMessageBranch")
 	beego.Router("/api/delete-welcome-message", &controllers.ApiController{}, "POST:DeleteWelcomeMessage")
 
 	beego.Router("/api/get-global-graphs", &controllers.ApiController{}, "GET:GetGlobalGraphs")
@@ -114,6 +122,25 @@ func initAPI() {
 	beego.Router("/api/delete-template", &controllers.ApiController{}, "POST:DeleteTemplate")
 	beego.Router("/api/get-k8s-status", &controllers.ApiController{}, "GET:GetK8sStatus")
 
+	beego.Router("/api/get-pricings", &controllers.ApiController{}, "GET:GetPricings")
+	beego.Router("/api/get-pricing", &controllers.ApiController{}, "GET:GetPricing")
+	beego.Router("/api/update-pricing", &controllers.ApiController{}, "POST:UpdatePricing")
+	beego.Router("/api/add-pricing", &controllers.ApiController{}, "POST:AddPricing")
+	beego.Router("/api/delete-pricing", &controllers.ApiController{}, "POST:DeletePricing")
+
+	beego.Router("/api/get-exchange-rates", &controllers.ApiController{}, "GET:GetExchangeRates")
+	beego.Router("/api/get-exchange-rate", &controllers.ApiController{}, "GET:GetExchangeRate")
+	beego.Router("/api/update-exchange-rate", &controllers.ApiController{}, "POST:UpdateExchangeRate")
+	beego.Router("/api/add-exchange-rate", &controllers.ApiController{}, "POST:AddExchangeRate")
+	beego.Router("/api/delete-exchange-rate", &controllers.ApiController{}, "POST:DeleteExchangeRate")
+	beego.Router("/api/convert-price", &controllers.ApiController{}, "GET:ConvertPrice")
+
+	beego.Router("/api/get-memories", &controllers.ApiController{}, "GET:GetMemories")
+	beego.Router("/api/get-memory", &controllers.ApiController{}, "GET:GetMemory")
+	beego.Router("/api/update-memory", &controllers.ApiController{}, "POST:UpdateMemory")
+	beego.Router("/api/add-memory", &controllers.ApiController{}, "POST:AddMemory")
+	beego.Router("/api/delete-memory", &controllers.ApiController{}, "POST:DeleteMemory")
+
 	beego.Router("/api/get-applications", &controllers.ApiController{}, "GET:GetApplications")
 	beego.Router("/api/get-application", &controllers.ApiController{}, "GET:GetApplication")
 	beego.Router("/api/update-application", &controllers.ApiController{}, "POST:UpdateApplication")
@@ -165,6 +192,7 @@ func initAPI() {
 	beego.Router("/api/update-file", &controllers.ApiController{}, "POST:UpdateFile")
 	beego.Router("/api/add-file", &controllers.ApiController{}, "POST:AddFile")
 	beego.Router("/api/delete-file", &controllers.ApiController{}, "POST:DeleteFile")
+	beego.Router("/api/move-file", &controllers.ApiController{}, "POST:MoveFile")
 	beego.Router("/api/activate-file", &controllers.ApiController{}, "POST:ActivateFile")
 	beego.Router("/api/get-active-file", &controllers.ApiController{}, "GET:GetActiveFile")
 
@@ -243,4 +271,8 @@ func initAPI() {
 	beego.Handler("/api/metrics", promhttp.Handler())
 
 	beego.Router("/api/chat/completions", &controllers.ApiController{}, "POST:ChatCompletions")
+	beego.Router("/api/mcp", &controllers.ApiController{}, "POST:McpServer;GET:McpServer;DELETE:McpServer")
+	beego.Router("/api/models", &controllers.ApiController{}, "GET:ListModels")
+	beego.Router("/api/embeddings", &controllers.ApiController{}, "POST:Embeddings")
+	beego.Router("/v1/messages", &controllers.ApiController{}, "POST:AnthropicMessages")
 }
@@ -17,9 +17,11 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/casibase/casibase/conf"
+	"github.com/casibase/casibase/util"
 )
 
 type CasdoorProvider struct {
@@ -73,3 +75,79 @@ func (p *CasdoorProvider) DeleteObject(key string) error {
 	}
 	return nil
 }
+
+func (p *CasdoorProvider) getResource(key string) (*casdoorsdk.Resource, error) {
+	casdoorOrganization := conf.GetConfigString("casdoorOrganization")
+	casdoorApplication := conf.GetConfigString("casdoorApplication")
+	resources, err := casdoorsdk.GetResources(casdoorOrganization, casdoorApplication, "provider", p.providerName, "Direct", key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		if resource.Name == key {
+			return resource, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *CasdoorProvider) GetObject(key string) (io.ReadCloser, error) {
+	resource, err := p.getResource(key)
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil {
+		return nil, fmt.Errorf("the object: %s is not found in storage provider: %s", key, p.providerName)
+	}
+
+	fileBuffer, err := util.DownloadFile(resource.Url)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(fileBuffer), nil
+}
+
+func (p *CasdoorProvider) StatObject(key string) (*Object, error) {
+	resource, err := p.getResource(key)
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil {
+		return nil, nil
+	}
+
+	return &Object{
+		Key:          resource.Name,
+		LastModified: resource.CreatedTime,
+		Size:         int64(resource.FileSize),
+		Url:          resource.Url,
+	}, nil
+}
+
+func (p *CasdoorProvider) CopyObject(srcKey string, dstKey string) error {
+	resource, err := p.getResource(srcKey)
+	if err != nil {
+		return err
+	}
+	if resource == nil {
+		return fmt.Errorf("the object: %s is not found in storage provider: %s", srcKey, p.providerName)
+	}
+
+	fileBuffer, err := util.DownloadFile(resource.Url)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.PutObject(resource.User, resource.Parent, dstKey, fileBuffer)
+	return err
+}
+
+func (p *CasdoorProvider) MoveObject(srcKey string, dstKey string) error {
+	err := p.CopyObject(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+
+	return p.DeleteObject(srcKey)
+}
@@ -93,3 +93,83 @@ func (p *LocalFileSystemStorageProvider) DeleteObject(key string) error {
 	}
 	return os.RemoveAll(fullPath)
 }
+
+func (p *LocalFileSystemStorageProvider) GetObject(key string) (io.ReadCloser, error) {
+	fullPath := filepath.Join(p.path, key)
+	return os.Open(filepath.Clean(fullPath))
+}
+
+func (p *LocalFileSystemStorageProvider) StatObject(key string) (*Object, error) {
+	fullPath := filepath.Join(p.path, key)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	fullPath = strings.ReplaceAll(fullPath, "\\", "/")
+	return &Object{
+		Key:          strings.TrimPrefix(key, "/"),
+		LastModified: info.ModTime().Format(time.RFC3339),
+		Size:         info.Size(),
+		Url:          fullPath,
+	}, nil
+}
+
+func (p *LocalFileSystemStorageProvider) CopyObject(srcKey string, dstKey string) error {
+	src, err := p.GetObject(srcKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := filepath.Join(p.path, dstKey)
+	err = os.MkdirAll(filepath.Dir(dstPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.Create(filepath.Clean(dstPath))
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (p *LocalFileSystemStorageProvider) MoveObject(srcKey string, dstKey string) error {
+	srcPath := filepath.Join(p.path, srcKey)
+	dstPath := filepath.Join(p.path, dstKey)
+	err := os.MkdirAll(filepath.Dir(dstPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(srcPath, dstPath)
+	if err != nil {
+		return err
+	}
+
+	p.removeEmptyFolders(filepath.Dir(srcPath))
+	return nil
+}
+
+// removeEmptyFolders removes the folder and its parents until a non-empty one or the root path is met
+func (p *LocalFileSystemStorageProvider) removeEmptyFolders(folder string) {
+	root := filepath.Clean(p.path)
+	for folder = filepath.Clean(folder); folder != root && strings.HasPrefix(folder, root); folder = filepath.Dir(folder) {
+		entries, err := os.ReadDir(folder)
+		if err != nil || len(entries) != 0 {
+			return
+		}
+
+		err = os.Remove(folder)
+		if err != nil {
+			return
+		}
+	}
+}
@@ -17,6 +17,8 @@ package storage
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/url"
 	"strings"
 	"time"
@@ -91,6 +93,32 @@ func (p *OpenAIFileSystemStorageProvider) DeleteObject(key string) error {
 	return nil
 }
 
+func (p *OpenAIFileSystemStorageProvider) GetObject(key string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("reading file content is not supported by OpenAI File System storage provider")
+}
+
+func (p *OpenAIFileSystemStorageProvider) StatObject(key string) (*Object, error) {
+	objects, _, err := p.getCachedFiles(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		if object.Key == key {
+			return object, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *OpenAIFileSystemStorageProvider) CopyObject(srcKey string, dstKey string) error {
+	return fmt.Errorf("copying files is not supported by OpenAI File System storage provider")
+}
+
+func (p *OpenAIFileSystemStorageProvider) MoveObject(srcKey string, dstKey string) error {
+	return fmt.Errorf("moving files is not supported by OpenAI File System storage provider")
+}
+
 func (p *OpenAIFileSystemStorageProvider) getCachedFiles(prefix string) ([]*Object, []CachedFile, error) {
 	var objects []*Object
 	var cachedFiles []CachedFile
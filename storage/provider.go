@@ -14,7 +14,10 @@
 
 package storage
 
-import "bytes"
+import (
+	"bytes"
+	"io"
+)
 
 type Object struct {
 	Key          string
@@ -27,15 +30,23 @@ type StorageProvider interface {
 	ListObjects(prefix string) ([]*Object, error)
 	PutObject(user string, parent string, key string, fileBuffer *bytes.Buffer) (string, error)
 	DeleteObject(key string) error
+	GetObject(key string) (io.ReadCloser, error)
+	StatObject(key string) (*Object, error)
+	CopyObject(srcKey string, dstKey string) error
+	MoveObject(srcKey string, dstKey string) error
 }
 
-func GetStorageProvider(typ string, clientId string, clientSecret string, providerName string, vectorStoreId string) (StorageProvider, error) {
+func GetStorageProvider(typ string, clientId string, clientSecret string, providerUrl string, providerName string, vectorStoreId string) (StorageProvider, error) {
 	var p StorageProvider
 	var err error
 	if typ == "Local File System" {
 		p, err = NewLocalFileSystemStorageProvider(clientId)
 	} else if typ == "OpenAI File System" {
 		p, err = NewOpenAIFileSystemStorageProvider(vectorStoreId, clientSecret)
+	} else if typ == "WebDAV" {
+		p, err = NewWebDavStorageProvider(providerUrl, clientId, clientSecret)
+	} else if typ == "SFTP" {
+		p, err = NewSftpStorageProvider(providerUrl, clientId, clientSecret)
 	} else {
 		p, err = NewCasdoorProvider(providerName)
 	}
@@ -0,0 +1,350 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+type SftpStorageProvider struct {
+	host        string
+	basePath    string
+	username    string
+	password    string
+	fingerprint string
+}
+
+// NewSftpStorageProvider creates the provider from a URL like sftp://host:22/path?fingerprint=SHA256:xxx. The host key
+// of the server must match the SHA256 fingerprint as printed by ssh-keygen -l, the connection is refused without it
+func NewSftpStorageProvider(providerUrl string, username string, password string) (*SftpStorageProvider, error) {
+	if providerUrl == "" {
+		return nil, fmt.Errorf("the provider URL of SFTP storage provider should not be empty")
+	}
+
+	if !strings.Contains(providerUrl, "://") {
+		providerUrl = "sftp://" + providerUrl
+	}
+
+	u, err := url.Parse(providerUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "sftp" {
+		return nil, fmt.Errorf("the provider URL of SFTP storage provider should start with sftp://, got: %s", providerUrl)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	basePath := u.Path
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	// The base64 of a fingerprint may have "+" in it, which the query decoding turns into a space
+	fingerprint := strings.ReplaceAll(u.Query().Get("fingerprint"), " ", "+")
+	if fingerprint != "" && !strings.HasPrefix(fingerprint, "SHA256:") {
+		return nil, fmt.Errorf("the host key fingerprint of SFTP storage provider should start with SHA256:, got: %s", fingerprint)
+	}
+
+	p := &SftpStorageProvider{
+		host:        host,
+		basePath:    basePath,
+		username:    username,
+		password:    password,
+		fingerprint: fingerprint,
+	}
+	return p, nil
+}
+
+func (p *SftpStorageProvider) checkHostKey(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+	if p.fingerprint == "" {
+		return fmt.Errorf("the host key fingerprint of SFTP storage provider is not configured, add ?fingerprint=%s to the provider URL if it is the host key of SFTP server: %s", fingerprint, hostname)
+	}
+
+	if fingerprint != p.fingerprint {
+		return fmt.Errorf("the host key fingerprint of SFTP server: %s is %s, which does not match the configured %s", hostname, fingerprint, p.fingerprint)
+	}
+	return nil
+}
+
+func (p *SftpStorageProvider) getClient() (*sftp.Client, *ssh.Client, error) {
+	config := &ssh.ClientConfig{
+		User: p.username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(p.password),
+		},
+		HostKeyCallback: p.checkHostKey,
+		Timeout:         30 * time.Second,
+	}
+
+	sshClient, err := ssh.Dial("tcp", p.host, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, nil, err
+	}
+
+	return client, sshClient, nil
+}
+
+// getFullPath resolves the key under the base path, a key with ".." segments can't go above it
+func (p *SftpStorageProvider) getFullPath(key string) string {
+	return path.Join(p.basePath, path.Clean("/"+key))
+}
+
+func (p *SftpStorageProvider) getUrl(key string) string {
+	u := url.URL{
+		Scheme: "sftp",
+		Host:   p.host,
+		Path:   p.getFullPath(key),
+	}
+	return u.String()
+}
+
+func (p *SftpStorageProvider) ListObjects(prefix string) ([]*Object, error) {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	objects := []*Object{}
+	fullPath := p.getFullPath(prefix)
+	walker := client.Walk(fullPath)
+	for walker.Step() {
+		if walker.Err() != nil {
+			if os.IsNotExist(walker.Err()) {
+				continue
+			}
+			return nil, walker.Err()
+		}
+
+		if walker.Path() == fullPath {
+			continue
+		}
+
+		info := walker.Stat()
+		base := path.Base(walker.Path())
+		if info.IsDir() {
+			if strings.HasPrefix(base, ".") || base == "node_modules" {
+				walker.SkipDir()
+			}
+			continue
+		}
+
+		relativePath := strings.TrimPrefix(walker.Path(), p.basePath)
+		relativePath = strings.TrimPrefix(relativePath, "/")
+
+		objects = append(objects, &Object{
+			Key:          relativePath,
+			LastModified: info.ModTime().Format(time.RFC3339),
+			Size:         info.Size(),
+			Url:          p.getUrl(relativePath),
+		})
+	}
+
+	return objects, nil
+}
+
+func (p *SftpStorageProvider) PutObject(user string, parent string, key string, fileBuffer *bytes.Buffer) (string, error) {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return "", err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	fullPath := p.getFullPath(key)
+	err = client.MkdirAll(path.Dir(fullPath))
+	if err != nil {
+		return "", err
+	}
+
+	dst, err := client.Create(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, fileBuffer)
+	if err != nil {
+		return "", err
+	}
+
+	return p.getUrl(key), nil
+}
+
+func (p *SftpStorageProvider) removeAll(client *sftp.Client, fullPath string) error {
+	info, err := client.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !info.IsDir() {
+		return client.Remove(fullPath)
+	}
+
+	entries, err := client.ReadDir(fullPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = p.removeAll(client, path.Join(fullPath, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return client.RemoveDirectory(fullPath)
+}
+
+func (p *SftpStorageProvider) DeleteObject(key string) error {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	fullPath := p.getFullPath(key)
+	if strings.HasSuffix(key, "_hidden.ini") && path.Dir(fullPath) != p.getFullPath("") {
+		fullPath = path.Dir(fullPath)
+	}
+	return p.removeAll(client, fullPath)
+}
+
+type sftpObjectReader struct {
+	*sftp.File
+	client    *sftp.Client
+	sshClient *ssh.Client
+}
+
+func (r *sftpObjectReader) Close() error {
+	err := r.File.Close()
+	r.client.Close()
+	r.sshClient.Close()
+	return err
+}
+
+func (p *SftpStorageProvider) GetObject(key string) (io.ReadCloser, error) {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := client.Open(p.getFullPath(key))
+	if err != nil {
+		client.Close()
+		sshClient.Close()
+		return nil, err
+	}
+
+	return &sftpObjectReader{File: file, client: client, sshClient: sshClient}, nil
+}
+
+func (p *SftpStorageProvider) StatObject(key string) (*Object, error) {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	info, err := client.Stat(p.getFullPath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	key = strings.TrimPrefix(key, "/")
+	return &Object{
+		Key:          key,
+		LastModified: info.ModTime().Format(time.RFC3339),
+		Size:         info.Size(),
+		Url:          p.getUrl(key),
+	}, nil
+}
+
+func (p *SftpStorageProvider) CopyObject(srcKey string, dstKey string) error {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	src, err := client.Open(p.getFullPath(srcKey))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := p.getFullPath(dstKey)
+	err = client.MkdirAll(path.Dir(dstPath))
+	if err != nil {
+		return err
+	}
+
+	dst, err := client.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (p *SftpStorageProvider) MoveObject(srcKey string, dstKey string) error {
+	client, sshClient, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+	defer client.Close()
+
+	dstPath := p.getFullPath(dstKey)
+	err = client.MkdirAll(path.Dir(dstPath))
+	if err != nil {
+		return err
+	}
+
+	return client.Rename(p.getFullPath(srcKey), dstPath)
+}
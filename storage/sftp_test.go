@@ -0,0 +1,222 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// newTestSftpServer starts an SFTP server on the local file system, it returns its address and host key fingerprint
+func newTestSftpServer(t *testing.T) (string, string) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() != "admin" || string(password) != "123" {
+				return nil, fmt.Errorf("wrong password")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSftpConn(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), ssh.FingerprintSHA256(signer.PublicKey())
+}
+
+func serveTestSftpConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			for request := range channelRequests {
+				isSftp := request.Type == "subsystem" && string(request.Payload[4:]) == "sftp"
+				request.Reply(isSftp, nil)
+				if !isSftp {
+					continue
+				}
+
+				server, err := sftp.NewServer(channel)
+				if err != nil {
+					channel.Close()
+					return
+				}
+				server.Serve()
+				server.Close()
+			}
+		}()
+	}
+}
+
+func TestSftpStorageProvider(t *testing.T) {
+	addr, fingerprint := newTestSftpServer(t)
+	basePath := t.TempDir()
+
+	p, err := NewSftpStorageProvider(fmt.Sprintf("sftp://%s%s?fingerprint=%s", addr, basePath, url.QueryEscape(fingerprint)), "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.PutObject("admin", "", "docs/a.txt", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.CopyObject("docs/a.txt", "docs/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.MoveObject("docs/b.txt", "other/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := p.ListObjects("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := getObjectKeys(objects)
+	if fmt.Sprint(keys) != "[docs/a.txt other/c.txt]" {
+		t.Fatalf("ListObjects() = %v, want [docs/a.txt other/c.txt]", keys)
+	}
+
+	reader, err := p.GetObject("other/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "hello" {
+		t.Fatalf("GetObject() = %q, want %q", string(content), "hello")
+	}
+
+	err = p.DeleteObject("docs/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	object, err := p.StatObject("docs/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object != nil {
+		t.Fatalf("StatObject() = %v, want nil after deletion", object)
+	}
+}
+
+func TestSftpStorageProviderKeyEscape(t *testing.T) {
+	addr, fingerprint := newTestSftpServer(t)
+	rootPath := t.TempDir()
+	basePath := rootPath + "/store"
+
+	p, err := NewSftpStorageProvider(fmt.Sprintf("sftp://%s%s?fingerprint=%s", addr, basePath, url.QueryEscape(fingerprint)), "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.PutObject("admin", "", "../escape.txt", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(rootPath + "/escape.txt")
+	if !os.IsNotExist(err) {
+		t.Fatalf("PutObject(../escape.txt) wrote above the base path, %v", err)
+	}
+	object, err := p.StatObject("escape.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object == nil {
+		t.Fatal("PutObject(../escape.txt) should write escape.txt under the base path")
+	}
+}
+
+func TestSftpStorageProviderHostKey(t *testing.T) {
+	addr, _ := newTestSftpServer(t)
+	basePath := t.TempDir()
+
+	p, err := NewSftpStorageProvider(fmt.Sprintf("sftp://%s%s", addr, basePath), "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.ListObjects("")
+	if err == nil {
+		t.Fatal("ListObjects() without a fingerprint should fail")
+	}
+
+	p, err = NewSftpStorageProvider(fmt.Sprintf("sftp://%s%s?fingerprint=SHA256:AAAA", addr, basePath), "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.ListObjects("")
+	if err == nil {
+		t.Fatal("ListObjects() with a mismatched fingerprint should fail")
+	}
+
+	_, err = NewSftpStorageProvider(fmt.Sprintf("sftp://%s%s?fingerprint=AAAA", addr, basePath), "admin", "123")
+	if err == nil {
+		t.Fatal("NewSftpStorageProvider() with a fingerprint without SHA256: should fail")
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type WebDavStorageProvider struct {
+	endpoint *url.URL
+	username string
+	password string
+	client   *http.Client
+}
+
+type webDavMultiStatus struct {
+	Responses []webDavResponse `xml:"response"`
+}
+
+type webDavResponse struct {
+	Href     string           `xml:"href"`
+	Propstat []webDavPropstat `xml:"propstat"`
+}
+
+type webDavPropstat struct {
+	Prop   webDavProp `xml:"prop"`
+	Status string     `xml:"status"`
+}
+
+type webDavProp struct {
+	ResourceType     webDavResourceType `xml:"resourcetype"`
+	GetContentLength string             `xml:"getcontentlength"`
+	GetLastModified  string             `xml:"getlastmodified"`
+}
+
+type webDavResourceType struct {
+	Collection *struct{} `xml:"collection"`
+}
+
+const webDavPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop>
+    <d:resourcetype/>
+    <d:getcontentlength/>
+    <d:getlastmodified/>
+  </d:prop>
+</d:propfind>`
+
+func NewWebDavStorageProvider(providerUrl string, username string, password string) (*WebDavStorageProvider, error) {
+	if providerUrl == "" {
+		return nil, fmt.Errorf("the provider URL of WebDAV storage provider should not be empty")
+	}
+
+	endpoint, err := url.Parse(providerUrl)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("the provider URL of WebDAV storage provider should start with http:// or https://, got: %s", providerUrl)
+	}
+
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + "/"
+
+	p := &WebDavStorageProvider{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+	return p, nil
+}
+
+// getUrl resolves the key under the endpoint path, a key with ".." segments can't go above it
+func (p *WebDavStorageProvider) getUrl(key string) string {
+	u := *p.endpoint
+	u.Path = path.Join(p.endpoint.Path, path.Clean("/"+key))
+	if strings.HasSuffix(key, "/") {
+		u.Path += "/"
+	}
+	return u.String()
+}
+
+func (p *WebDavStorageProvider) doRequest(method string, key string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, p.getUrl(key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.username != "" || p.password != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return p.client.Do(req)
+}
+
+func (p *WebDavStorageProvider) propfind(key string) ([]webDavResponse, error) {
+	resp, err := p.doRequest("PROPFIND", key, strings.NewReader(webDavPropfindBody), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return []webDavResponse{}, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("WebDAV PROPFIND %s failed with status: %s", key, resp.Status)
+	}
+
+	var multiStatus webDavMultiStatus
+	err = xml.NewDecoder(resp.Body).Decode(&multiStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return multiStatus.Responses, nil
+}
+
+// getKeyFromHref converts an href returned by the server into a key relative to the endpoint
+func (p *WebDavStorageProvider) getKeyFromHref(href string) string {
+	u, err := url.Parse(href)
+	if err == nil {
+		href = u.Path
+	}
+
+	return strings.TrimPrefix(href, p.endpoint.Path)
+}
+
+func (p *WebDavStorageProvider) listObjects(dir string, objects []*Object) ([]*Object, error) {
+	responses, err := p.propfind(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range responses {
+		key := p.getKeyFromHref(response.Href)
+		if strings.Trim(key, "/") == strings.Trim(dir, "/") {
+			continue
+		}
+
+		var prop *webDavProp
+		for i, propstat := range response.Propstat {
+			if strings.Contains(propstat.Status, " 200 ") {
+				prop = &response.Propstat[i].Prop
+				break
+			}
+		}
+		if prop == nil {
+			continue
+		}
+
+		base := path.Base(strings.TrimSuffix(key, "/"))
+		if prop.ResourceType.Collection != nil {
+			if strings.HasPrefix(base, ".") || base == "node_modules" {
+				continue
+			}
+
+			objects, err = p.listObjects(strings.TrimSuffix(key, "/")+"/", objects)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		size, _ := strconv.ParseInt(prop.GetContentLength, 10, 64)
+		lastModified := prop.GetLastModified
+		modTime, err := http.ParseTime(lastModified)
+		if err == nil {
+			lastModified = modTime.Format(time.RFC3339)
+		}
+
+		objects = append(objects, &Object{
+			Key:          key,
+			LastModified: lastModified,
+			Size:         size,
+			Url:          p.getUrl(key),
+		})
+	}
+
+	return objects, nil
+}
+
+func (p *WebDavStorageProvider) ListObjects(prefix string) ([]*Object, error) {
+	dir := strings.Trim(prefix, "/")
+	if dir != "" {
+		dir += "/"
+	}
+
+	return p.listObjects(dir, []*Object{})
+}
+
+func (p *WebDavStorageProvider) ensureFolderExists(dir string) error {
+	tokens := strings.Split(strings.Trim(dir, "/"), "/")
+	current := ""
+	for _, token := range tokens {
+		if token == "" || token == "." {
+			continue
+		}
+
+		current += token + "/"
+		resp, err := p.doRequest("MKCOL", current, nil, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		// 405 Method Not Allowed means the collection already exists
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("WebDAV MKCOL %s failed with status: %s", current, resp.Status)
+		}
+	}
+	return nil
+}
+
+func (p *WebDavStorageProvider) PutObject(user string, parent string, key string, fileBuffer *bytes.Buffer) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+	err := p.ensureFolderExists(path.Dir(key))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.doRequest("PUT", key, fileBuffer, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("WebDAV PUT %s failed with status: %s", key, resp.Status)
+	}
+
+	return p.getUrl(key), nil
+}
+
+func (p *WebDavStorageProvider) DeleteObject(key string) error {
+	key = strings.TrimPrefix(key, "/")
+	if strings.HasSuffix(key, "_hidden.ini") && path.Dir(key) != "." {
+		key = path.Dir(key) + "/"
+	}
+
+	resp, err := p.doRequest("DELETE", key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("WebDAV DELETE %s failed with status: %s", key, resp.Status)
+	}
+	return nil
+}
+
+func (p *WebDavStorageProvider) GetObject(key string) (io.ReadCloser, error) {
+	resp, err := p.doRequest("GET", strings.TrimPrefix(key, "/"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("WebDAV GET %s failed with status: %s", key, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (p *WebDavStorageProvider) StatObject(key string) (*Object, error) {
+	key = strings.TrimPrefix(key, "/")
+	resp, err := p.doRequest("PROPFIND", key, strings.NewReader(webDavPropfindBody), map[string]string{
+		"Depth":        "0",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("WebDAV PROPFIND %s failed with status: %s", key, resp.Status)
+	}
+
+	var multiStatus webDavMultiStatus
+	err = xml.NewDecoder(resp.Body).Decode(&multiStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range multiStatus.Responses {
+		for _, propstat := range response.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+
+			size, _ := strconv.ParseInt(propstat.Prop.GetContentLength, 10, 64)
+			lastModified := propstat.Prop.GetLastModified
+			modTime, err := http.ParseTime(lastModified)
+			if err == nil {
+				lastModified = modTime.Format(time.RFC3339)
+			}
+
+			return &Object{
+				Key:          key,
+				LastModified: lastModified,
+				Size:         size,
+				Url:          p.getUrl(key),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *WebDavStorageProvider) transferObject(method string, srcKey string, dstKey string) error {
+	srcKey = strings.TrimPrefix(srcKey, "/")
+	dstKey = strings.TrimPrefix(dstKey, "/")
+	err := p.ensureFolderExists(path.Dir(dstKey))
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.doRequest(method, srcKey, nil, map[string]string{
+		"Destination": p.getUrl(dstKey),
+		"Overwrite":   "T",
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("WebDAV %s %s to %s failed with status: %s", method, srcKey, dstKey, resp.Status)
+	}
+	return nil
+}
+
+func (p *WebDavStorageProvider) CopyObject(srcKey string, dstKey string) error {
+	return p.transferObject("COPY", srcKey, dstKey)
+}
+
+func (p *WebDavStorageProvider) MoveObject(srcKey string, dstKey string) error {
+	return p.transferObject("MOVE", srcKey, dstKey)
+}
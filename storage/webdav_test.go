@@ -0,0 +1,220 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+func newTestWebDavServer(t *testing.T) *httptest.Server {
+	handler := &webdav.Handler{
+		Prefix:     "/dav",
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "admin" || password != "123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getObjectKeys(objects []*Object) []string {
+	res := []string{}
+	for _, object := range objects {
+		res = append(res, object.Key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestWebDavStorageProvider(t *testing.T) {
+	server := newTestWebDavServer(t)
+
+	p, err := NewWebDavStorageProvider(server.URL+"/dav/", "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"a.txt":                "hello",
+		"docs/b.md":            "# title",
+		"docs/sub/c.txt":       "world",
+		"folder/_hidden.ini":   "",
+		".git/ignored.txt":     "ignored",
+		"docs/sub/deep/d.docx": "docx",
+	}
+	for key, content := range files {
+		_, err = p.PutObject("admin", "store", key, bytes.NewBufferString(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := p.ListObjects("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := getObjectKeys(objects)
+	expected := []string{"a.txt", "docs/b.md", "docs/sub/c.txt", "docs/sub/deep/d.docx", "folder/_hidden.ini"}
+	if len(keys) != len(expected) {
+		t.Fatalf("ListObjects() = %v, want %v", keys, expected)
+	}
+	for i := range keys {
+		if keys[i] != expected[i] {
+			t.Fatalf("ListObjects() = %v, want %v", keys, expected)
+		}
+	}
+
+	for _, object := range objects {
+		if object.Key == "docs/b.md" && object.Size != int64(len("# title")) {
+			t.Errorf("size of %s = %d, want %d", object.Key, object.Size, len("# title"))
+		}
+	}
+
+	objects, err = p.ListObjects("docs/sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("ListObjects(docs/sub) = %v, want 2 objects", getObjectKeys(objects))
+	}
+
+	err = p.DeleteObject("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.DeleteObject("folder/_hidden.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err = p.ListObjects("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = getObjectKeys(objects)
+	expected = []string{"docs/b.md", "docs/sub/c.txt", "docs/sub/deep/d.docx"}
+	if len(keys) != len(expected) {
+		t.Fatalf("ListObjects() after delete = %v, want %v", keys, expected)
+	}
+}
+
+func TestWebDavStorageProviderUnauthorized(t *testing.T) {
+	server := newTestWebDavServer(t)
+
+	p, err := NewWebDavStorageProvider(server.URL+"/dav", "admin", "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.ListObjects("")
+	if err == nil {
+		t.Fatal("ListObjects() with wrong password should fail")
+	}
+}
+
+func TestWebDavStorageProviderMoveObject(t *testing.T) {
+	server := newTestWebDavServer(t)
+
+	p, err := NewWebDavStorageProvider(server.URL+"/dav", "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.PutObject("admin", "store", "docs/a.txt", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.CopyObject("docs/a.txt", "backup/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.MoveObject("docs/a.txt", "archive/2025/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := p.StatObject("docs/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object != nil {
+		t.Fatalf("StatObject(docs/a.txt) = %v, want nil after move", object)
+	}
+
+	object, err = p.StatObject("archive/2025/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object == nil || object.Size != int64(len("hello")) {
+		t.Fatalf("StatObject(archive/2025/b.txt) = %v, want size %d", object, len("hello"))
+	}
+
+	for _, key := range []string{"archive/2025/b.txt", "backup/a.txt"} {
+		reader, err := p.GetObject(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "hello" {
+			t.Errorf("GetObject(%s) = %q, want %q", key, string(content), "hello")
+		}
+	}
+}
+
+func TestWebDavStorageProviderKeyEscape(t *testing.T) {
+	server := newTestWebDavServer(t)
+
+	p, err := NewWebDavStorageProvider(server.URL+"/dav/store", "admin", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.PutObject("admin", "store", "../../escape.txt", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"escape.txt", "../escape.txt"} {
+		object, err := p.StatObject(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if object == nil {
+			t.Fatalf("StatObject(%s) = nil, want escape.txt under the endpoint path", key)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package txt
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -42,7 +43,27 @@ func GetParsedTextFromUrl(url string, ext string) (string, error) {
 		}()
 	}
 
+	return getParsedTextFromPath(path, ext)
+}
+
+func GetParsedTextFromReader(reader io.Reader, ext string) (string, error) {
+	path, err := getTempFilePathFromReader(reader, ext)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = os.Remove(path)
+		if err != nil {
+			fmt.Printf("%v\n", err.Error())
+		}
+	}()
+
+	return getParsedTextFromPath(path, ext)
+}
+
+func getParsedTextFromPath(path string, ext string) (string, error) {
 	var res string
+	var err error
 	if ext == "" || ext == ".txt" || ext == ".md" || ext == ".yaml" {
 		res, err = getTextFromPlain(path)
 	} else if ext == ".csv" {
@@ -40,3 +40,18 @@ func getTempFilePathFromUrl(url string) (string, error) {
 
 	return file.Name(), nil
 }
+
+func getTempFilePathFromReader(reader io.Reader, ext string) (string, error) {
+	file, err := ioutil.TempFile("", "casibase-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	if err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
@@ -81,3 +81,12 @@ func AdjustTimeWithMilli(timeStr string, offsetMs int) string {
 func GetCurrentUnixTime() int64 {
 	return time.Now().Unix()
 }
+
+// GetUnixTimeFromString returns the Unix timestamp in seconds of an RFC 3339 time, or 0 if it cannot be parsed
+func GetUnixTimeFromString(timeStr string) int64 {
+	t, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -16,7 +16,7 @@ import React, {Component} from "react";
 import {Link, Redirect, Route, Switch, withRouter} from "react-router-dom";
 import {StyleProvider, legacyLogicalPropertiesTransformer} from "@ant-design/cssinjs";
 import {Avatar, Button, Card, ConfigProvider, Drawer, Dropdown, FloatButton, Layout, Menu, Result} from "antd";
-import {AppstoreTwoTone, BarsOutlined, BulbTwoTone, CloudTwoTone, CommentOutlined, DownOutlined, HomeTwoTone, LockTwoTone, LoginOutlined, LogoutOutlined, SettingOutlined, SettingTwoTone, VideoCameraTwoTone, WalletTwoTone} from "@ant-design/icons";
+import {AppstoreTwoTone, BarsOutlined, BookOutlined, BulbTwoTone, CloudTwoTone, CommentOutlined, DownOutlined, HomeTwoTone, LockTwoTone, LoginOutlined, LogoutOutlined, SettingOutlined, SettingTwoTone, VideoCameraTwoTone, WalletTwoTone} from "@ant-design/icons";
 import "./App.less";
 import {Helmet} from "react-helmet";
 import * as Setting from "./Setting";
@@ -31,6 +31,7 @@ import VideoListPage from "./VideoListPage";
 import VideoEditPage from "./VideoEditPage";
 import VideoPage from "./VideoPage";
 import PublicVideoListPage from "./basic/PublicVideoListPage";
+import SharedChatPage from "./SharedChatPage";
 import ProviderListPage from "./ProviderListPage";
 import ProviderEditPage from "./ProviderEditPage";
 import VectorListPage from "./VectorListPage";
@@ -46,6 +47,11 @@ import MessageListPage from "./MessageListPage";
 import MessageEditPage from "./MessageEditPage";
 import GraphListPage from "./GraphListPage";
 import GraphEditPage from "./GraphEditPage";
+import PricingListPage from "./PricingListPage";
+import PricingEditPage from "./PricingEditPage";
+import ExchangeRateListPage from "./ExchangeRateListPage";
+import ExchangeRateEditPage from "./ExchangeRateEditPage";
+import MemoryListPage from "./MemoryListPage";
 import NodeListPage from "./NodeListPage";
 import NodeEditPage from "./NodeEditPage";
 import MachineListPage from "./MachineListPage";
@@ -195,6 +201,12 @@ class App extends Component {
       this.setState({selectedMenuKey: "/providers"});
     } else if (uri.includes("/vectors")) {
       this.setState({selectedMenuKey: "/vectors"});
+    } else if (uri.includes("/memories")) {
+      this.setState({selectedMenuKey: "/memories"});
+    } else if (uri.includes("/pricings")) {
+      this.setState({selectedMenuKey: "/pricings"});
+    } else if (uri.includes("/exchange-rates")) {
+      this.setState({selectedMenuKey: "/exchange-rates"});
     } else if (uri.includes("/chats")) {
       this.setState({selectedMenuKey: "/chats"});
     } else if (uri.includes("/messages")) {
@@ -418,6 +430,9 @@ class App extends Component {
       items.push(Setting.getItem(<><CommentOutlined />&nbsp;&nbsp;{i18next.t("general:Chats & Messages")}</>,
         "/chat"
       ));
+      items.push(Setting.getItem(<><BookOutlined />&nbsp;&nbsp;{i18next.t("general:Memories")}</>,
+        "/memories"
+      ));
       items.push(Setting.getItem(<><LogoutOutlined />&nbsp;&nbsp;{i18next.t("account:Sign Out")}</>,
         "/logout"
       ));
@@ -433,6 +448,8 @@ class App extends Component {
         this.signout();
       } else if (e.key === "/chat") {
         this.props.history.push("/chat");
+      } else if (e.key === "/memories") {
+        this.props.history.push("/memories");
       } else if (e.key === "/login") {
         this.props.history.push(window.location.pathname);
         Setting.redirectToLogin();
@@ -622,12 +639,15 @@ class App extends Component {
       res.push(Setting.getItem(<Link style={{color: textColor}} to="/chats">{i18next.t("general:Chats & Messages")}</Link>, "/ai-chat", <BulbTwoTone twoToneColor={twoToneColor} />, [
         Setting.getItem(<Link to="/chats">{i18next.t("general:Chats")}</Link>, "/chats"),
         Setting.getItem(<Link to="/messages">{i18next.t("general:Messages")}</Link>, "/messages"),
+        Setting.getItem(<Link to="/memories">{i18next.t("general:Memories")}</Link>, "/memories"),
       ]));
 
       res.push(Setting.getItem(<Link style={{color: textColor}} to="/stores">{i18next.t("general:AI Setting")}</Link>, "/ai-setting", <AppstoreTwoTone twoToneColor={twoToneColor} />, [
         Setting.getItem(<Link to="/stores">{i18next.t("general:Stores")}</Link>, "/stores"),
         Setting.getItem(<Link to="/providers">{i18next.t("general:Providers")}</Link>, "/providers"),
         Setting.getItem(<Link to="/vectors">{i18next.t("general:Vectors")}</Link>, "/vectors"),
+        Setting.getItem(<Link to="/pricings">{i18next.t("general:Pricings")}</Link>, "/pricings"),
+        Setting.getItem(<Link to="/exchange-rates">{i18next.t("general:Exchange rates")}</Link>, "/exchange-rates"),
       ]));
 
       res.push(Setting.getItem(<Link style={{color: textColor}} to="/nodes">{i18next.t("general:Cloud Resources")}</Link>, "/cloud", <CloudTwoTone twoToneColor={twoToneColor} />, [
@@ -743,10 +763,16 @@ class App extends Component {
         <Route exact path="/videos/:owner/:videoName" render={(props) => this.renderSigninIfNotSignedIn(<VideoEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/public-videos" render={(props) => <PublicVideoListPage {...props} />} />
         <Route exact path="/public-videos/:owner/:videoName" render={(props) => <VideoPage account={this.state.account} {...props} />} />
+        <Route exact path="/share/:shareId" render={(props) => <SharedChatPage {...props} />} />
         <Route exact path="/providers" render={(props) => this.renderSigninIfNotSignedIn(<ProviderListPage account={this.state.account} {...props} />)} />
         <Route exact path="/providers/:providerName" render={(props) => this.renderSigninIfNotSignedIn(<ProviderEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/vectors" render={(props) => this.renderSigninIfNotSignedIn(<VectorListPage account={this.state.account} {...props} />)} />
         <Route exact path="/vectors/:vectorName" render={(props) => this.renderSigninIfNotSignedIn(<VectorEditPage account={this.state.account} {...props} />)} />
+        <Route exact path="/memories" render={(props) => this.renderSigninIfNotSignedIn(<MemoryListPage account={this.state.account} {...props} />)} />
+        <Route exact path="/pricings" render={(props) => this.renderSigninIfNotSignedIn(<PricingListPage account={this.state.account} {...props} />)} />
+        <Route exact path="/pricings/:pricingName" render={(props) => this.renderSigninIfNotSignedIn(<PricingEditPage account={this.state.account} {...props} />)} />
+        <Route exact path="/exchange-rates" render={(props) => this.renderSigninIfNotSignedIn(<ExchangeRateListPage account={this.state.account} {...props} />)} />
+        <Route exact path="/exchange-rates/:exchangeRateName" render={(props) => this.renderSigninIfNotSignedIn(<ExchangeRateEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/chats" render={(props) => this.renderSigninIfNotSignedIn(<ChatListPage account={this.state.account} {...props} />)} />
         <Route exact path="/chats/:chatName" render={(props) => this.renderSigninIfNotSignedIn(<ChatEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/messages" render={(props) => this.renderSigninIfNotSignedIn(<MessageListPage account={this.state.account} {...props} />)} />
@@ -808,7 +834,7 @@ class App extends Component {
     if (uri === undefined) {
       uri = this.state.uri;
     }
-    const hiddenPaths = ["/workbench", "/access"];
+    const hiddenPaths = ["/workbench", "/access", "/share/"];
     for (const path of hiddenPaths) {
       if (uri.startsWith(path)) {
         return true;
@@ -317,6 +317,22 @@ class ChatBox extends React.Component {
       });
   };
 
+  handleSwitchBranch = (message, siblingName) => {
+    MessageBackend.switchMessageBranch({owner: message.owner, name: siblingName})
+      .then((res) => {
+        if (res.status === "ok") {
+          if (this.props.onMessageEdit) {
+            this.props.onMessageEdit(message.chat);
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  };
+
   render() {
     let messages = this.props.messages;
     if (messages === null) {
@@ -346,6 +362,7 @@ class ChatBox extends React.Component {
             onCopyMessage={this.copyMessageFromHTML}
             onToggleRead={this.toggleMessageReadState}
             onEditMessage={this.handleEditMessage}
+            onSwitchBranch={this.props.onMessageEdit ? this.handleSwitchBranch : null}
             previewMode={this.props.previewMode}
             hideInput={this.props.hideInput}
             disableInput={this.props.disableInput}
@@ -13,8 +13,8 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Dropdown, Input, Menu, Popconfirm, Tooltip} from "antd";
-import {CloseOutlined, DeleteOutlined, EditOutlined, LayoutOutlined, PlusOutlined, SaveOutlined} from "@ant-design/icons";
+import {Button, Dropdown, Input, Menu, Popconfirm, Tooltip, Upload} from "antd";
+import {CloseOutlined, DeleteOutlined, DownloadOutlined, EditOutlined, ImportOutlined, LayoutOutlined, PlusOutlined, SaveOutlined, ShareAltOutlined} from "@ant-design/icons";
 import i18next from "i18next";
 import {ThemeDefault} from "./Conf";
 
@@ -110,7 +110,7 @@ class ChatMenu extends React.Component {
                   />
                 </div>) : (
                 <div className="menu-item-container">
-                  <div style={{width: "70%", overflow: "hidden"}}>
+                  <div style={{width: isSelected ? "55%" : "70%", overflow: "hidden"}}>
                     <Tooltip title={chat.displayName}>{chat.displayName}</Tooltip>
                   </div>
                   {isSelected && (
@@ -127,6 +127,33 @@ class ChatMenu extends React.Component {
                             editChat: true,
                           });
                         }} />
+                      {this.props.onExportChat && (
+                        <Dropdown trigger={["click"]} menu={{
+                          items: ["Markdown", "JSON", "HTML"].map(format => ({key: format, label: format})),
+                          onClick: ({key, domEvent}) => {
+                            domEvent.stopPropagation();
+                            this.props.onExportChat(globalChatIndex, key);
+                          },
+                        }}>
+                          <DownloadOutlined className="menu-item-icon"
+                            onMouseEnter={handleIconMouseEnter}
+                            onMouseLeave={handleIconMouseLeave}
+                            onMouseDown={handleIconMouseDown}
+                            onMouseUp={handleIconMouseUp}
+                            onClick={(e) => e.stopPropagation()} />
+                        </Dropdown>
+                      )}
+                      {this.props.onShareChat && (
+                        <ShareAltOutlined className="menu-item-icon"
+                          onMouseEnter={handleIconMouseEnter}
+                          onMouseLeave={handleIconMouseLeave}
+                          onMouseDown={handleIconMouseDown}
+                          onMouseUp={handleIconMouseUp}
+                          onClick={(e) => {
+                            e.stopPropagation();
+                            this.props.onShareChat(globalChatIndex);
+                          }} />
+                      )}
                       <Popconfirm
                         title={`${i18next.t("general:Sure to delete")}: ${chat.displayName} ?`}
                         onConfirm={() => {
@@ -307,12 +334,34 @@ class ChatMenu extends React.Component {
     );
   }
 
+  renderImportChatButton() {
+    return (
+      <Upload accept=".json" showUploadList={false} beforeUpload={(file) => {
+        const reader = new FileReader();
+        reader.onload = (e) => {
+          this.props.onImportChat(e.target.result);
+        };
+        reader.readAsText(file);
+        return false;
+      }}>
+        <Tooltip title={i18next.t("chat:Import chat")}>
+          <Button icon={<ImportOutlined />} style={{height: "40px", margin: "4px 4px 4px 0", borderColor: "rgb(229,229,229)"}} />
+        </Tooltip>
+      </Upload>
+    );
+  }
+
   render() {
     const items = this.chatsToItems(this.props.chats, this.props.currentStoreName);
 
     return (
       <div>
-        {this.renderAddChatButton(this.props.stores, this.props.currentStoreName)}
+        <div style={{display: "flex", alignItems: "center"}}>
+          <div style={{flex: 1}}>
+            {this.renderAddChatButton(this.props.stores, this.props.currentStoreName)}
+          </div>
+          {this.props.onImportChat && this.renderImportChatButton()}
+        </div>
         <div style={{marginRight: "4px"}}>
           <Menu
             style={{maxHeight: "calc(100vh - 140px - 40px - 8px)", overflowY: "auto"}}
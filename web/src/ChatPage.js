@@ -29,6 +29,8 @@ import i18next from "i18next";
 import BaseListPage from "./BaseListPage";
 import * as Conf from "./Conf";
 import {MessageCarrier} from "./chat/MessageCarrier";
+import ChatShareModal from "./chat/ChatShareModal";
+import FileSaver from "file-saver";
 
 class ChatPage extends BaseListPage {
   constructor(props) {
@@ -248,16 +250,48 @@ class ChatPage extends BaseListPage {
     return message;
   }
 
+  showToolApproval(message, approval) {
+    const decide = (approved) => {
+      return MessageBackend.approveToolCall(message.owner, message.name, approval.toolCallId, approved)
+        .then((res) => {
+          if (res.status !== "ok") {
+            Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+          }
+        })
+        .catch(error => {
+          Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+        });
+    };
+
+    let args = approval.arguments;
+    try {
+      args = JSON.stringify(JSON.parse(approval.arguments), null, 2);
+    } catch (e) {
+      // The arguments are shown as they are
+    }
+
+    Modal.confirm({
+      title: `${i18next.t("chat:Approval required")}: ${approval.toolName}`,
+      content: <pre style={{maxHeight: "400px", overflow: "auto", whiteSpace: "pre-wrap"}}>{args}</pre>,
+      okText: i18next.t("chat:Approve"),
+      cancelText: i18next.t("chat:Reject"),
+      onOk: () => decide(true),
+      onCancel: () => decide(false),
+    });
+  }
+
   cancelMessage = () => {
     if (this.state.messages && this.state.messages.length > 0) {
       const lastMessage = this.state.messages[this.state.messages.length - 1];
       if (lastMessage.author === "AI" && this.state.messageLoading) {
-        MessageBackend.closeMessageEventSource(lastMessage.owner, lastMessage.name);
-
-        MessageBackend.updateMessage(lastMessage.owner, lastMessage.name, lastMessage)
+        // The server cancels the generation and saves the partial answer itself
+        MessageBackend.stopMessageAnswer(lastMessage.owner, lastMessage.name)
           .then((res) => {
+            MessageBackend.closeMessageEventSource(lastMessage.owner, lastMessage.name);
             if (res.status === "ok") {
+              lastMessage.state = "Stopped";
               this.setState({
+                messages: [...this.state.messages],
                 messageLoading: false,
               });
             } else {
@@ -345,6 +379,8 @@ class ChatPage extends BaseListPage {
               return;
             }
             const mssageCarrier = new MessageCarrier(chat.needTitle);
+            // The agent steps of the answer come one by one, each copy of the last message takes them from here
+            lastMessage.agentSteps = [];
             MessageBackend.getMessageAnswer(lastMessage.owner, lastMessage.name, (data) => {
               const jsonData = JSON.parse(data);
 
@@ -462,6 +498,20 @@ class ChatPage extends BaseListPage {
                   this.chatBox.current.toggleMessageReadState(lastMessage2);
                 }
               }
+            }, (data) => {
+              this.showToolApproval(lastMessage, JSON.parse(data));
+            }, (data) => {
+              if (!chat || (this.state.chat.name !== chat.name)) {
+                return;
+              }
+
+              lastMessage.agentSteps = [...lastMessage.agentSteps, JSON.parse(data)];
+              const lastMessage2 = Setting.deepCopy(res.data[res.data.length - 1]);
+              lastMessage2.agentSteps = lastMessage.agentSteps;
+              res.data[res.data.length - 1] = lastMessage2;
+              this.setState({
+                messages: res.data,
+              });
             });
           } else {
             this.setState({
@@ -559,6 +609,55 @@ class ChatPage extends BaseListPage {
       });
   }
 
+  exportChat(chat, format) {
+    ChatBackend.exportChat(chat.owner, chat.name, format)
+      .then((res) => {
+        if (res.status === "ok") {
+          const types = {"Markdown": "text/markdown", "JSON": "application/json", "HTML": "text/html"};
+          const blob = new Blob([res.data], {type: `${types[format]};charset=utf-8`});
+          FileSaver.saveAs(blob, res.data2);
+        } else {
+          Setting.showMessage("error", `${i18next.t("chat:Failed to export")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  }
+
+  importChat(text) {
+    ChatBackend.importChat(text, this.state.storeName ?? "")
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("chat:Successfully imported"));
+          const newChat = res.data;
+          this.goToLinkSoft(this.generateChatUrl(newChat.name, newChat.store));
+          this.setState({
+            chat: newChat,
+            messages: null,
+            messageError: false,
+          });
+          this.getMessages(newChat);
+
+          this.fetch({}, false);
+        } else {
+          Setting.showMessage("error", `${i18next.t("chat:Failed to import")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  }
+
+  handleChatShared = (sharedChat) => {
+    const update = (c) => (c.name === sharedChat.name ? {...c, shareId: sharedChat.shareId, shareExpireTime: sharedChat.shareExpireTime} : c);
+    this.setState(prevState => ({
+      data: prevState.data.map(update),
+      chat: prevState.chat ? update(prevState.chat) : prevState.chat,
+      shareChat: update(prevState.shareChat),
+    }));
+  };
+
   handleMessageEdit = (chatName) => {
     const chat = this.state.data.find(c => c.name === chatName);
     if (chat) {
@@ -673,6 +772,18 @@ class ChatPage extends BaseListPage {
       this.updateChatName(chats, i, chat, newName);
     };
 
+    const onExportChat = (i, format) => {
+      this.exportChat(chats[i], format);
+    };
+
+    const onShareChat = (i) => {
+      this.setState({shareChat: chats[i]});
+    };
+
+    const onImportChat = (text) => {
+      this.importChat(text);
+    };
+
     const currentStoreName = this.state.storeName;
 
     if (this.state.loading) {
@@ -691,10 +802,13 @@ class ChatPage extends BaseListPage {
         {
           this.renderUnsafePasswordModal()
         }
+        <ChatShareModal chat={this.state.shareChat} open={!!this.state.shareChat}
+          onClose={() => this.setState({shareChat: undefined})}
+          onChatShared={this.handleChatShared} />
         {
           !(Setting.isMobile() || Setting.getUrlParam("isRaw") !== null) && !this.state.chatMenuCollapsed && (
             <div style={{width: "250px", height: "100%", marginRight: "2px"}}>
-              <ChatMenu ref={this.menu} chats={chats} chatName={this.getChat()} onSelectChat={onSelectChat} onAddChat={onAddChat} onDeleteChat={onDeleteChat} onUpdateChatName={onUpdateChatName} stores={this.state.stores} currentStoreName={currentStoreName} />
+              <ChatMenu ref={this.menu} chats={chats} chatName={this.getChat()} onSelectChat={onSelectChat} onAddChat={onAddChat} onDeleteChat={onDeleteChat} onUpdateChatName={onUpdateChatName} onExportChat={onExportChat} onShareChat={onShareChat} onImportChat={onImportChat} stores={this.state.stores} currentStoreName={currentStoreName} />
             </div>
           )
         }
@@ -702,7 +816,7 @@ class ChatPage extends BaseListPage {
         {Setting.isMobile() && (
           <Drawer title={i18next.t("chat:Chats")} placement="left" open={this.state.chatMenuVisible} onClose={this.closeChatMenu} width={250}
           >
-            <ChatMenu ref={this.menu} chats={chats} chatName={this.getChat()} onSelectChat={onSelectChat} onAddChat={onAddChat} onDeleteChat={onDeleteChat} onUpdateChatName={onUpdateChatName} stores={this.state.stores} />
+            <ChatMenu ref={this.menu} chats={chats} chatName={this.getChat()} onSelectChat={onSelectChat} onAddChat={onAddChat} onDeleteChat={onDeleteChat} onUpdateChatName={onUpdateChatName} onExportChat={onExportChat} onShareChat={onShareChat} onImportChat={onImportChat} stores={this.state.stores} />
           </Drawer>
         )}
 
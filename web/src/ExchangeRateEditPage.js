@@ -0,0 +1,171 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Card, Col, Input, InputNumber, Row, Select} from "antd";
+import * as ExchangeRateBackend from "./backend/ExchangeRateBackend";
+import * as Setting from "./Setting";
+import i18next from "i18next";
+
+const {Option} = Select;
+
+class ExchangeRateEditPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+      exchangeRateName: props.match.params.exchangeRateName,
+      exchangeRate: null,
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getExchangeRate();
+  }
+
+  getExchangeRate() {
+    ExchangeRateBackend.getExchangeRate("admin", this.state.exchangeRateName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            exchangeRate: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  parseExchangeRateField(key, value) {
+    if (["rate"].includes(key)) {
+      value = Setting.myParseFloat(value);
+    }
+    return value;
+  }
+
+  updateExchangeRateField(key, value) {
+    value = this.parseExchangeRateField(key, value);
+
+    const exchangeRate = this.state.exchangeRate;
+    exchangeRate[key] = value;
+    this.setState({
+      exchangeRate: exchangeRate,
+    });
+  }
+
+  renderExchangeRate() {
+    return (
+      <Card size="small" title={
+        <div>
+          {i18next.t("exchangeRate:Edit Exchange Rate")}&nbsp;&nbsp;&nbsp;&nbsp;
+          <Button onClick={() => this.submitExchangeRateEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitExchangeRateEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+        </div>
+      } style={{marginLeft: "5px"}} type="inner">
+        <Row style={{marginTop: "10px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Name"), i18next.t("general:Name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.exchangeRate.name} onChange={e => {
+              this.updateExchangeRateField("name", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("provider:Currency"), i18next.t("provider:Currency - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.exchangeRate.currency} onChange={(value => {
+              this.updateExchangeRateField("currency", value);
+            })}>
+              {
+                Setting.getCurrencyOptions().map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("exchangeRate:Target currency"), i18next.t("exchangeRate:Target currency - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.exchangeRate.targetCurrency} onChange={(value => {
+              this.updateExchangeRateField("targetCurrency", value);
+            })}>
+              {
+                Setting.getCurrencyOptions().map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("exchangeRate:Rate"), i18next.t("exchangeRate:Rate - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={0} value={this.state.exchangeRate.rate} onChange={value => {
+              this.updateExchangeRateField("rate", value);
+            }} />
+          </Col>
+        </Row>
+      </Card>
+    );
+  }
+
+  submitExchangeRateEdit(exitAfterSave) {
+    const exchangeRate = Setting.deepCopy(this.state.exchangeRate);
+    ExchangeRateBackend.updateExchangeRate(this.state.exchangeRate.owner, this.state.exchangeRateName, exchangeRate)
+      .then((res) => {
+        if (res.status === "ok") {
+          if (res.data) {
+            Setting.showMessage("success", i18next.t("general:Successfully saved"));
+            this.setState({
+              exchangeRateName: this.state.exchangeRate.name,
+            });
+            if (exitAfterSave) {
+              this.props.history.push("/exchange-rates");
+            } else {
+              this.props.history.push(`/exchange-rates/${this.state.exchangeRate.name}`);
+            }
+          } else {
+            Setting.showMessage("error", i18next.t("general:Failed to save"));
+            this.updateExchangeRateField("name", this.state.exchangeRateName);
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${error}`);
+      });
+  }
+
+  render() {
+    return (
+      <div>
+        {
+          this.state.exchangeRate !== null ? this.renderExchangeRate() : null
+        }
+        <div style={{marginTop: "20px", marginLeft: "40px"}}>
+          <Button size="large" onClick={() => this.submitExchangeRateEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" size="large" onClick={() => this.submitExchangeRateEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+        </div>
+      </div>
+    );
+  }
+}
+
+export default ExchangeRateEditPage;
@@ -15,7 +15,7 @@
 import React from "react";
 import {withRouter} from "react-router-dom";
 import {Button, Card, Col, DatePicker, Descriptions, Empty, Input, Modal, Popconfirm, Radio, Result, Row, Select, Spin, Tooltip, Tree, Upload} from "antd";
-import {CloudUploadOutlined, DeleteOutlined, DownloadOutlined, FileDoneOutlined, FolderAddOutlined, InfoCircleTwoTone, createFromIconfontCN} from "@ant-design/icons";
+import {CloudUploadOutlined, DeleteOutlined, DownloadOutlined, EditOutlined, FileDoneOutlined, FolderAddOutlined, InfoCircleTwoTone, createFromIconfontCN} from "@ant-design/icons";
 import moment from "moment";
 import * as Setting from "./Setting";
 import * as FileBackend from "./backend/FileBackend";
@@ -55,6 +55,7 @@ class FileTree extends React.Component {
       loading: false,
       text: null,
       newFolder: null,
+      newKey: null,
       permissions: null,
       permissionMap: null,
       searchValue: "",
@@ -87,7 +88,7 @@ class FileTree extends React.Component {
     PermissionBackend.getPermissions(Conf.AuthConfig.organizationName)
       .then((res) => {
         if (res.status === "ok") {
-          const permissions = res.data.filter(permission => (permission.domains[0] === this.props.store.name) && permission.users.length !== 0);
+          const permissions = res.data.filter(permission => (permission.domains[0] === this.props.store.name) && this.getPermissionSubjects(permission).length !== 0);
           this.setState({
             permissions: permissions,
             permissionMap: this.getPermissionMap(permissions),
@@ -230,6 +231,66 @@ class FileTree extends React.Component {
       });
   }
 
+  moveFile(file, newKey, isLeaf) {
+    const storeId = `${this.props.store.owner}/${this.props.store.name}`;
+    FileBackend.moveFile(storeId, file.key, newKey, isLeaf)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully saved"));
+          this.setState({
+            newKey: null,
+          });
+          this.props.onRefresh();
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${error}`);
+      });
+  }
+
+  renderMoveButton(file, isLeaf) {
+    return (
+      <Tooltip color={"rgb(255,255,255)"} placement="top" title={
+        <span onClick={(e) => e.stopPropagation()}>
+          <div style={{color: "black"}}>
+            {i18next.t("store:Move")}:
+          </div>
+          <Input.Group style={{marginTop: "5px"}} compact>
+            <Input style={{width: "200px"}} placeholder={file.key} value={this.state.newKey} onChange={e => {
+              this.setState({
+                newKey: e.target.value,
+              });
+            }} />
+            <Button type="primary" onClick={(e) => {
+              this.moveFile(file, this.state.newKey, isLeaf);
+              e.stopPropagation();
+            }}
+            >
+              OK
+            </Button>
+          </Input.Group>
+        </span>
+      }>
+        <span onClick={(e) => e.stopPropagation()}>
+          <Button style={{marginRight: "5px"}} icon={<EditOutlined />} size="small" onClick={(e) => {
+            e.stopPropagation();
+          }} />
+        </span>
+      </Tooltip>
+    );
+  }
+
+  getPermissionSubjects(permission) {
+    // Groups and roles are ACL subjects as well, they are shown with their full "owner/name" ids
+    return [
+      ...(permission.users ?? []).map(user => user.split("/")[1]),
+      ...(permission.groups ?? []),
+      ...(permission.roles ?? []),
+    ];
+  }
+
   renderPermission(permission, isReadable) {
     if (!isReadable) {
       const userId = `${this.props.account.owner}/${this.props.account.name}`;
@@ -246,12 +307,11 @@ class FileTree extends React.Component {
         }}
       >
         {
-          permission.users.map(user => {
-            const username = user.split("/")[1];
+          this.getPermissionSubjects(permission).map(subject => {
             return (
-              <span key={username}>
+              <span key={subject}>
                 {
-                  Setting.getTag(username, permission.actions[0], permission.state)
+                  Setting.getTag(subject, permission.actions[0], permission.state)
                 }
               </span>
             );
@@ -475,18 +535,7 @@ class FileTree extends React.Component {
                   {
                     !isWritable ? null : (
                       <React.Fragment>
-                        {/* <Tooltip title={i18next.t("store:Rename")}>*/}
-                        {/*  <Button style={{marginRight: "5px"}} icon={<EditOutlined />} size="small" onClick={(e) => {*/}
-                        {/*    Setting.showMessage("error", "Rename");*/}
-                        {/*    e.stopPropagation();*/}
-                        {/*  }} />*/}
-                        {/* </Tooltip>*/}
-                        {/* <Tooltip title={i18next.t("store:Move")}>*/}
-                        {/*  <Button style={{marginRight: "5px"}} icon={<RadiusSettingOutlined />} size="small" onClick={(e) => {*/}
-                        {/*    Setting.showMessage("error", "Move");*/}
-                        {/*    e.stopPropagation();*/}
-                        {/*  }} />*/}
-                        {/* </Tooltip>*/}
+                        {this.renderMoveButton(file, true)}
                         <Tooltip title={i18next.t("general:Delete")}>
                           <span onClick={(e) => e.stopPropagation()}>
                             <Popconfirm
@@ -575,6 +624,9 @@ class FileTree extends React.Component {
                             </Upload>
                           </span>
                         </Tooltip>
+                        {
+                          file.key === "/" ? null : this.renderMoveButton(file, false)
+                        }
                         {
                           file.key === "/" ? null : (
                             <Tooltip title={i18next.t("general:Delete")}>
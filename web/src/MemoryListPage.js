@@ -0,0 +1,167 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Popconfirm, Table} from "antd";
+import {DeleteOutlined} from "@ant-design/icons";
+import BaseListPage from "./BaseListPage";
+import * as Setting from "./Setting";
+import * as MemoryBackend from "./backend/MemoryBackend";
+import i18next from "i18next";
+
+class MemoryListPage extends BaseListPage {
+  constructor(props) {
+    super(props);
+  }
+
+  deleteItem = async(i) => {
+    return MemoryBackend.deleteMemory(this.state.data[i]);
+  };
+
+  deleteMemory(record) {
+    MemoryBackend.deleteMemory(record)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully deleted"));
+          this.setState({
+            data: this.state.data.filter((item) => item.name !== record.name),
+            pagination: {
+              ...this.state.pagination,
+              total: this.state.pagination.total - 1,
+            },
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to delete")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to delete")}: ${error}`);
+      });
+  }
+
+  renderTable(memories) {
+    const columns = [
+      {
+        title: i18next.t("general:Text"),
+        dataIndex: "text",
+        key: "text",
+        sorter: (a, b) => a.text.localeCompare(b.text),
+        ...this.getColumnSearchProps("text"),
+      },
+      {
+        title: i18next.t("general:Store"),
+        dataIndex: "store",
+        key: "store",
+        width: "150px",
+        sorter: (a, b) => a.store.localeCompare(b.store),
+      },
+      {
+        title: i18next.t("general:Chat"),
+        dataIndex: "chat",
+        key: "chat",
+        width: "150px",
+        sorter: (a, b) => a.chat.localeCompare(b.chat),
+      },
+      {
+        title: i18next.t("general:Updated time"),
+        dataIndex: "updatedTime",
+        key: "updatedTime",
+        width: "200px",
+        sorter: (a, b) => a.updatedTime.localeCompare(b.updatedTime),
+        render: (text, record, index) => {
+          return Setting.getFormattedDate(text);
+        },
+      },
+      {
+        title: i18next.t("general:Action"),
+        dataIndex: "action",
+        key: "action",
+        width: "100px",
+        fixed: (Setting.isMobile()) ? "false" : "right",
+        render: (text, record, index) => {
+          return (
+            <div>
+              <Popconfirm
+                title={`${i18next.t("general:Sure to delete")}: ${record.text} ?`}
+                onConfirm={() => this.deleteMemory(record)}
+                okText={i18next.t("general:OK")}
+                cancelText={i18next.t("general:Cancel")}
+              >
+                <Button style={{marginTop: "10px", marginBottom: "10px"}} type="primary" danger>{i18next.t("general:Delete")}</Button>
+              </Popconfirm>
+            </div>
+          );
+        },
+      },
+    ];
+
+    const paginationProps = {
+      total: this.state.pagination.total,
+      showQuickJumper: true,
+      showSizeChanger: true,
+      pageSizeOptions: ["10", "20", "50", "100", "1000", "10000", "100000"],
+      showTotal: () => i18next.t("general:{total} in total").replace("{total}", this.state.pagination.total),
+    };
+
+    return (
+      <div>
+        <Table scroll={{x: "max-content"}} columns={columns} dataSource={memories} rowKey="name" rowSelection={this.getRowSelection()} size="middle" bordered pagination={paginationProps}
+          title={() => (
+            <div>
+              {i18next.t("general:Memories")}&nbsp;&nbsp;&nbsp;&nbsp;
+              {this.state.selectedRowKeys.length > 0 && (
+                <Popconfirm title={`${i18next.t("general:Sure to delete")}: ${this.state.selectedRowKeys.length} ${i18next.t("general:items")} ?`} onConfirm={() => this.performBulkDelete(this.state.selectedRows, this.state.selectedRowKeys)} okText={i18next.t("general:OK")} cancelText={i18next.t("general:Cancel")}>
+                  <Button type="primary" danger size="small" icon={<DeleteOutlined />}>
+                    {i18next.t("general:Delete")} ({this.state.selectedRowKeys.length})
+                  </Button>
+                </Popconfirm>
+              )}
+            </div>
+          )}
+          loading={this.state.loading}
+        />
+      </div>
+    );
+  }
+
+  fetch = (params = {}) => {
+    // The memories of the user are few, so they are fetched at once and paginated by the table
+    this.setState({loading: true});
+    MemoryBackend.getMemories(this.props.account.name)
+      .then((res) => {
+        this.setState({
+          loading: false,
+        });
+        if (res.status === "ok") {
+          this.setState({
+            data: res.data,
+            pagination: {
+              ...params.pagination,
+              total: res.data.length,
+            },
+          });
+        } else {
+          if (Setting.isResponseDenied(res)) {
+            this.setState({
+              isAuthorized: false,
+            });
+          } else {
+            Setting.showMessage("error", res.msg);
+          }
+        }
+      });
+  };
+}
+
+export default MemoryListPage;
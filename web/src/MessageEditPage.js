@@ -13,11 +13,12 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Card, Col, Input, Row, Select, Switch} from "antd";
+import {Button, Card, Col, Input, Row, Select, Switch, Tag} from "antd";
 import i18next from "i18next";
 import * as Setting from "./Setting";
 import * as MessageBackend from "./backend/MessageBackend";
 import * as ChatBackend from "./backend/ChatBackend";
+import MessageAgentSteps from "./chat/MessageAgentSteps";
 
 const {TextArea} = Input;
 
@@ -218,6 +219,14 @@ class MessageEditPage extends React.Component {
             />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}}>
+          <Col style={{marginTop: "5px"}} span={2}>
+            {Setting.getLabel(i18next.t("message:Parent message"), i18next.t("message:Parent message - Tooltip"))} :
+          </Col>
+          <Col span={22}>
+            <Input disabled={true} value={this.state.message.parentMessage} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}}>
           <Col style={{marginTop: "5px"}} span={2}>
             {Setting.getLabel(i18next.t("general:Reasoning text"), i18next.t("general:Reasoning text - Tooltip"))} :
@@ -248,6 +257,58 @@ class MessageEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        {
+          this.state.message.agentSteps?.length > 0 ? (
+            <Row style={{marginTop: "20px"}}>
+              <Col style={{marginTop: "5px"}} span={2}>
+                {Setting.getLabel(i18next.t("message:Agent steps"), i18next.t("message:Agent steps - Tooltip"))} :
+              </Col>
+              <Col span={22}>
+                <MessageAgentSteps message={this.state.message} />
+              </Col>
+            </Row>
+          ) : null
+        }
+        {
+          Object.keys(this.state.message.carrierValues ?? {}).length > 0 ? (
+            <Row style={{marginTop: "20px"}}>
+              <Col style={{marginTop: "5px"}} span={2}>
+                {Setting.getLabel(i18next.t("message:Carrier values"), i18next.t("message:Carrier values - Tooltip"))} :
+              </Col>
+              <Col span={22}>
+                {
+                  Object.entries(this.state.message.carrierValues).map(([name, value]) => {
+                    return (
+                      <Tag key={name} style={{marginTop: "5px", whiteSpace: "normal"}}>
+                        {`${name}: ${value}`}
+                      </Tag>
+                    );
+                  })
+                }
+              </Col>
+            </Row>
+          ) : null
+        }
+        {
+          this.state.message.guardrailEvents?.length > 0 ? (
+            <Row style={{marginTop: "20px"}}>
+              <Col style={{marginTop: "5px"}} span={2}>
+                {Setting.getLabel(i18next.t("message:Guardrail events"), i18next.t("message:Guardrail events - Tooltip"))} :
+              </Col>
+              <Col span={22}>
+                {
+                  this.state.message.guardrailEvents.map((event, index) => {
+                    return (
+                      <Tag key={index} style={{marginTop: "5px", whiteSpace: "normal"}} color={event.action === "Block" ? "error" : "warning"}>
+                        {`${event.time} ${event.stage} ${event.guardrail} (${event.type}): ${event.action} ${event.detail}`}
+                      </Tag>
+                    );
+                  })
+                }
+              </Col>
+            </Row>
+          ) : null
+        }
         <Row style={{marginTop: "20px"}}>
           <Col style={{marginTop: "5px"}} span={2}>
             {Setting.getLabel(i18next.t("message:Comment"), i18next.t("message:Comment - Tooltip"))} :
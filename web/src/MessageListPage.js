@@ -14,7 +14,7 @@
 
 import React from "react";
 import {Link} from "react-router-dom";
-import {Button, Popconfirm, Switch, Table, Tag} from "antd";
+import {Button, Popconfirm, Switch, Table, Tag, Tooltip} from "antd";
 import BaseListPage from "./BaseListPage";
 import {ThemeDefault} from "./Conf";
 import * as Setting from "./Setting";
@@ -232,6 +232,24 @@ class MessageListPage extends BaseListPage {
           );
         },
       },
+      {
+        title: i18next.t("message:Used provider"),
+        dataIndex: "usedModelProvider",
+        key: "usedModelProvider",
+        width: "150px",
+        sorter: (a, b) => a.usedModelProvider.localeCompare(b.usedModelProvider),
+        render: (text, record, index) => {
+          if (!text) {
+            return null;
+          }
+
+          return (
+            <Link to={`/providers/${text}`}>
+              {text}
+            </Link>
+          );
+        },
+      },
       {
         title: i18next.t("chat:Token count"),
         dataIndex: "tokenCount",
@@ -303,6 +321,57 @@ class MessageListPage extends BaseListPage {
           });
         },
       },
+      {
+        title: i18next.t("message:Agent steps"),
+        dataIndex: "agentSteps",
+        key: "agentSteps",
+        width: "200px",
+        render: (text, record, index) => {
+          return text?.map((step, i) => {
+            return (
+              <Tooltip key={i} title={step.error ? step.error : `${step.duration} ms`}>
+                <Tag style={{marginTop: "5px"}} color={step.error ? "error" : "success"}>
+                  {step.toolName}
+                </Tag>
+              </Tooltip>
+            );
+          });
+        },
+      },
+      {
+        title: i18next.t("message:Carrier values"),
+        dataIndex: "carrierValues",
+        key: "carrierValues",
+        width: "200px",
+        render: (text, record, index) => {
+          return Object.entries(text ?? {}).map(([name, value]) => {
+            return (
+              <Tooltip key={name} title={value}>
+                <Tag style={{marginTop: "5px"}}>
+                  {`${name}: ${Setting.getShortText(value, 30)}`}
+                </Tag>
+              </Tooltip>
+            );
+          });
+        },
+      },
+      {
+        title: i18next.t("message:Guardrail events"),
+        dataIndex: "guardrailEvents",
+        key: "guardrailEvents",
+        width: "200px",
+        render: (text, record, index) => {
+          return (text ?? []).map((event, i) => {
+            return (
+              <Tooltip key={i} title={`${event.stage}: ${event.detail}`}>
+                <Tag style={{marginTop: "5px"}} color={event.action === "Block" ? "error" : "warning"}>
+                  {`${event.guardrail}: ${event.action}`}
+                </Tag>
+              </Tooltip>
+            );
+          });
+        },
+      },
       {
         title: i18next.t("message:Suggestions"),
         dataIndex: "suggestions",
@@ -0,0 +1,255 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Card, Col, DatePicker, Input, InputNumber, Row, Select} from "antd";
+import moment from "moment";
+import * as PricingBackend from "./backend/PricingBackend";
+import * as Setting from "./Setting";
+import i18next from "i18next";
+
+const {Option} = Select;
+
+class PricingEditPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+      pricingName: props.match.params.pricingName,
+      pricing: null,
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getPricing();
+  }
+
+  getPricing() {
+    PricingBackend.getPricing("admin", this.state.pricingName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            pricing: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  parsePricingField(key, value) {
+    if (["inputPricePerThousandTokens", "outputPricePerThousandTokens", "cachedInputPricePerThousandTokens", "pricePerImage", "audioPricePerThousandTokens"].includes(key)) {
+      value = Setting.myParseFloat(value);
+    }
+    return value;
+  }
+
+  updatePricingField(key, value) {
+    value = this.parsePricingField(key, value);
+
+    const pricing = this.state.pricing;
+    pricing[key] = value;
+    this.setState({
+      pricing: pricing,
+    });
+  }
+
+  renderPriceField(key, label) {
+    return (
+      <Row style={{marginTop: "20px"}} >
+        <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+          {Setting.getLabel(i18next.t(label), i18next.t(`${label} - Tooltip`))} :
+        </Col>
+        <Col span={22} >
+          <InputNumber min={0} value={this.state.pricing[key]} onChange={value => {
+            this.updatePricingField(key, value);
+          }} />
+        </Col>
+      </Row>
+    );
+  }
+
+  renderPricing() {
+    const category = this.state.pricing.category;
+
+    return (
+      <Card size="small" title={
+        <div>
+          {i18next.t("pricing:Edit Pricing")}&nbsp;&nbsp;&nbsp;&nbsp;
+          <Button onClick={() => this.submitPricingEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitPricingEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+        </div>
+      } style={{marginLeft: "5px"}} type="inner">
+        <Row style={{marginTop: "10px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Name"), i18next.t("general:Name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.pricing.name} onChange={e => {
+              this.updatePricingField("name", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Display name"), i18next.t("general:Display name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.pricing.displayName} onChange={e => {
+              this.updatePricingField("displayName", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("provider:Category"), i18next.t("provider:Category - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={category} onChange={(value => {
+              this.updatePricingField("category", value);
+              const types = Setting.getProviderTypeOptions(value);
+              this.updatePricingField("type", types.length > 0 ? types[0].id : "");
+              this.updatePricingField("subType", "");
+            })}>
+              {
+                [
+                  {id: "Model", name: "Model"},
+                  {id: "Embedding", name: "Embedding"},
+                  {id: "Text-to-Speech", name: "Text-to-Speech"},
+                ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Type"), i18next.t("general:Type - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.pricing.type} onChange={(value => {
+              this.updatePricingField("type", value);
+              this.updatePricingField("subType", "");
+            })}>
+              {
+                Setting.getProviderTypeOptions(category).map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("provider:Sub type"), i18next.t("pricing:Sub type - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} showSearch value={this.state.pricing.subType} onChange={(value => {
+              this.updatePricingField("subType", value);
+            })}>
+              {
+                [{id: "", name: i18next.t("pricing:All sub types")}, ...(Setting.getProviderSubTypeOptions(category, this.state.pricing.type) || [])]
+                  .map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        {
+          category === "Text-to-Speech" ? null : (
+            this.renderPriceField("inputPricePerThousandTokens", "provider:Input price / 1k tokens")
+          )
+        }
+        {
+          category !== "Model" ? null : (
+            <>
+              {this.renderPriceField("outputPricePerThousandTokens", "provider:Output price / 1k tokens")}
+              {this.renderPriceField("cachedInputPricePerThousandTokens", "pricing:Cached input price / 1k tokens")}
+              {this.renderPriceField("pricePerImage", "pricing:Price per image")}
+            </>
+          )
+        }
+        {
+          category !== "Text-to-Speech" ? null : (
+            this.renderPriceField("audioPricePerThousandTokens", "pricing:Audio price / 1k tokens")
+          )
+        }
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("provider:Currency"), i18next.t("provider:Currency - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.pricing.currency} onChange={(value => {
+              this.updatePricingField("currency", value);
+            })}>
+              {
+                Setting.getCurrencyOptions().map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("pricing:Effective time"), i18next.t("pricing:Effective time - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <DatePicker showTime value={this.state.pricing.effectiveTime === "" ? null : moment(this.state.pricing.effectiveTime)} onChange={(value) => {
+              this.updatePricingField("effectiveTime", value === null ? "" : value.format());
+            }} />
+          </Col>
+        </Row>
+      </Card>
+    );
+  }
+
+  submitPricingEdit(exitAfterSave) {
+    const pricing = Setting.deepCopy(this.state.pricing);
+    PricingBackend.updatePricing(this.state.pricing.owner, this.state.pricingName, pricing)
+      .then((res) => {
+        if (res.status === "ok") {
+          if (res.data) {
+            Setting.showMessage("success", i18next.t("general:Successfully saved"));
+            this.setState({
+              pricingName: this.state.pricing.name,
+            });
+            if (exitAfterSave) {
+              this.props.history.push("/pricings");
+            } else {
+              this.props.history.push(`/pricings/${this.state.pricing.name}`);
+            }
+          } else {
+            Setting.showMessage("error", i18next.t("general:Failed to save"));
+            this.updatePricingField("name", this.state.pricingName);
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${error}`);
+      });
+  }
+
+  render() {
+    return (
+      <div>
+        {
+          this.state.pricing !== null ? this.renderPricing() : null
+        }
+        <div style={{marginTop: "20px", marginLeft: "40px"}}>
+          <Button size="large" onClick={() => this.submitPricingEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" size="large" onClick={() => this.submitPricingEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+        </div>
+      </div>
+    );
+  }
+}
+
+export default PricingEditPage;
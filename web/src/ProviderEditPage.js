@@ -21,6 +21,7 @@ import i18next from "i18next";
 import copy from "copy-to-clipboard";
 import FileSaver from "file-saver";
 import McpToolsTable from "./table/McpToolsTable";
+import ModelRouteTable from "./table/ModelRouteTable";
 import ModelTestWidget from "./common/TestModelWidget";
 import TtsTestWidget from "./common/TestTtsWidget";
 import EmbedTestWidget from "./common/TestEmbedWidget";
@@ -42,12 +43,27 @@ class ProviderEditPage extends React.Component {
       provider: null,
       originalProvider: null,
       refreshButtonLoading: false,
+      modelProviders: [],
       isAdmin: props.account?.isAdmin || props.account?.owner === "admin",
     };
   }
 
   UNSAFE_componentWillMount() {
     this.getProvider();
+    this.getModelProviders();
+  }
+
+  getModelProviders() {
+    ProviderBackend.getProviders(this.props.account.name)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            modelProviders: res.data.filter(provider => provider.category === "Model" && provider.type !== "Router" && provider.name !== this.state.providerName),
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
   }
 
   getProvider() {
@@ -77,6 +93,9 @@ class ProviderEditPage extends React.Component {
       }
     }
     if (provider.category === "Storage") {
+      if (["WebDAV", "SFTP"].includes(provider.type)) {
+        return Setting.getLabel(i18next.t("general:Username"), i18next.t("general:Username - Tooltip"));
+      }
       return Setting.getLabel(i18next.t("store:Storage subpath"), i18next.t("store:Storage subpath - Tooltip"));
     }
     return Setting.getLabel(i18next.t("provider:Client ID"), i18next.t("provider:Client ID - Tooltip"));
@@ -92,6 +111,9 @@ class ProviderEditPage extends React.Component {
   }
 
   getProviderUrlLabel(provider) {
+    if (provider.category === "Storage" && provider.type === "SFTP") {
+      return Setting.getLabel(i18next.t("general:Provider URL"), i18next.t("provider:SFTP provider URL - Tooltip"));
+    }
     if (["Model", "Blockchain"].includes(provider.category)) {
       if (provider.type === "Volcano Engine") {
         return Setting.getLabel(i18next.t("provider:Endpoint ID"), i18next.t("provider:Endpoint ID - Tooltip"));
@@ -113,7 +135,9 @@ class ProviderEditPage extends React.Component {
 
   getClientSecretLabel(provider) {
     if (["Storage", "Embedding", "Text-to-Speech", "Speech-to-Text"].includes(provider.category)) {
-      if (provider.type === "Baidu Cloud") {
+      if (provider.category === "Storage" && ["WebDAV", "SFTP"].includes(provider.type)) {
+        return Setting.getLabel(i18next.t("general:Password"), i18next.t("general:Password - Tooltip"));
+      } else if (provider.type === "Baidu Cloud") {
         return Setting.getLabel(i18next.t("general:Access secret"), i18next.t("general:Access secret - Tooltip"));
       }
       return Setting.getLabel(i18next.t("general:Secret key"), i18next.t("general:Secret key - Tooltip"));
@@ -378,6 +402,8 @@ class ProviderEditPage extends React.Component {
                   this.updateProviderField("subType", "gpt-4o");
                 } else if (value === "Writer") {
                   this.updateProviderField("subType", "palmyra-x5");
+                } else if (value === "Router") {
+                  this.updateProviderField("subType", "Failover");
                 }
               } else if (this.state.provider.category === "Embedding") {
                 if (value === "OpenAI") {
@@ -402,6 +428,13 @@ class ProviderEditPage extends React.Component {
                   this.updateProviderField("subType", "Default");
                 } else if (value === "A2A") {
                   this.updateProviderField("subType", "Default");
+                } else if (value === "Builtin") {
+                  this.updateProviderField("subType", "Default");
+                } else if (value === "OpenAPI") {
+                  this.updateProviderField("subType", "Default");
+                  if (!this.state.provider.text) {
+                    this.updateProviderField("text", Setting.getDefaultOpenApiConfig());
+                  }
                 }
               } else if (this.state.provider.category === "Text-to-Speech") {
                 if (value === "Alibaba Cloud") {
@@ -629,10 +662,10 @@ class ProviderEditPage extends React.Component {
         }
         {
           (
-            (this.state.provider.category === "Storage" && this.state.provider.type !== "OpenAI File System") ||
-            (this.state.provider.category === "Agent" && this.state.provider.type === "MCP") ||
+            (this.state.provider.category === "Storage" && !["OpenAI File System", "WebDAV", "SFTP"].includes(this.state.provider.type)) ||
+            (this.state.provider.category === "Agent" && ["MCP", "Builtin", "OpenAPI"].includes(this.state.provider.type)) ||
             (this.state.provider.category === "Blockchain" && this.state.provider.type === "ChainMaker") ||
-            this.state.provider.type === "Dummy"
+            this.state.provider.type === "Dummy" || this.state.provider.type === "Router"
           ) ? null : (
               <Row style={{marginTop: "20px"}} >
                 <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
@@ -646,6 +679,26 @@ class ProviderEditPage extends React.Component {
               </Row>
             )
         }
+        {
+          (this.state.provider.category === "Model" && this.state.provider.type === "Router") && (
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("provider:Model routes"), i18next.t("provider:Model routes - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                <ModelRouteTable
+                  title={i18next.t("provider:Model routes")}
+                  table={this.state.provider.modelRoutes}
+                  providers={this.state.modelProviders}
+                  subType={this.state.provider.subType}
+                  onUpdateTable={(value) => {
+                    this.updateProviderField("modelRoutes", value);
+                  }}
+                />
+              </Col>
+            </Row>
+          )
+        }
         {
           (this.state.provider.type === "iFlytek" && this.state.provider.category === "Model") && (
             <Row style={{marginTop: "20px"}} >
@@ -695,7 +748,11 @@ class ProviderEditPage extends React.Component {
             <>
               <Row style={{marginTop: "20px"}} >
                 <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
-                  {Setting.getLabel(i18next.t("provider:MCP servers"), i18next.t("provider:MCP servers - Tooltip"))} :
+                  {
+                    this.state.provider.type === "OpenAPI" ?
+                      Setting.getLabel(i18next.t("provider:OpenAPI config"), i18next.t("provider:OpenAPI config - Tooltip")) :
+                      Setting.getLabel(i18next.t("provider:MCP servers"), i18next.t("provider:MCP servers - Tooltip"))
+                  } :
                 </Col>
                 <Col span={10} >
                   <div style={{height: "500px"}}>
@@ -1072,7 +1129,7 @@ class ProviderEditPage extends React.Component {
           onUpdateProvider={this.updateProviderField.bind(this)}
         />
         {
-          this.state.provider.category === "Model" ? (
+          ["Model", "Embedding"].includes(this.state.provider.category) ? (
             <Row style={{marginTop: "20px"}} >
               <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
                 {Setting.getLabel(i18next.t("provider:Provider key"), i18next.t("provider:Provider key - Tooltip"))} :
@@ -1113,7 +1170,7 @@ class ProviderEditPage extends React.Component {
             {this.getProviderUrlLabel(this.state.provider)} :
           </Col>
           <Col span={22} >
-            <Input prefix={<LinkOutlined />} value={this.state.provider.providerUrl} onChange={e => {
+            <Input prefix={<LinkOutlined />} placeholder={(this.state.provider.category === "Storage" && this.state.provider.type === "SFTP") ? "sftp://example.com:22/path?fingerprint=SHA256:xxx" : ""} value={this.state.provider.providerUrl} onChange={e => {
               this.updateProviderField("providerUrl", e.target.value);
             }} />
           </Col>
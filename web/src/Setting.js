@@ -539,6 +539,12 @@ export function getRandomName() {
   return Math.random().toString(36).slice(-6);
 }
 
+export function generateApiKey() {
+  const chars = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ";
+  const values = window.crypto.getRandomValues(new Uint8Array(24));
+  return `sk-${Array.from(values, value => chars[value % chars.length]).join("")}`;
+}
+
 export function getFriendlyFileSize(size) {
   if (size < 1024) {
     return size + " B";
@@ -750,6 +756,10 @@ export function getOtherProviderInfo() {
         logo: `${StaticBaseUrl}/img/social_aws.png`,
         url: "https://aws.amazon.com/bedrock/",
       },
+      "Router": {
+        logo: `${StaticBaseUrl}/img/social_default.png`,
+        url: "",
+      },
       "Dummy": {
         logo: `${StaticBaseUrl}/img/social_default.png`,
         url: "",
@@ -902,6 +912,14 @@ export function getOtherProviderInfo() {
         logo: `${StaticBaseUrl}/img/social_openai.svg`,
         url: "https://platform.openai.com",
       },
+      "WebDAV": {
+        logo: `${StaticBaseUrl}/img/social_file.png`,
+        url: "",
+      },
+      "SFTP": {
+        logo: `${StaticBaseUrl}/img/social_file.png`,
+        url: "",
+      },
     },
     Blockchain: {
       "Hyperledger Fabric": {
@@ -940,10 +958,18 @@ export function getOtherProviderInfo() {
       },
     },
     Agent: {
+      "Builtin": {
+        logo: `${StaticBaseUrl}/img/casibase.png`,
+        url: "https://casibase.org/",
+      },
       "MCP": {
         logo: `${StaticBaseUrl}/img/social_mcp.png`,
         url: "https://modelcontextprotocol.io/",
       },
+      "OpenAPI": {
+        logo: `${StaticBaseUrl}/img/social_openapi.png`,
+        url: "https://www.openapis.org/",
+      },
       "A2A": {
         logo: `${StaticBaseUrl}/img/social_a2a.png`,
         url: "https://agent2agent.info/",
@@ -1101,6 +1127,8 @@ export function getProviderTypeOptions(category) {
       [
         {id: "Local File System", name: "Local File System"},
         {id: "OpenAI File System", name: "OpenAI File System"},
+        {id: "WebDAV", name: "WebDAV"},
+        {id: "SFTP", name: "SFTP"},
       ]
     );
   } else if (category === "Model") {
@@ -1122,6 +1150,7 @@ export function getProviderTypeOptions(category) {
         {id: "Cohere", name: "Cohere"},
         {id: "Moonshot", name: "Moonshot"},
         {id: "Amazon Bedrock", name: "Amazon Bedrock"},
+        {id: "Router", name: "Router"},
         {id: "Dummy", name: "Dummy"},
         {id: "Alibaba Cloud", name: "Alibaba Cloud"},
         {id: "Baichuan", name: "Baichuan"},
@@ -1158,6 +1187,8 @@ export function getProviderTypeOptions(category) {
     return ([
       {id: "MCP", name: "MCP"},
       {id: "A2A", name: "A2A"},
+      {id: "Builtin", name: "Builtin"},
+      {id: "OpenAPI", name: "OpenAPI"},
     ]);
   } else if (category === "Public Cloud") {
     return ([
@@ -1678,6 +1709,13 @@ export function getModelSubTypeOptions(type) {
     return [
       {id: "Dummy", name: "Dummy"},
     ];
+  } else if (type === "Router") {
+    return [
+      {id: "Failover", name: "Failover"},
+      {id: "Round Robin", name: "Round Robin"},
+      {id: "Weighted", name: "Weighted"},
+      {id: "Latency", name: "Latency"},
+    ];
   } else {
     return [];
   }
@@ -1759,7 +1797,7 @@ export function getProviderSubTypeOptions(category, type) {
   } else if (category === "Embedding") {
     return getEmbeddingSubTypeOptions(type);
   } else if (category === "Agent") {
-    if (type === "MCP") {
+    if (type === "MCP" || type === "Builtin" || type === "OpenAPI") {
       return [
         {id: "Default", name: "Default"},
       ];
@@ -1787,6 +1825,21 @@ export function getProviderSubTypeOptions(category, type) {
   }
 }
 
+export function getCurrencyOptions() {
+  return [
+    {id: "USD", name: "USD"},
+    {id: "CNY", name: "CNY"},
+    {id: "EUR", name: "EUR"},
+    {id: "JPY", name: "JPY"},
+    {id: "GBP", name: "GBP"},
+    {id: "AUD", name: "AUD"},
+    {id: "CAD", name: "CAD"},
+    {id: "CHF", name: "CHF"},
+    {id: "HKD", name: "HKD"},
+    {id: "SGD", name: "SGD"},
+  ];
+}
+
 export function getProviderAzureApiVersionOptions() {
   return ([
     {id: "", name: ""},
@@ -2107,6 +2160,18 @@ export function getBlockBrowserUrl(providerMap, record, block, isFirst = true) {
   );
 }
 
+export function getDefaultOpenApiConfig() {
+  return JSON.stringify({
+    specUrl: "https://petstore3.swagger.io/api/v3/openapi.json",
+    spec: "",
+    baseUrl: "",
+    operations: [],
+    headers: {},
+    auth: {type: "None", token: "", username: "", password: "", name: "", in: "header"},
+    timeout: 30,
+  }, null, 2);
+}
+
 export function formatJsonString(s) {
   if (s === "") {
     return "";
@@ -0,0 +1,138 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Collapse, Result, Spin, Tag, Tooltip} from "antd";
+import {PaperClipOutlined} from "@ant-design/icons";
+import i18next from "i18next";
+import * as Setting from "./Setting";
+import * as ChatBackend from "./backend/ChatBackend";
+import {renderText} from "./ChatMessageRender";
+
+class SharedChatPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      shareId: props.match.params.shareId,
+      chat: null,
+      errorText: "",
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getSharedChat();
+  }
+
+  getSharedChat() {
+    ChatBackend.getSharedChat(this.state.shareId)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            chat: res.data,
+          });
+        } else {
+          this.setState({
+            errorText: res.msg,
+          });
+        }
+      })
+      .catch(error => {
+        this.setState({
+          errorText: `${i18next.t("general:Failed to connect to server")}: ${error}`,
+        });
+      });
+  }
+
+  renderAttachment(attachment) {
+    if (!attachment) {
+      return null;
+    }
+
+    return (
+      <div style={{marginTop: "8px"}}>
+        <PaperClipOutlined style={{marginRight: "4px"}} />
+        {attachment.url !== "" ? <a target="_blank" rel="noreferrer" href={attachment.url}>{attachment.name}</a> : attachment.name}
+      </div>
+    );
+  }
+
+  renderCitations(citations) {
+    if (!citations || citations.length === 0) {
+      return null;
+    }
+
+    return (
+      <div style={{marginTop: "8px", color: "#666", fontSize: "12px"}}>
+        {i18next.t("chat:Citations")}:
+        {citations.map((citation, index) => (
+          <Tooltip key={index} title={Setting.getShortText(citation.text, 300)}>
+            <Tag style={{marginLeft: "6px"}}>{`${index + 1}. ${citation.file} (${citation.score.toFixed(2)})`}</Tag>
+          </Tooltip>
+        ))}
+      </div>
+    );
+  }
+
+  renderMessage(message, index) {
+    const isAi = message.author === "AI";
+
+    return (
+      <div key={index} style={{display: "flex", justifyContent: isAi ? "flex-start" : "flex-end", margin: "16px 0"}}>
+        <div style={{maxWidth: "85%", padding: "12px 16px", borderRadius: "16px", backgroundColor: isAi ? "#f5f5f5" : "#e6f4ff"}}>
+          <div style={{color: "#999", fontSize: "12px", marginBottom: "4px"}}>
+            {isAi ? i18next.t("chat:AI") : i18next.t("general:User")} · {Setting.getFormattedDate(message.createdTime)}
+          </div>
+          {message.reasonText && (
+            <Collapse size="small" ghost style={{marginBottom: "8px"}} items={[{
+              key: "reason",
+              label: i18next.t("chat:Reasoning process"),
+              children: renderText(message.reasonText),
+            }]} />
+          )}
+          {renderText(message.text)}
+          {this.renderAttachment(message.attachment)}
+          {this.renderCitations(message.citations)}
+        </div>
+      </div>
+    );
+  }
+
+  render() {
+    if (this.state.errorText !== "") {
+      return (
+        <Result status="404" title={i18next.t("chat:The shared chat is not available")} subTitle={this.state.errorText} />
+      );
+    }
+
+    if (this.state.chat === null) {
+      return (
+        <div style={{display: "flex", justifyContent: "center", paddingTop: "10%"}}>
+          <Spin size="large" tip={i18next.t("login:Loading")} />
+        </div>
+      );
+    }
+
+    return (
+      <div style={{maxWidth: "860px", width: "100%", margin: "0 auto", padding: "24px"}}>
+        <h2>{this.state.chat.displayName}</h2>
+        <div style={{color: "#999"}}>
+          {`${i18next.t("general:Created time")}: ${Setting.getFormattedDate(this.state.chat.createdTime)}`}
+        </div>
+        {this.state.chat.messages.map((message, index) => this.renderMessage(message, index))}
+      </div>
+    );
+  }
+}
+
+export default SharedChatPage;
@@ -22,6 +22,8 @@ import i18next from "i18next";
 import FileTree from "./FileTree";
 import {ThemeDefault} from "./Conf";
 import PromptTable from "./table/PromptTable";
+import CarrierTable from "./table/CarrierTable";
+import GuardrailTable from "./table/GuardrailTable";
 import StoreAvatarUploader from "./AvatarUpload";
 import {LinkOutlined} from "@ant-design/icons";
 import {Controlled as CodeMirror} from "react-codemirror2";
@@ -156,6 +158,11 @@ class StoreEditPage extends React.Component {
     return false;
   }
 
+  isLocalStorageProvider(storageProvider) {
+    const providerSelected = this.state.storageProviders.find(v => v.name === storageProvider);
+    return providerSelected !== undefined && providerSelected.type === "Local File System";
+  }
+
   renderStore() {
     return (
       <Card size="small" title={
@@ -235,6 +242,22 @@ class StoreEditPage extends React.Component {
             ].map(item => Setting.getOption(item.label, item.value))} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:API key"), i18next.t("store:API key - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input.Password style={{width: "calc(100% - 110px)"}} value={this.state.store.apiKey} placeholder={i18next.t("store:API access is disabled")} disabled={!(this.props.account?.isAdmin || this.props.account?.owner === "admin")} onChange={e => {
+              this.updateStoreField("apiKey", e.target.value);
+            }} />
+            <Button style={{marginLeft: "10px", width: "100px"}} disabled={!(this.props.account?.isAdmin || this.props.account?.owner === "admin")} onClick={() => {
+              this.updateStoreField("apiKey", Setting.generateApiKey());
+            }}
+            >
+              {i18next.t("store:Generate")}
+            </Button>
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Storage provider"), i18next.t("store:Storage provider - Tooltip"))} :
@@ -260,6 +283,40 @@ class StoreEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        {this.isLocalStorageProvider(this.state.store.storageProvider) ? (
+          <>
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("store:Enable watch"), i18next.t("store:Enable watch - Tooltip"))} :
+              </Col>
+              <Col span={1} >
+                <Switch checked={this.state.store.enableWatch} onChange={checked => {
+                  this.updateStoreField("enableWatch", checked);
+                }} />
+              </Col>
+            </Row>
+            {!this.state.store.enableWatch ? null : (
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("store:Sync status"), i18next.t("store:Sync status - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <div style={{marginTop: "5px"}}>
+                    {`${i18next.t("store:Sync time")}: ${this.state.store.syncTime ? Setting.getFormattedDate(this.state.store.syncTime) : "-"}`}
+                  </div>
+                  <div>
+                    {`${i18next.t("store:Pending files")}: ${(this.state.store.pendingFiles ?? []).length === 0 ? "-" : this.state.store.pendingFiles.join(", ")}`}
+                  </div>
+                  {!this.state.store.syncError ? null : (
+                    <div style={{color: "red"}}>
+                      {`${i18next.t("general:Error")}: ${this.state.store.syncError}`}
+                    </div>
+                  )}
+                </Col>
+              </Row>
+            )}
+          </>
+        ) : null}
         {this.isAIStorageProvider(this.state.store.storageProvider) ? (
           <>
             <Row style={{marginTop: "20px"}} >
@@ -417,6 +474,44 @@ class StoreEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Memory strategy"), i18next.t("store:Memory strategy - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.store.memoryStrategy === "" ? "Window" : this.state.store.memoryStrategy} onChange={(value => {this.updateStoreField("memoryStrategy", value);})}>
+              {
+                [
+                  {id: "Window", name: i18next.t("store:Window")},
+                  {id: "Summary", name: i18next.t("store:Summary")},
+                ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Enable memory"), i18next.t("store:Enable memory - Tooltip"))} :
+          </Col>
+          <Col span={1}>
+            <Switch checked={this.state.store.enableMemory} onChange={checked => {
+              this.updateStoreField("enableMemory", checked);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Memory provider"), i18next.t("store:Memory provider - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.store.memoryProvider} onChange={(value => {this.updateStoreField("memoryProvider", value);})}>
+              <Option key="Empty" value="">{i18next.t("general:empty")}</Option>
+              {
+                this.state.modelProviders.map((provider, index) => this.renderProviderOption(provider, index))
+              }
+            </Select>
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Limit minutes"), i18next.t("store:Limit minutes - Tooltip"))} :
@@ -501,6 +596,26 @@ class StoreEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Carriers"), i18next.t("store:Carriers - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <CarrierTable carriers={this.state.store.carriers} onUpdateCarriers={(carriers) => {
+              this.updateStoreField("carriers", carriers);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Guardrails"), i18next.t("store:Guardrails - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <GuardrailTable guardrails={this.state.store.guardrails} modelProviders={this.state.modelProviders} onUpdateGuardrails={(guardrails) => {
+              this.updateStoreField("guardrails", guardrails);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("general:Site setting"), i18next.t("general:Site setting - Tooltip"))} :
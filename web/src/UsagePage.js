@@ -347,6 +347,7 @@ class UsagePage extends BaseListPage {
                   title={i18next.t("chat:Price")}
                   value={lastUsage.price}
                   prefix={lastUsage.currency && "$"}
+                  suffix={this.renderOtherPrices(lastUsage)}
                 />
               </Col>
               {
@@ -370,6 +371,24 @@ class UsagePage extends BaseListPage {
     );
   }
 
+  // The prices without an exchange rate to the display currency are shown in their own currencies
+  renderOtherPrices(usage) {
+    if (!usage.prices) {
+      return null;
+    }
+
+    const texts = Object.entries(usage.prices)
+      .filter(([currency]) => currency !== usage.currency)
+      .map(([currency, price]) => `${price} ${currency}`);
+    if (texts.length === 0) {
+      return null;
+    }
+
+    return (
+      <span style={{fontSize: "14px"}}>{` + ${texts.join(" + ")}`}</span>
+    );
+  }
+
   getServerUrlFromEndpoint(endpoint) {
     if (endpoint === "localhost:14000") {
       return `http://${endpoint}`;
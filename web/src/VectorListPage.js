@@ -178,6 +178,24 @@ class VectorListPage extends BaseListPage {
         width: "80px",
         sorter: (a, b) => a.index - b.index,
       },
+      {
+        title: i18next.t("vector:Duplicate of"),
+        dataIndex: "duplicateOf",
+        key: "duplicateOf",
+        width: "140px",
+        sorter: (a, b) => (a.duplicateOf ?? "").localeCompare(b.duplicateOf ?? ""),
+        render: (text, record, index) => {
+          if (!text) {
+            return null;
+          }
+
+          return (
+            <Link to={`/vectors/${text}`}>
+              {text}
+            </Link>
+          );
+        },
+      },
       {
         title: i18next.t("general:Text"),
         dataIndex: "text",
@@ -79,3 +79,54 @@ export function deleteChat(chat) {
     body: JSON.stringify(newChat),
   }).then(res => res.json());
 }
+
+export function exportChat(owner, name, format) {
+  return fetch(`${Setting.ServerUrl}/api/export-chat?id=${owner}/${encodeURIComponent(name)}&format=${format}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function importChat(text, storeName = "") {
+  return fetch(`${Setting.ServerUrl}/api/import-chat?store=${encodeURIComponent(storeName)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: text,
+  }).then(res => res.json());
+}
+
+export function shareChat(owner, name, expireTime = "") {
+  return fetch(`${Setting.ServerUrl}/api/share-chat?id=${owner}/${encodeURIComponent(name)}&expireTime=${encodeURIComponent(expireTime)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function unshareChat(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/unshare-chat?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getSharedChat(shareId) {
+  return fetch(`${Setting.ServerUrl}/api/get-shared-chat?shareId=${encodeURIComponent(shareId)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
@@ -49,6 +49,16 @@ export function deleteFile(storeId, key, isLeaf) {
   }).then(res => res.json());
 }
 
+export function moveFile(storeId, key, newKey, isLeaf) {
+  return fetch(`${Setting.ServerUrl}/api/move-file?store=${storeId}&key=${encodeURIComponent(key)}&newKey=${encodeURIComponent(newKey)}&isLeaf=${isLeaf ? 1 : 0}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
 export function activateFile(key, filename) {
   return fetch(`${Setting.ServerUrl}/api/activate-file?key=${key}&filename=${filename}`, {
     method: "POST",
@@ -0,0 +1,71 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import * as Setting from "../Setting";
+
+export function getMemories(user) {
+  return fetch(`${Setting.ServerUrl}/api/get-memories?user=${encodeURIComponent(user)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getMemory(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/get-memory?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function updateMemory(owner, name, memory) {
+  const newMemory = Setting.deepCopy(memory);
+  return fetch(`${Setting.ServerUrl}/api/update-memory?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newMemory),
+  }).then(res => res.json());
+}
+
+export function addMemory(memory) {
+  const newMemory = Setting.deepCopy(memory);
+  return fetch(`${Setting.ServerUrl}/api/add-memory`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newMemory),
+  }).then(res => res.json());
+}
+
+export function deleteMemory(memory) {
+  const newMemory = Setting.deepCopy(memory);
+  return fetch(`${Setting.ServerUrl}/api/delete-memory`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newMemory),
+  }).then(res => res.json());
+}
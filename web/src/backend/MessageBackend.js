@@ -46,7 +46,7 @@ export function getChatMessages(owner, chat) {
 
 const eventSourceMap = new Map();
 
-export function getMessageAnswer(owner, name, onMessage, onReason, onError, onEnd) {
+export function getMessageAnswer(owner, name, onMessage, onReason, onError, onEnd, onApproval, onStep) {
   if (eventSourceMap.has(`${owner}/${name}`)) {
     return;
   }
@@ -63,6 +63,18 @@ export function getMessageAnswer(owner, name, onMessage, onReason, onError, onEn
     onReason(e.data);
   });
 
+  eventSource.addEventListener("approval", (e) => {
+    if (onApproval) {
+      onApproval(e.data);
+    }
+  });
+
+  eventSource.addEventListener("step", (e) => {
+    if (onStep) {
+      onStep(e.data);
+    }
+  });
+
   eventSource.addEventListener("myerror", (e) => {
     onError(e.data);
     eventSource.close();
@@ -118,6 +130,26 @@ export function updateMessage(owner, name, message, isHitOnly = false) {
   }).then(res => res.json());
 }
 
+export function stopMessageAnswer(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/stop-message-answer?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function approveToolCall(owner, name, toolCallId, approved) {
+  return fetch(`${Setting.ServerUrl}/api/approve-tool-call?id=${owner}/${encodeURIComponent(name)}&toolCallId=${encodeURIComponent(toolCallId)}&approved=${approved}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
 export function closeMessageEventSource(owner, name) {
   const key = `${owner}/${name}`;
   if (eventSourceMap.has(key)) {
@@ -141,6 +173,18 @@ export function addMessage(message) {
   }).then(res => res.json());
 }
 
+export function switchMessageBranch(message) {
+  const newMessage = Setting.deepCopy(message);
+  return fetch(`${Setting.ServerUrl}/api/switch-message-branch`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newMessage),
+  }).then(res => res.json());
+}
+
 export function deleteMessage(message) {
   const newMessage = Setting.deepCopy(message);
   return fetch(`${Setting.ServerUrl}/api/delete-message`, {
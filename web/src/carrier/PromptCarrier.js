@@ -0,0 +1,25 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+export class PromptCarrier {
+  constructor() {
+    // The value of a prompt carrier follows its marker like "<<<intent>>>", and ends at the next divider
+    this.valueRegex = /\s*<<<[^<>\n]+>>>[\s\S]*?(?=<<<|=====|\|\|\||$)/g;
+    this.partialMarkerRegex = /\s*<<<[^<>\n]*>?>?$/;
+  }
+
+  parseAnswer = (answer) => {
+    return answer.replace(this.valueRegex, "").replace(this.partialMarkerRegex, "");
+  };
+}
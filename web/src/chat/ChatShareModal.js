@@ -0,0 +1,120 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React, {useState} from "react";
+import {Button, Input, Modal, Select, Space} from "antd";
+import {CopyOutlined} from "@ant-design/icons";
+import copy from "copy-to-clipboard";
+import moment from "moment";
+import i18next from "i18next";
+import * as Setting from "../Setting";
+import * as ChatBackend from "../backend/ChatBackend";
+
+export function getChatShareUrl(shareId) {
+  return `${window.location.origin}/share/${shareId}`;
+}
+
+const ChatShareModal = ({chat, open, onClose, onChatShared}) => {
+  const [expireDays, setExpireDays] = useState(0);
+  const [loading, setLoading] = useState(false);
+
+  if (!chat) {
+    return null;
+  }
+
+  const isShared = chat.shareId !== undefined && chat.shareId !== "";
+
+  const share = () => {
+    const expireTime = expireDays === 0 ? "" : moment().add(expireDays, "days").format();
+    setLoading(true);
+    ChatBackend.shareChat(chat.owner, chat.name, expireTime)
+      .then((res) => {
+        setLoading(false);
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully saved"));
+          onChatShared(res.data);
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        setLoading(false);
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  };
+
+  const unshare = () => {
+    setLoading(true);
+    ChatBackend.unshareChat(chat.owner, chat.name)
+      .then((res) => {
+        setLoading(false);
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully saved"));
+          onChatShared({...chat, shareId: "", shareExpireTime: ""});
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        setLoading(false);
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  };
+
+  return (
+    <Modal
+      title={`${i18next.t("chat:Share chat")}: ${chat.displayName}`}
+      open={open}
+      onCancel={onClose}
+      footer={[
+        isShared && (
+          <Button key="unshare" danger loading={loading} onClick={unshare}>
+            {i18next.t("chat:Stop sharing")}
+          </Button>
+        ),
+        <Button key="share" type="primary" loading={loading} onClick={share}>
+          {isShared ? i18next.t("chat:Update link") : i18next.t("chat:Create link")}
+        </Button>,
+      ]}
+    >
+      <p>{i18next.t("chat:Anyone with the link can view this conversation without signing in")}</p>
+      {isShared && (
+        <Space.Compact style={{width: "100%", marginBottom: "10px"}}>
+          <Input readOnly value={getChatShareUrl(chat.shareId)} />
+          <Button icon={<CopyOutlined />} onClick={() => {
+            copy(getChatShareUrl(chat.shareId));
+            Setting.showMessage("success", i18next.t("general:Copied to clipboard successfully"));
+          }} />
+        </Space.Compact>
+      )}
+      {isShared && (
+        <p style={{color: "#999"}}>
+          {`${i18next.t("chat:Expire time")}: ${chat.shareExpireTime === "" ? i18next.t("chat:Never") : Setting.getFormattedDate(chat.shareExpireTime)}`}
+        </p>
+      )}
+      <div>
+        <span style={{marginRight: "10px"}}>{i18next.t("chat:Expire in")}:</span>
+        <Select value={expireDays} style={{width: "10rem"}} onChange={setExpireDays}
+          options={[
+            {value: 0, label: i18next.t("chat:Never")},
+            {value: 1, label: `1 ${i18next.t("chat:days")}`},
+            {value: 7, label: `7 ${i18next.t("chat:days")}`},
+            {value: 30, label: `30 ${i18next.t("chat:days")}`},
+          ]} />
+      </div>
+    </Modal>
+  );
+};
+
+export default ChatShareModal;
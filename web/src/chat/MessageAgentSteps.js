@@ -0,0 +1,86 @@
+// Copyright 2023 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Collapse, Tag, Typography} from "antd";
+import i18next from "i18next";
+
+const formatStepText = (text) => {
+  if (!text) {
+    return "";
+  }
+
+  try {
+    return JSON.stringify(JSON.parse(text), null, 2);
+  } catch (e) {
+    return text;
+  }
+};
+
+const MessageAgentSteps = ({message}) => {
+  if (message.author !== "AI" || !message.agentSteps || message.agentSteps.length === 0) {
+    return null;
+  }
+
+  const preStyle = {
+    whiteSpace: "pre-wrap",
+    wordBreak: "break-word",
+    maxHeight: "300px",
+    overflow: "auto",
+    margin: 0,
+    fontSize: "12px",
+  };
+
+  const items = message.agentSteps.map((step, index) => {
+    return {
+      key: `${index}`,
+      label: (
+        <div style={{display: "flex", alignItems: "center", gap: "8px", flexWrap: "wrap"}}>
+          <Typography.Text code>{step.toolName}</Typography.Text>
+          {step.error ? (
+            <Tag color="error">{i18next.t("chat:Failed")}</Tag>
+          ) : (
+            <Tag color="success">{i18next.t("chat:Succeeded")}</Tag>
+          )}
+          <Typography.Text type="secondary" style={{fontSize: "12px"}}>{`${step.duration} ms`}</Typography.Text>
+        </div>
+      ),
+      children: (
+        <div style={{display: "flex", flexDirection: "column", gap: "8px"}}>
+          <div>
+            <Typography.Text strong>{i18next.t("chat:Arguments")}:</Typography.Text>
+            <pre style={preStyle}>{formatStepText(step.arguments)}</pre>
+          </div>
+          <div>
+            <Typography.Text strong type={step.error ? "danger" : undefined}>
+              {step.error ? i18next.t("general:Error") : i18next.t("general:Result")}:
+            </Typography.Text>
+            <pre style={preStyle}>{step.error ? step.error : formatStepText(step.result)}</pre>
+          </div>
+        </div>
+      ),
+    };
+  });
+
+  return (
+    <div style={{marginBottom: "12px"}}>
+      <Typography.Text type="secondary" style={{fontSize: "12px"}}>
+        {i18next.t("chat:Tool calls")} ({message.agentSteps.length})
+      </Typography.Text>
+      <Collapse size="small" items={items} style={{marginTop: "4px"}} />
+    </div>
+  );
+};
+
+export default MessageAgentSteps;
@@ -0,0 +1,38 @@
+// Copyright 2023 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button} from "antd";
+import {LeftOutlined, RightOutlined} from "@ant-design/icons";
+
+const MessageBranchSwitcher = ({message, disabled, onSwitchBranch}) => {
+  const siblings = message.siblings;
+  if (!siblings || siblings.length <= 1 || !onSwitchBranch) {
+    return null;
+  }
+
+  const index = siblings.indexOf(message.name);
+
+  return (
+    <div style={{display: "flex", alignItems: "center", gap: "4px", color: "#999", fontSize: "12px"}}>
+      <Button type="text" size="small" icon={<LeftOutlined />} disabled={disabled || index <= 0}
+        onClick={() => onSwitchBranch(message, siblings[index - 1])} />
+      <span>{`${index + 1} / ${siblings.length}`}</span>
+      <Button type="text" size="small" icon={<RightOutlined />} disabled={disabled || index >= siblings.length - 1}
+        onClick={() => onSwitchBranch(message, siblings[index + 1])} />
+    </div>
+  );
+};
+
+export default MessageBranchSwitcher;
@@ -12,18 +12,23 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {PromptCarrier} from "../carrier/PromptCarrier";
 import {SuggestionCarrier} from "../carrier/SuggestionCarrier";
 import {TitleCarrier} from "../carrier/TitleCarrier";
 
 export class MessageCarrier {
   constructor(needTitle) {
+    this.promptCarrier = new PromptCarrier();
     this.suggestionCarrier = new SuggestionCarrier();
     this.titleCarrier = new TitleCarrier(needTitle);
   }
 
   parseAnswerWithCarriers = (answer) => {
-    // First extract title
-    const {parsedAnswer, title} = this.titleCarrier.parseAnswerAndTitle(answer);
+    // First remove the values of the prompt carriers, which are saved on the message by the backend
+    const promptParsedAnswer = this.promptCarrier.parseAnswer(answer);
+
+    // Then extract title
+    const {parsedAnswer, title} = this.titleCarrier.parseAnswerAndTitle(promptParsedAnswer);
 
     // Then extract suggestions
     const {finalAnswer, suggestionArray} = this.suggestionCarrier.parseAnswerAndSuggestions(parsedAnswer);
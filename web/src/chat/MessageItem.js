@@ -24,6 +24,8 @@ import MessageActions from "./MessageActions";
 import MessageSuggestions from "./MessageSuggestions";
 import MessageEdit from "./MessageEdit";
 import {MessageCarrier} from "./MessageCarrier";
+import MessageAgentSteps from "./MessageAgentSteps";
+import MessageBranchSwitcher from "./MessageBranchSwitcher";
 
 const MessageItem = ({
   message,
@@ -36,6 +38,7 @@ const MessageItem = ({
   onLike,
   onToggleRead,
   onEditMessage,
+  onSwitchBranch,
   disableInput,
   isReading,
   isLoadingTTS, // Added new prop for TTS loading state
@@ -223,11 +226,18 @@ const MessageItem = ({
           placement={isUserMessage ? "end" : "start"}
           content={
             <div style={{position: "relative", width: "100%"}}>
+              <MessageAgentSteps message={message} />
               {renderMessageContent()}
             </div>
           }
           footer={
             <div style={{display: "flex", flexDirection: "column", gap: "12px"}}>
+              <MessageBranchSwitcher message={message} disabled={disableInput} onSwitchBranch={onSwitchBranch} />
+              {message.author === "AI" && message.state === "Stopped" && (
+                <div style={{color: "#999", fontSize: "12px"}}>
+                  {i18next.t("chat:Generation stopped")}
+                </div>
+              )}
               {!isEditing && message.author === "AI" && (disableInput === false || index !== isLastMessage) && (
                 <MessageActions
                   message={message}
@@ -251,7 +261,7 @@ const MessageItem = ({
               )}
             </div>
           }
-          loading={message.text === "" && message.author === "AI" && !message.reasonText && !message.errorText}
+          loading={message.text === "" && message.author === "AI" && !message.reasonText && !message.errorText && !message.agentSteps?.length}
           typing={message.author === "AI" && !message.isReasoningPhase ? {
             step: 2,
             interval: 50,
@@ -28,6 +28,7 @@ class MessageList extends React.Component {
       onCopyMessage,
       onToggleRead,
       onEditMessage,
+      onSwitchBranch,
       previewMode,
       hideInput,
       disableInput,
@@ -71,6 +72,7 @@ class MessageList extends React.Component {
             onLike={onMessageLike}
             onToggleRead={onToggleRead}
             onEditMessage={onEditMessage}
+            onSwitchBranch={onSwitchBranch}
             disableInput={disableInput}
             isReading={isReading}
             isLoadingTTS={isLoadingTTS}
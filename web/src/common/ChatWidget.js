@@ -360,12 +360,14 @@ class ChatWidget extends React.Component {
     if (this.state.messages && this.state.messages.length > 0) {
       const lastMessage = this.state.messages[this.state.messages.length - 1];
       if (lastMessage.author === "AI" && this.state.messageLoading) {
-        MessageBackend.closeMessageEventSource(lastMessage.owner, lastMessage.name);
-
-        MessageBackend.updateMessage(lastMessage.owner, lastMessage.name, lastMessage)
+        // The server cancels the generation and saves the partial answer itself
+        MessageBackend.stopMessageAnswer(lastMessage.owner, lastMessage.name)
           .then((res) => {
+            MessageBackend.closeMessageEventSource(lastMessage.owner, lastMessage.name);
             if (res.status === "ok") {
+              lastMessage.state = "Stopped";
               this.setState({
+                messages: [...this.state.messages],
                 messageLoading: false,
               });
             } else {
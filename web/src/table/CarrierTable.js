@@ -0,0 +1,141 @@
+// Copyright 2023 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import {Button, Input, Select, Switch, Table} from "antd";
+import i18next from "i18next";
+import React from "react";
+
+const {Option} = Select;
+
+class CarrierTable extends React.Component {
+  constructor(props) {
+    super(props);
+  }
+
+  updateCarriers(index, field, value) {
+    const newCarriers = this.props.carriers.map((carrier, i) => {
+      if (i === index) {
+        return {
+          ...carrier,
+          [field]: value,
+        };
+      }
+      return carrier;
+    });
+    this.props.onUpdateCarriers(newCarriers);
+  }
+
+  render() {
+    if (!this.props.carriers) {
+      this.props.onUpdateCarriers([]);
+    }
+
+    const carriersColumn = [
+      {
+        title: i18next.t("general:Name"),
+        dataIndex: "name",
+        key: "name",
+        width: "15%",
+        render: (text, record, index) => (
+          <Input value={text} onChange={e => this.updateCarriers(index, "name", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("store:Instruction"),
+        dataIndex: "instruction",
+        key: "instruction",
+        width: "40%",
+        render: (text, record, index) => (
+          <Input.TextArea autoSize={{minRows: 1, maxRows: 5}} value={text} onChange={e => this.updateCarriers(index, "instruction", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("store:Parse type"),
+        dataIndex: "parseType",
+        key: "parseType",
+        width: "12%",
+        render: (text, record, index) => (
+          <Select virtual={false} style={{width: "100%"}} value={text} onChange={value => this.updateCarriers(index, "parseType", value)}>
+            {
+              ["Text", "Regex", "JSON"].map((item, i) => <Option key={i} value={item}>{item}</Option>)
+            }
+          </Select>
+        ),
+      },
+      {
+        title: i18next.t("store:Pattern"),
+        dataIndex: "pattern",
+        key: "pattern",
+        width: "20%",
+        render: (text, record, index) => (
+          <Input value={text} disabled={record.parseType === "Text"}
+            placeholder={record.parseType === "JSON" ? "intent.name" : "(\\d+)%"}
+            onChange={e => this.updateCarriers(index, "pattern", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("general:Is enabled"),
+        dataIndex: "isEnabled",
+        key: "isEnabled",
+        width: "8%",
+        render: (text, record, index) => (
+          <Switch checked={text} onChange={checked => this.updateCarriers(index, "isEnabled", checked)} />
+        ),
+      },
+      {
+        title: i18next.t("general:Action"),
+        key: "action",
+        render: (text, record, index) => (
+          <Button type="primary" size="small" onClick={() => {
+            const carriers = [...this.props.carriers];
+            carriers.splice(index, 1);
+            this.props.onUpdateCarriers(carriers);
+          }}>{i18next.t("general:Delete")}</Button>
+        ),
+      },
+    ];
+
+    return (
+      <div style={{
+        marginTop: "20px",
+      }}>
+        <div style={{
+          flexDirection: "row",
+        }}>
+          <Table rowKey="index" columns={carriersColumn} dataSource={this.props.carriers} size="middle" bordered
+            pagination={false}
+            title={() => (
+              <div>
+                {i18next.t("store:Carriers")}&nbsp;&nbsp;&nbsp;&nbsp;
+                <Button style={{marginRight: "5px"}} type="primary" size="small"
+                  onClick={() => {
+                    const newCarrier = {
+                      name: `carrier_${this.props.carriers.length + 1}`,
+                      instruction: "Classify the intent of the user's question as one of: question, task, chitchat, complaint.",
+                      parseType: "Text",
+                      pattern: "",
+                      isEnabled: true,
+                    };
+                    this.props.onUpdateCarriers([...this.props.carriers, newCarrier]);
+                  }}>{i18next.t("general:Add")}</Button>
+              </div>
+            )}
+          />
+        </div>
+      </div>
+    );
+  }
+}
+
+export default CarrierTable;
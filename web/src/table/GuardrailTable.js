@@ -0,0 +1,190 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import {Button, Input, Select, Switch, Table} from "antd";
+import i18next from "i18next";
+import React from "react";
+
+const {Option} = Select;
+
+class GuardrailTable extends React.Component {
+  constructor(props) {
+    super(props);
+  }
+
+  updateGuardrails(index, field, value) {
+    const newGuardrails = this.props.guardrails.map((guardrail, i) => {
+      if (i === index) {
+        return {
+          ...guardrail,
+          [field]: value,
+        };
+      }
+      return guardrail;
+    });
+    this.props.onUpdateGuardrails(newGuardrails);
+  }
+
+  renderRule(record, index) {
+    if (record.type === "PII") {
+      return (
+        <Select virtual={false} mode="multiple" style={{width: "100%"}} placeholder={i18next.t("store:All PII types")} value={record.piiTypes ?? []} onChange={value => this.updateGuardrails(index, "piiTypes", value)}>
+          {
+            ["API Key", "Email", "ID Number", "Phone"].map((item, i) => <Option key={i} value={item}>{item}</Option>)
+          }
+        </Select>
+      );
+    } else if (record.type === "Blocklist") {
+      return (
+        <div>
+          <Select virtual={false} mode="tags" style={{width: "100%"}} placeholder={i18next.t("store:Keywords")} value={record.keywords ?? []} onChange={value => this.updateGuardrails(index, "keywords", value)} />
+          <Select virtual={false} mode="tags" style={{width: "100%", marginTop: "5px"}} placeholder={i18next.t("store:Regex patterns")} value={record.patterns ?? []} onChange={value => this.updateGuardrails(index, "patterns", value)} />
+        </div>
+      );
+    } else if (record.type === "Moderation") {
+      return (
+        <Select virtual={false} style={{width: "100%"}} placeholder={i18next.t("store:Moderation provider")} value={record.provider} onChange={value => this.updateGuardrails(index, "provider", value)}>
+          {
+            (this.props.modelProviders ?? []).map((provider, i) => <Option key={i} value={provider.name}>{provider.name}</Option>)
+          }
+        </Select>
+      );
+    }
+    return null;
+  }
+
+  render() {
+    if (!this.props.guardrails) {
+      this.props.onUpdateGuardrails([]);
+    }
+
+    const guardrailsColumn = [
+      {
+        title: i18next.t("general:Name"),
+        dataIndex: "name",
+        key: "name",
+        width: "12%",
+        render: (text, record, index) => (
+          <Input value={text} onChange={e => this.updateGuardrails(index, "name", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("general:Type"),
+        dataIndex: "type",
+        key: "type",
+        width: "12%",
+        render: (text, record, index) => (
+          <Select virtual={false} style={{width: "100%"}} value={text} onChange={value => {
+            this.updateGuardrails(index, "type", value);
+            if (value === "PII") {
+              this.updateGuardrails(index, "action", "Mask");
+            }
+          }}>
+            {
+              ["PII", "Blocklist", "Moderation"].map((item, i) => <Option key={i} value={item}>{item}</Option>)
+            }
+          </Select>
+        ),
+      },
+      {
+        title: i18next.t("store:Stage"),
+        dataIndex: "stage",
+        key: "stage",
+        width: "10%",
+        render: (text, record, index) => (
+          <Select virtual={false} style={{width: "100%"}} value={text} onChange={value => this.updateGuardrails(index, "stage", value)}>
+            {
+              ["Input", "Output", "Both"].map((item, i) => <Option key={i} value={item}>{item}</Option>)
+            }
+          </Select>
+        ),
+      },
+      {
+        title: i18next.t("general:Action"),
+        dataIndex: "action",
+        key: "action",
+        width: "10%",
+        render: (text, record, index) => (
+          <Select virtual={false} style={{width: "100%"}} value={text} onChange={value => this.updateGuardrails(index, "action", value)}>
+            {
+              ["Mask", "Block"].map((item, i) => <Option key={i} value={item}>{item}</Option>)
+            }
+          </Select>
+        ),
+      },
+      {
+        title: i18next.t("store:Rule"),
+        dataIndex: "rule",
+        key: "rule",
+        width: "40%",
+        render: (text, record, index) => this.renderRule(record, index),
+      },
+      {
+        title: i18next.t("general:Is enabled"),
+        dataIndex: "isEnabled",
+        key: "isEnabled",
+        width: "8%",
+        render: (text, record, index) => (
+          <Switch checked={text} onChange={checked => this.updateGuardrails(index, "isEnabled", checked)} />
+        ),
+      },
+      {
+        title: i18next.t("general:Delete"),
+        key: "delete",
+        render: (text, record, index) => (
+          <Button type="primary" size="small" onClick={() => {
+            const guardrails = [...this.props.guardrails];
+            guardrails.splice(index, 1);
+            this.props.onUpdateGuardrails(guardrails);
+          }}>{i18next.t("general:Delete")}</Button>
+        ),
+      },
+    ];
+
+    return (
+      <div style={{
+        marginTop: "20px",
+      }}>
+        <div style={{
+          flexDirection: "row",
+        }}>
+          <Table rowKey="index" columns={guardrailsColumn} dataSource={this.props.guardrails} size="middle" bordered
+            pagination={false}
+            title={() => (
+              <div>
+                {i18next.t("store:Guardrails")}&nbsp;&nbsp;&nbsp;&nbsp;
+                <Button style={{marginRight: "5px"}} type="primary" size="small"
+                  onClick={() => {
+                    const newGuardrail = {
+                      name: `guardrail_${this.props.guardrails.length + 1}`,
+                      type: "PII",
+                      stage: "Both",
+                      action: "Mask",
+                      piiTypes: [],
+                      keywords: [],
+                      patterns: [],
+                      provider: "",
+                      isEnabled: true,
+                    };
+                    this.props.onUpdateGuardrails([...this.props.guardrails, newGuardrail]);
+                  }}>{i18next.t("general:Add")}</Button>
+              </div>
+            )}
+          />
+        </div>
+      </div>
+    );
+  }
+}
+
+export default GuardrailTable;
@@ -1,6 +1,7 @@
 import React from "react";
-import {Col, Input, Row, Switch, Table} from "antd";
+import {Col, Input, Row, Select, Switch, Table, Tag, Tooltip} from "antd";
 import i18next from "i18next";
+import * as Setting from "../Setting";
 
 import {Controlled as CodeMirror} from "react-codemirror2";
 import "codemirror/lib/codemirror.css";
@@ -53,13 +54,57 @@ class McpToolsTable extends React.Component {
           );
         },
       },
+      {
+        title: i18next.t("general:Status"),
+        dataIndex: "status",
+        key: "status",
+        width: "120px",
+        render: (text, record, index) => {
+          if (!text) {
+            return null;
+          }
+
+          return (
+            <Tooltip title={`${record.message ? record.message + " " : ""}(${Setting.getFormattedDate(record.checkedTime)})`}>
+              <Tag color={text === "Healthy" ? "success" : "error"}>{text}</Tag>
+            </Tooltip>
+          );
+        },
+      },
+      {
+        title: i18next.t("provider:Tool policies"),
+        dataIndex: "toolPolicies",
+        key: "toolPolicies",
+        width: "300px",
+        render: (text, record, index) => {
+          const tools = JSON.parse(record.tools || "[]");
+          return tools.map(tool => (
+            <Row key={tool.name} style={{marginBottom: "5px"}}>
+              <Col span={14} style={{marginTop: "5px", overflow: "hidden", textOverflow: "ellipsis"}}>
+                {tool.name}
+              </Col>
+              <Col span={10}>
+                <Select virtual={false} style={{width: "100%"}} value={text?.[tool.name] ?? "Auto"} onChange={(value => {
+                  this.updateField(table, index, "toolPolicies", {...text, [tool.name]: value});
+                })}
+                options={[
+                  {value: "Auto", label: i18next.t("provider:Auto")},
+                  {value: "Approval", label: i18next.t("provider:Approval")},
+                  {value: "Deny", label: i18next.t("provider:Deny")},
+                ]}
+                />
+              </Col>
+            </Row>
+          ));
+        },
+      },
       {
         title: i18next.t("provider:Tools"),
         dataIndex: "tools",
         key: "tools",
         width: "800px",
         render: (text, record, index) => {
-          const formattedTools = JSON.stringify(JSON.parse(record.tools), null, 2);
+          const formattedTools = JSON.stringify(JSON.parse(record.tools || "[]"), null, 2);
           return (
             <div style={{height: "490px", overflow: "auto"}}>
               <CodeMirror